@@ -9,11 +9,13 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/database"
 	"job-portal-api/internal/handler"
+	"job-portal-api/internal/mail"
 	"job-portal-api/internal/repository"
 	"job-portal-api/internal/service"
 
@@ -36,33 +38,11 @@ func StartApp() error {
 	cfg := config.GetConfig()
 	log.Info().Msg("main started : initializing the authentication support")
 
-	//reading the private key file
-	decodedPVKBytes, err := base64.StdEncoding.DecodeString(cfg.AuthConfig.PrivateKey)
-	if err != nil {
-		fmt.Println("Error decoding base64:", err)
-		return err
-	}
-
-	decodedPKBytes, err := base64.StdEncoding.DecodeString(cfg.AuthConfig.PublicKey)
+	keys, err := loadKeySet(cfg.AuthConfig)
 	if err != nil {
-		fmt.Println("Error decoding base64:", err)
 		return err
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(decodedPVKBytes))
-	if err != nil {
-		return fmt.Errorf("error in parsing auth private key : %w", err) // %w is used for error wraping
-	}
-
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(decodedPKBytes))
-	if err != nil {
-		return fmt.Errorf("error in parsing auth public key : %w", err) // %w is used for error wraping
-	}
-	a, err := auth.NewAuth(privateKey, publicKey)
-	if err != nil {
-		return fmt.Errorf("error in constructing auth %w", err)
-	}
-
 	// =========================================================================
 	// start the database
 
@@ -87,27 +67,73 @@ func StartApp() error {
 	}
 
 	// =========================================================================
-	//initializing redis
+	// initializing the cache, redis or in memory
 
-	rdb := redis.NewRedisClient(cfg)
+	rdb, err := redis.New(cfg)
+	if err != nil {
+		return err
+	}
 	// initialize the repository layer
 	repo, err := repository.NewRepository(db)
 	if err != nil {
 		return err
 	}
 
-	svc, err := service.NewService(repo, a, rdb)
+	// the repository doubles as the token revocation list
+	a, err := auth.NewAuth(keys, repo)
+	if err != nil {
+		return fmt.Errorf("error in constructing auth %w", err)
+	}
+
+	mailer, err := mail.New(cfg.MailConfig)
+	if err != nil {
+		return err
+	}
+
+	svc, err := service.NewService(repo, a, rdb, cfg)
 	if err != nil {
 		return err
 	}
 
+	// make the users listed in ADMIN_EMAILS admins, nobody could grant the role otherwise
+	if cfg.AuthConfig.AdminEmails != "" {
+		err = svc.BootstrapAdminsService(ctx, strings.Split(cfg.AuthConfig.AdminEmails, ","))
+		if err != nil {
+			return fmt.Errorf("error in granting the admin role : %w", err)
+		}
+	}
+
+	// =========================================================================
+	// deliver queued emails and webhooks in the background
+	dispatcher, err := service.NewOutboxDispatcher(repo, mailer, cfg.OutboxConfig)
+	if err != nil {
+		return err
+	}
+	workers, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go dispatcher.Run(workers)
+
+	// evict the jobs and companies changed on other instances from the cache
+	invalidator, err := service.NewCacheInvalidator(repo, rdb, database.NewListener(cfg))
+	if err != nil {
+		return err
+	}
+	go invalidator.Run(workers)
+
+	// drop the revoked access tokens that have expired anyway
+	pruner, err := service.NewRevocationPruner(repo)
+	if err != nil {
+		return err
+	}
+	go pruner.Run(workers)
+
 	// initializing the http server
 	api := http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.AppConfig.Host, cfg.AppConfig.Port),
 		ReadTimeout:  8000 * time.Second,
 		WriteTimeout: 800 * time.Second,
 		IdleTimeout:  800 * time.Second,
-		Handler:      handler.SetupApi(a, svc),
+		Handler:      handler.SetupApi(a, svc, rdb, rdb, cfg),
 	}
 
 	// channel to store any errors while setting up the service
@@ -142,4 +168,42 @@ func StartApp() error {
 
 }
 
-//hello
+// loadKeySet reads the signing keys from AUTH_KEY_DIR when it is set. Otherwise the single
+// base64 encoded key pair from the environment is used, identified by its thumbprint.
+func loadKeySet(cfg config.AuthConfig) (*auth.KeySet, error) {
+	if cfg.KeyDir != "" {
+		var retired []string
+		if cfg.RetiredKeyIDs != "" {
+			retired = strings.Split(cfg.RetiredKeyIDs, ",")
+		}
+		keys, err := auth.LoadKeySet(cfg.KeyDir, cfg.ActiveKeyID, retired)
+		if err != nil {
+			return nil, fmt.Errorf("error in loading auth keys : %w", err)
+		}
+		return keys, nil
+	}
+
+	//reading the private key file
+	decodedPVKBytes, err := base64.StdEncoding.DecodeString(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error in decoding auth private key : %w", err)
+	}
+
+	decodedPKBytes, err := base64.StdEncoding.DecodeString(cfg.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error in decoding auth public key : %w", err)
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(decodedPVKBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing auth private key : %w", err) // %w is used for error wraping
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(decodedPKBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing auth public key : %w", err) // %w is used for error wraping
+	}
+
+	kid := auth.Thumbprint(publicKey)
+	return auth.NewKeySet(kid, auth.SigningKey{ID: kid, Private: privateKey, Public: publicKey})
+}
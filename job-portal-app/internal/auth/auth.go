@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
@@ -11,23 +11,74 @@ type ctxKey int
 
 const Key ctxKey = 1
 
+// Audiences of the tokens the portal issues. Access tokens are for the api; challenge tokens
+// only prove the password step of a 2FA login and are rejected everywhere else.
+const (
+	AudienceUsers     = "users"
+	AudienceChallenge = "2fa-challenge"
+)
+
+// Claims are the JWT claims issued by the portal. Role carries the user's role so that
+// route-level authorization does not need a database lookup.
+type Claims struct {
+	jwt.RegisteredClaims
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	// EmailVerified is false until the user has confirmed their email address.
+	EmailVerified bool `json:"email_verified"`
+	// Locale is the language the user chose when the token was issued, empty to follow
+	// Accept-Language.
+	Locale string `json:"locale,omitempty"`
+	// The fields below are only set for requests authenticated with an API key and are
+	// never part of a JWT.
+	APIKeyID  uint     `json:"-"`
+	CompanyID uint     `json:"-"`
+	Scopes    []string `json:"-"`
+}
+
+// HasScope reports whether the claims include scope.
+func (c Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyValidator resolves an API key to the claims of the request. It reports an error for
+// unknown, revoked and expired keys.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (Claims, error)
+}
+
+// RevocationList reports whether an otherwise valid token has been revoked, either by its
+// jti or because the session it belongs to was logged out.
+type RevocationList interface {
+	IsTokenRevoked(ctx context.Context, jti string, sessionID string) (bool, error)
+}
+
 type Auth struct {
-	privateKey *rsa.PrivateKey
-	publickey  *rsa.PublicKey
+	keys    *KeySet
+	revoked RevocationList
 }
 
 //go:generate mockgen -source=auth.go -destination=auth_mock.go -package=auth
 type Authentication interface {
-	GenerateAuthToken(claims jwt.RegisteredClaims) (string, error)
-	ValidateToken(token string) (jwt.RegisteredClaims, error)
+	GenerateAuthToken(claims Claims) (string, error)
+	ValidateToken(ctx context.Context, token string) (Claims, error)
+	ValidateChallengeToken(ctx context.Context, token string) (Claims, error)
+	JWKS() JWKS
 }
 
-func (a *Auth) GenerateAuthToken(claims jwt.RegisteredClaims) (string, error) {
+func (a *Auth) GenerateAuthToken(claims Claims) (string, error) {
 	// creates a new token with signing menthod and claims
 	tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
 
-	// signing our token with the private key
-	token, err := tkn.SignedString(a.privateKey)
+	// signing our token with the active key, the kid tells validators which key to use
+	key := a.keys.signingKey()
+	tkn.Header["kid"] = key.ID
+	token, err := tkn.SignedString(key.Private)
 	if err != nil {
 		return "", fmt.Errorf("error in signing the token : %w", err)
 	}
@@ -35,29 +86,58 @@ func (a *Auth) GenerateAuthToken(claims jwt.RegisteredClaims) (string, error) {
 	return token, nil
 }
 
-func (a *Auth) ValidateToken(token string) (jwt.RegisteredClaims, error) {
-	// Parse the token with the registered claims.
-	var c jwt.RegisteredClaims
+// ValidateToken validates an access token.
+func (a *Auth) ValidateToken(ctx context.Context, token string) (Claims, error) {
+	return a.validate(ctx, token, AudienceUsers)
+}
+
+// ValidateChallengeToken validates the token issued after the password step of a 2FA login.
+func (a *Auth) ValidateChallengeToken(ctx context.Context, token string) (Claims, error) {
+	return a.validate(ctx, token, AudienceChallenge)
+}
+
+func (a *Auth) validate(ctx context.Context, token string, audience string) (Claims, error) {
+	// Parse the token with the portal claims.
+	var c Claims
 	tkn, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
-		return a.publickey, nil
-	})
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.verificationKey(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithAudience(audience))
 	if err != nil {
-		return jwt.RegisteredClaims{}, fmt.Errorf("error in parsing the token : %w", err)
+		return Claims{}, fmt.Errorf("error in parsing the token : %w", err)
 	}
 
 	// checking if the token is valid or not
 	if !tkn.Valid {
-		return jwt.RegisteredClaims{}, errors.New("token in not valid")
+		return Claims{}, errors.New("token in not valid")
+	}
+
+	// checking the revocation list
+	if a.revoked != nil {
+		revoked, err := a.revoked.IsTokenRevoked(ctx, c.ID, c.SessionID)
+		if err != nil {
+			return Claims{}, fmt.Errorf("error in checking the revocation list : %w", err)
+		}
+		if revoked {
+			return Claims{}, errors.New("token has been revoked")
+		}
 	}
 	return c, nil
 }
 
-func NewAuth(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) (Authentication, error) {
-	if privateKey == nil && publicKey == nil {
-		return nil, errors.New("publickey and privatekey cannot be null")
+// JWKS returns the public keys that tokens may currently be signed with.
+func (a *Auth) JWKS() JWKS {
+	return a.keys.JWKS()
+}
+
+// NewAuth creates the token issuer and validator. revoked may be nil, in which case tokens
+// are valid until they expire.
+func NewAuth(keys *KeySet, revoked RevocationList) (Authentication, error) {
+	if keys == nil {
+		return nil, errors.New("keyset cannot be null")
 	}
 	return &Auth{
-		privateKey: privateKey,
-		publickey:  publicKey,
+		keys:    keys,
+		revoked: revoked,
 	}, nil
 }
@@ -9,12 +9,50 @@
 package auth
 
 import (
+	context "context"
 	reflect "reflect"
 
-	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockRevocationList is a mock of RevocationList interface.
+type MockRevocationList struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationListMockRecorder
+}
+
+// MockRevocationListMockRecorder is the mock recorder for MockRevocationList.
+type MockRevocationListMockRecorder struct {
+	mock *MockRevocationList
+}
+
+// NewMockRevocationList creates a new mock instance.
+func NewMockRevocationList(ctrl *gomock.Controller) *MockRevocationList {
+	mock := &MockRevocationList{ctrl: ctrl}
+	mock.recorder = &MockRevocationListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationList) EXPECT() *MockRevocationListMockRecorder {
+	return m.recorder
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockRevocationList) IsTokenRevoked(ctx context.Context, jti, sessionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockRevocationListMockRecorder) IsTokenRevoked(ctx, jti, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockRevocationList)(nil).IsTokenRevoked), ctx, jti, sessionID)
+}
+
 // MockAuthentication is a mock of Authentication interface.
 type MockAuthentication struct {
 	ctrl     *gomock.Controller
@@ -39,7 +77,7 @@ func (m *MockAuthentication) EXPECT() *MockAuthenticationMockRecorder {
 }
 
 // GenerateAuthToken mocks base method.
-func (m *MockAuthentication) GenerateAuthToken(claims jwt.RegisteredClaims) (string, error) {
+func (m *MockAuthentication) GenerateAuthToken(claims Claims) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateAuthToken", claims)
 	ret0, _ := ret[0].(string)
@@ -53,17 +91,46 @@ func (mr *MockAuthenticationMockRecorder) GenerateAuthToken(claims any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAuthToken", reflect.TypeOf((*MockAuthentication)(nil).GenerateAuthToken), claims)
 }
 
+// JWKS mocks base method.
+func (m *MockAuthentication) JWKS() JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(JWKS)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthenticationMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthentication)(nil).JWKS))
+}
+
+// ValidateChallengeToken mocks base method.
+func (m *MockAuthentication) ValidateChallengeToken(ctx context.Context, token string) (Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateChallengeToken", ctx, token)
+	ret0, _ := ret[0].(Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateChallengeToken indicates an expected call of ValidateChallengeToken.
+func (mr *MockAuthenticationMockRecorder) ValidateChallengeToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChallengeToken", reflect.TypeOf((*MockAuthentication)(nil).ValidateChallengeToken), ctx, token)
+}
+
 // ValidateToken mocks base method.
-func (m *MockAuthentication) ValidateToken(token string) (jwt.RegisteredClaims, error) {
+func (m *MockAuthentication) ValidateToken(ctx context.Context, token string) (Claims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateToken", token)
-	ret0, _ := ret[0].(jwt.RegisteredClaims)
+	ret := m.ctrl.Call(m, "ValidateToken", ctx, token)
+	ret0, _ := ret[0].(Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ValidateToken indicates an expected call of ValidateToken.
-func (mr *MockAuthenticationMockRecorder) ValidateToken(token any) *gomock.Call {
+func (mr *MockAuthenticationMockRecorder) ValidateToken(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthentication)(nil).ValidateToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthentication)(nil).ValidateToken), ctx, token)
 }
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one RSA key of the keyset. Keys without a private half can only verify tokens,
+// which is how the previous key is kept around for a while after a rotation.
+type SigningKey struct {
+	ID      string
+	Private *rsa.PrivateKey
+	Public  *rsa.PublicKey
+	Retired bool
+}
+
+// KeySet holds every signing key the service knows about. Tokens are signed with the
+// active key and validated against any key that is not retired.
+type KeySet struct {
+	keys   map[string]SigningKey
+	active string
+}
+
+// JWK is the public half of a key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet builds a keyset from keys and marks active as the signing key.
+func NewKeySet(active string, keys ...SigningKey) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]SigningKey, len(keys)), active: active}
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, errors.New("key id cannot be empty")
+		}
+		if k.Public == nil && k.Private != nil {
+			k.Public = &k.Private.PublicKey
+		}
+		if k.Public == nil {
+			return nil, fmt.Errorf("key %q has no public key", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+	activeKey, ok := ks.keys[active]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", active)
+	}
+	if activeKey.Private == nil || activeKey.Retired {
+		return nil, fmt.Errorf("active key %q must be a private key that is not retired", active)
+	}
+	return ks, nil
+}
+
+// LoadKeySet reads every *.pem file in dir. The file name without the extension is the key
+// ID. Files may hold a private key, or only a public key for verification. active names the
+// signing key; when it is empty the private key with the highest ID is used, so naming
+// keys by date makes the newest one active. Keys listed in retired are not trusted anymore.
+func LoadKeySet(dir string, active string, retired []string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("listing keys in %s : %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	isRetired := make(map[string]bool, len(retired))
+	for _, kid := range retired {
+		isRetired[strings.TrimSpace(kid)] = true
+	}
+
+	var keys []SigningKey
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key %s : %w", path, err)
+		}
+		key := SigningKey{ID: kid, Retired: isRetired[kid]}
+		if strings.Contains(string(data), "PRIVATE KEY") {
+			key.Private, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+		} else {
+			key.Public, err = jwt.ParseRSAPublicKeyFromPEM(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing key %s : %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if active == "" {
+		// paths are sorted, so the last private key wins
+		for i := len(keys) - 1; i >= 0; i-- {
+			if keys[i].Private != nil && !keys[i].Retired {
+				active = keys[i].ID
+				break
+			}
+		}
+	}
+	return NewKeySet(active, keys...)
+}
+
+// Thumbprint returns the RFC 7638 JWK thumbprint of the key. It is used as the key ID when
+// a single key is configured without one.
+func Thumbprint(pub *rsa.PublicKey) string {
+	jwk := toJWK("", pub)
+	// members must be in lexicographic order with no whitespace
+	canonical := fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, jwk.E, jwk.Kty, jwk.N)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (ks *KeySet) signingKey() SigningKey {
+	return ks.keys[ks.active]
+}
+
+// verificationKey returns the public key for kid. Tokens issued before kid headers were
+// added carry none and are checked against the active key.
+func (ks *KeySet) verificationKey(kid string) (*rsa.PublicKey, error) {
+	if kid == "" {
+		kid = ks.active
+	}
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if key.Retired {
+		return nil, fmt.Errorf("signing key %q is retired", kid)
+	}
+	return key.Public, nil
+}
+
+// JWKS returns the public keys of every key that is not retired.
+func (ks *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(ks.keys))
+	for kid, key := range ks.keys {
+		if !key.Retired {
+			ids = append(ids, kid)
+		}
+	}
+	sort.Strings(ids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, kid := range ids {
+		set.Keys = append(set.Keys, toJWK(kid, ks.keys[kid].Public))
+	}
+	return set
+}
+
+func toJWK(kid string, pub *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+// PublicKey decodes an RSA key published by another issuer, such as an OpenID provider.
+func (k JWK) PublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decoding modulus of key %q: %w", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decoding exponent of key %q: %w", k.Kid, err)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent in key %q", k.Kid)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
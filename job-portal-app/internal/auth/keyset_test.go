@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testKeys are shared by the tests, generating RSA keys is slow.
+var testKeys = func() []*rsa.PrivateKey {
+	keys := make([]*rsa.PrivateKey, 3)
+	for i := range keys {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+		keys[i] = key
+	}
+	return keys
+}()
+
+func writePrivateKey(t *testing.T, dir, kid string, key *rsa.PrivateKey) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writePublicKey(t *testing.T, dir, kid string, key *rsa.PublicKey) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	err = os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string)
+		active     string
+		retired    []string
+		wantActive string
+		wantErr    bool
+	}{
+		{
+			name:    "no keys",
+			setup:   func(t *testing.T, dir string) {},
+			wantErr: true,
+		},
+		{
+			name: "invalid key",
+			setup: func(t *testing.T, dir string) {
+				err := os.WriteFile(filepath.Join(dir, "2024-01.pem"), []byte("not a key"), 0o600)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "newest private key is active",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "2024-01", testKeys[0])
+				writePrivateKey(t, dir, "2024-02", testKeys[1])
+			},
+			wantActive: "2024-02",
+		},
+		{
+			name: "public keys are never active",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "2024-01", testKeys[0])
+				writePublicKey(t, dir, "2024-02", &testKeys[1].PublicKey)
+			},
+			wantActive: "2024-01",
+		},
+		{
+			name: "retired keys are never active",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "2024-01", testKeys[0])
+				writePrivateKey(t, dir, "2024-02", testKeys[1])
+			},
+			retired:    []string{" 2024-02"},
+			wantActive: "2024-01",
+		},
+		{
+			name: "active key chosen",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "2024-01", testKeys[0])
+				writePrivateKey(t, dir, "2024-02", testKeys[1])
+			},
+			active:     "2024-01",
+			wantActive: "2024-01",
+		},
+		{
+			name: "active key is retired",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "2024-01", testKeys[0])
+			},
+			active:  "2024-01",
+			retired: []string{"2024-01"},
+			wantErr: true,
+		},
+		{
+			name: "active key is unknown",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "2024-01", testKeys[0])
+			},
+			active:  "2023-12",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(t, dir)
+			got, err := LoadKeySet(dir, tt.active, tt.retired)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadKeySet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.active != tt.wantActive {
+				t.Errorf("LoadKeySet() active = %v, want %v", got.active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestKeySet_verificationKey(t *testing.T) {
+	ks, err := NewKeySet("new",
+		SigningKey{ID: "new", Private: testKeys[0]},
+		SigningKey{ID: "previous", Public: &testKeys[1].PublicKey},
+		SigningKey{ID: "compromised", Public: &testKeys[2].PublicKey, Retired: true},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		kid     string
+		want    *rsa.PublicKey
+		wantErr bool
+	}{
+		{name: "tokens without kid use the active key", kid: "", want: &testKeys[0].PublicKey},
+		{name: "active key", kid: "new", want: &testKeys[0].PublicKey},
+		{name: "previous key", kid: "previous", want: &testKeys[1].PublicKey},
+		{name: "retired key", kid: "compromised", wantErr: true},
+		{name: "unknown key", kid: "other", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ks.verificationKey(tt.kid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verificationKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("verificationKey() returned another key")
+			}
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	ks, err := NewKeySet("b",
+		SigningKey{ID: "b", Private: testKeys[0]},
+		SigningKey{ID: "a", Public: &testKeys[1].PublicKey},
+		SigningKey{ID: "c", Public: &testKeys[2].PublicKey, Retired: true},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+	got := ks.JWKS()
+
+	var kids []string
+	for _, k := range got.Keys {
+		kids = append(kids, k.Kid)
+		if k.Kty != "RSA" || k.Use != "sig" || k.Alg != "RS256" {
+			t.Errorf("JWKS() key %s = %+v, want an RS256 signing key", k.Kid, k)
+		}
+	}
+	// sorted, and without the retired key
+	if want := []string{"a", "b"}; !reflect.DeepEqual(kids, want) {
+		t.Fatalf("JWKS() kids = %v, want %v", kids, want)
+	}
+	for i, want := range []*rsa.PublicKey{&testKeys[1].PublicKey, &testKeys[0].PublicKey} {
+		pub, err := got.Keys[i].PublicKey()
+		if err != nil {
+			t.Fatalf("PublicKey() error = %v", err)
+		}
+		if !pub.Equal(want) {
+			t.Errorf("JWKS() key %s does not decode to its public key", got.Keys[i].Kid)
+		}
+	}
+}
+
+func TestAuth_keyRotation(t *testing.T) {
+	claims := Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "1",
+		Audience:  jwt.ClaimStrings{AudienceUsers},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}}
+	before, _ := NewKeySet("old", SigningKey{ID: "old", Private: testKeys[0]})
+	a, _ := NewAuth(before, nil)
+	token, err := a.GenerateAuthToken(claims)
+	if err != nil {
+		t.Fatalf("GenerateAuthToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		old     SigningKey
+		wantErr bool
+	}{
+		{name: "previous key still trusted", old: SigningKey{ID: "old", Public: &testKeys[0].PublicKey}},
+		{name: "previous key retired", old: SigningKey{ID: "old", Public: &testKeys[0].PublicKey, Retired: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after, err := NewKeySet("new", SigningKey{ID: "new", Private: testKeys[1]}, tt.old)
+			if err != nil {
+				t.Fatalf("NewKeySet() error = %v", err)
+			}
+			a, _ := NewAuth(after, nil)
+			_, err = a.ValidateToken(context.Background(), token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
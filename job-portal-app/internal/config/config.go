@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	env "github.com/Netflix/go-env"
 )
@@ -9,32 +10,135 @@ import (
 var cfg Config
 
 type Config struct {
-	AppConfig   AppConfig
-	DBConfig    DBConfig
-	RedisConfig RedisConfig
-	AuthConfig  AuthConfig
-	MailConfig  MailConfig
+	AppConfig      AppConfig
+	DBConfig       DBConfig
+	RedisConfig    RedisConfig
+	AuthConfig     AuthConfig
+	PasswordConfig PasswordConfig
+	OIDCConfig     OIDCConfig
+	MailConfig     MailConfig
+	OutboxConfig   OutboxConfig
 }
 
 type AppConfig struct {
 	Host string `env:"APP_HOST"`
 	Port string `env:"APP_PORT,required=true"`
+	// BaseURL is the public address of the api, used to build links sent in emails.
+	BaseURL string `env:"APP_BASE_URL"`
+	// TrustedProxies is a comma separated list of the IPs or CIDRs of the proxies in front of
+	// the api. Only they may set X-Forwarded-For; by default no proxy is trusted and the client
+	// IP is the address of the connection.
+	TrustedProxies string `env:"APP_TRUSTED_PROXIES"`
 }
 
 type RedisConfig struct {
+	// Driver is redis, or memory to keep the cache in the process. The memory cache is meant
+	// for development and is not shared between replicas.
+	Driver   string `env:"CACHE_DRIVER,default=redis"`
 	Host     string `env:"REDIS_HOST"`
 	Port     string `env:"REDIS_PORT"`
 	Password string `env:"REDIS_PASSWORD"`
 	DB       int    `env:"REDIS_DB"`
+	// JobTTL and CompanyTTL are how long a job or company is cached after it is read. 0 turns
+	// caching of that kind off.
+	JobTTL     time.Duration `env:"CACHE_JOB_TTL,default=10m"`
+	CompanyTTL time.Duration `env:"CACHE_COMPANY_TTL,default=30m"`
+	// LocalTTL is how long each instance also keeps a job or company in its own memory. 0
+	// turns the memory tier off.
+	LocalTTL time.Duration `env:"CACHE_LOCAL_TTL,default=30s"`
+	// Timeout bounds every call to Redis, so an unreachable server fails fast.
+	Timeout time.Duration `env:"REDIS_TIMEOUT,default=500ms"`
+	// After BreakerThreshold failures in a row the cache is bypassed for BreakerCooldown,
+	// then probed again.
+	BreakerThreshold int           `env:"CACHE_BREAKER_THRESHOLD,default=5"`
+	BreakerCooldown  time.Duration `env:"CACHE_BREAKER_COOLDOWN,default=30s"`
 }
 
 type AuthConfig struct {
 	PublicKey  string `env:"PUBLIC_KEY"`
 	PrivateKey string `env:"PRIVATE_KEY"`
+	// KeyDir holds one <kid>.pem file per signing key and takes precedence over the keys above.
+	KeyDir        string `env:"AUTH_KEY_DIR"`
+	ActiveKeyID   string `env:"AUTH_ACTIVE_KID"`
+	RetiredKeyIDs string `env:"AUTH_RETIRED_KIDS"`
+	// EmailVerification decides what an unverified account cannot do, see the
+	// EmailVerification* constants. Verification links are signed with EmailVerificationSecret.
+	EmailVerification       string `env:"EMAIL_VERIFICATION"`
+	EmailVerificationSecret string `env:"EMAIL_VERIFICATION_SECRET"`
+	// AdminEmails is a comma separated list of registered users made admins at startup, so
+	// that a new deployment has someone who can grant roles.
+	AdminEmails string `env:"ADMIN_EMAILS"`
 }
 
+// Values of AuthConfig.EmailVerification.
+const (
+	// EmailVerificationOff lets unverified accounts do everything.
+	EmailVerificationOff = ""
+	// EmailVerificationLogin stops unverified accounts from logging in.
+	EmailVerificationLogin = "login"
+	// EmailVerificationPrivileged lets unverified accounts log in but not use role restricted routes.
+	EmailVerificationPrivileged = "privileged"
+)
+
+// PasswordConfig is the policy new passwords are checked against. Every password is also
+// screened against the breached-password list shipped with the service; BreachedListFile
+// adds a local file with one password per line to it.
+type PasswordConfig struct {
+	MinLength        int    `env:"PASSWORD_MIN_LENGTH,default=10"`
+	RequireLower     bool   `env:"PASSWORD_REQUIRE_LOWER,default=true"`
+	RequireUpper     bool   `env:"PASSWORD_REQUIRE_UPPER,default=false"`
+	RequireDigit     bool   `env:"PASSWORD_REQUIRE_DIGIT,default=true"`
+	RequireSymbol    bool   `env:"PASSWORD_REQUIRE_SYMBOL,default=false"`
+	BreachedListFile string `env:"PASSWORD_BREACHED_LIST_FILE"`
+}
+
+// OIDCConfig lists the OpenID Connect providers users can sign in with. Providers is a JSON
+// array of OIDCProvider, e.g.
+//
+//	[{"name":"acme","issuer":"https://login.acme.com","client_id":"portal","client_secret":"..."}]
+type OIDCConfig struct {
+	Providers string `env:"OIDC_PROVIDERS"`
+}
+
+// OIDCProvider is one OpenID Connect provider. RedirectURL defaults to
+// <APP_BASE_URL>/api/oidc/<name>/callback and Scopes to openid, email and profile.
+type OIDCProvider struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+}
+
+// MailConfig selects how emails are sent. Driver is smtp, file or log: file writes every
+// message to Dir and log only logs it, both are meant for development. TLS is starttls,
+// tls (implicit TLS, usually port 465) or none.
 type MailConfig struct {
-	Port int `env:"MAIL_PORT"`
+	Driver   string `env:"MAIL_DRIVER,default=log"`
+	Host     string `env:"MAIL_HOST"`
+	Port     int    `env:"MAIL_PORT,default=587"`
+	Username string `env:"MAIL_USERNAME"`
+	Password string `env:"MAIL_PASSWORD"`
+	TLS      string `env:"MAIL_TLS,default=starttls"`
+	From     string `env:"MAIL_FROM"`
+	Dir      string `env:"MAIL_DIR"`
+}
+
+// OutboxConfig tunes the delivery of queued emails and webhooks. A failed delivery is retried
+// after BackoffBase, doubling up to BackoffMax, until MaxAttempts is reached and it is
+// dead-lettered.
+type OutboxConfig struct {
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL,default=5s"`
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE,default=20"`
+	MaxAttempts  int           `env:"OUTBOX_MAX_ATTEMPTS,default=10"`
+	BackoffBase  time.Duration `env:"OUTBOX_BACKOFF_BASE,default=30s"`
+	BackoffMax   time.Duration `env:"OUTBOX_BACKOFF_MAX,default=6h"`
+	// Retention is how long delivered messages are kept for admins to look at. 0 keeps them.
+	Retention time.Duration `env:"OUTBOX_RETENTION,default=168h"`
+	// AllowPrivateWebhookTargets lets webhooks reach loopback and private network addresses,
+	// which are refused by default so an endpoint cannot be used to probe the internal network.
+	AllowPrivateWebhookTargets bool `env:"WEBHOOK_ALLOW_PRIVATE_TARGETS,default=false"`
 }
 
 type DBConfig struct {
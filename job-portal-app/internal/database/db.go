@@ -8,30 +8,69 @@ import (
 	"job-portal-api/internal/models"
 )
 
-func ConnectToDatabase(cfg config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+func dsn(cfg config.Config) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
 		cfg.DBConfig.Host, cfg.DBConfig.UserName, cfg.DBConfig.Password, cfg.DBConfig.DbName, cfg.DBConfig.Port, cfg.DBConfig.SslMode, cfg.DBConfig.TimeZone)
+}
+
+func ConnectToDatabase(cfg config.Config) (*gorm.DB, error) {
 	//dsn := "host=postgres user=postgres password=admin dbname=postgres port=5432 sslmode=disable TimeZone=Asia/Shanghai"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn(cfg)), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
 
 	// AutoMigrate function will ONLY create tables, missing columns and missing indexes, and WON'T change existing column's type or delete unused columns
-	err = db.Migrator().AutoMigrate(&models.User{})
+	err = db.Migrator().AutoMigrate(
+		&models.User{},
+		&models.Company{},
+		&models.Jobs{},
+		&models.CompanyMember{},
+		&models.Session{}, &models.RefreshToken{}, &models.RevokedToken{},
+		&models.RecoveryCode{},
+		&models.APIKey{},
+		&models.LockoutEvent{},
+		&models.ExternalIdentity{}, &models.OIDCLoginState{},
+		&models.AuditEvent{},
+		&models.OutboxMessage{},
+		&models.WebhookEndpoint{}, &models.WebhookDelivery{},
+		&models.Notification{}, &models.JobPreference{},
+		&models.CacheInvalidation{},
+	)
 	if err != nil {
 		// If there is an error while migrating, log the error message and stop the program
 		return nil, err
 	}
-	err = db.Migrator().AutoMigrate(&models.Company{})
+	// The audit log is append-only, the database itself rejects changes to it
+	err = db.Exec(auditLogAppendOnly).Error
 	if err != nil {
-		// If there is an error while migrating, log the error message and stop the program
 		return nil, err
 	}
-	err = db.Migrator().AutoMigrate(&models.Jobs{})
+	// Emails are unique whatever their case. Accounts whose emails only differ in case have
+	// to be merged by hand before this succeeds.
+	err = db.Exec(emailsIgnoreCase).Error
 	if err != nil {
-		// If there is an error while migrating, log the error message and stop the program
-		return nil, err
+		return nil, fmt.Errorf("making emails case insensitive: %w", err)
 	}
 	return db, nil
 }
+
+const emailsIgnoreCase = `
+UPDATE users SET email = lower(email)
+	WHERE email <> lower(email)
+	AND NOT EXISTS (SELECT 1 FROM users other WHERE other.id <> users.id AND lower(other.email) = lower(users.email));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
+`
+
+const auditLogAppendOnly = `
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+`
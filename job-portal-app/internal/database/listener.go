@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/config"
+)
+
+const (
+	// listenerPingInterval is how long the listener waits for a notification before it
+	// checks that the connection is still up. A connection that died without being closed
+	// would otherwise wait forever.
+	listenerPingInterval = 30 * time.Second
+	listenerMinBackoff   = time.Second
+	listenerMaxBackoff   = time.Minute
+)
+
+// Listener receives Postgres notifications on a connection of its own, outside the pool
+// gorm uses, since a LISTEN only lasts as long as the connection it was run on.
+type Listener struct {
+	dsn string
+}
+
+func NewListener(cfg config.Config) *Listener {
+	return &Listener{dsn: dsn(cfg)}
+}
+
+// Listen calls notify with the payload of every notification sent on channel until ctx is
+// done. When the connection drops it reconnects with an exponential backoff. connected is
+// called every time the connection is up, before any notification received on it, with
+// the time from which notifications may have been missed; it is zero on the first
+// connection.
+func (l *Listener) Listen(ctx context.Context, channel string, connected func(ctx context.Context, missedSince time.Time), notify func(ctx context.Context, payload string)) error {
+	var lastAlive time.Time
+	backoff := listenerMinBackoff
+	for {
+		up, err := l.listen(ctx, channel, &lastAlive, connected, notify)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if up {
+			// only failed connection attempts back off further
+			backoff = listenerMinBackoff
+		}
+		log.Warn().Err(err).Str("channel", channel).Dur("retry in", backoff).Msg("notification listener disconnected")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > listenerMaxBackoff {
+			backoff = listenerMaxBackoff
+		}
+	}
+}
+
+// listen runs one connection until it fails, and reports whether it got to listen on it.
+// lastAlive is the last time the connection was known to be up.
+func (l *Listener) listen(ctx context.Context, channel string, lastAlive *time.Time, connected func(ctx context.Context, missedSince time.Time), notify func(ctx context.Context, payload string)) (bool, error) {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+	if err != nil {
+		return false, err
+	}
+	// anything sent from now on is delivered on this connection
+	missedSince := *lastAlive
+	*lastAlive = time.Now()
+	log.Info().Str("channel", channel).Msg("notification listener connected")
+	connected(ctx, missedSince)
+
+	for {
+		wait, cancel := context.WithTimeout(ctx, listenerPingInterval)
+		n, err := conn.WaitForNotification(wait)
+		cancel()
+		switch {
+		case err == nil:
+			*lastAlive = time.Now()
+			notify(ctx, n.Payload)
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			err = conn.Ping(ctx)
+			if err != nil {
+				return true, err
+			}
+			*lastAlive = time.Now()
+		default:
+			return true, err
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+)
+
+// GetMe returns the profile of the logged in user.
+func (h *handler) GetMe(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.GetProfileService(ctx, actor.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": tr(c, "user not found")})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateMe changes the username and email of the logged in user.
+func (h *handler) UpdateMe(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	var profileData models.UpdateProfileRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&profileData)
+	if err == nil {
+		err = validator.New().Struct(profileData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide a valid username or email")})
+		return
+	}
+
+	user, err := h.service.UpdateProfileService(ctx, actor.UserID, profileData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(accountErrorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// ExportMe sends everything held about the logged in user as a JSON file.
+func (h *handler) ExportMe(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	export, err := h.service.ExportAccountService(ctx, actor.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": tr(c, "could not export the account")})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%d-export.json"`, actor.UserID))
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, export)
+}
+
+// DeleteMe deletes the account of the logged in user.
+func (h *handler) DeleteMe(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	var deleteData models.DeleteAccountRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&deleteData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide your password")})
+		return
+	}
+
+	err = h.service.DeleteAccountService(ctx, actor.UserID, deleteData.Password)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(accountErrorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "account deleted")})
+}
+
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrUsernameTaken), errors.Is(err, service.ErrEmailTaken), errors.Is(err, service.ErrLastCompanyOwner):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrEmailChangeUnavailable):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_DeleteMe(t *testing.T) {
+	claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}}
+	tests := []struct {
+		name               string
+		claims             interface{}
+		body               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "missing jwt claims",
+			body:               `{"password":"validpassword"}`,
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:               "invalid body",
+			claims:             claims,
+			body:               `not json`,
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide your password"}`,
+		},
+		{
+			name:   "wrong password",
+			claims: claims,
+			body:   `{"password":"wrongpassword"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().DeleteAccountService(gomock.Any(), uint(1), "wrongpassword").Return(service.ErrInvalidPassword).Times(1)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"invalid password"}`,
+		},
+		{
+			name:   "last owner of a company",
+			claims: claims,
+			body:   `{"password":"validpassword"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().DeleteAccountService(gomock.Any(), uint(1), "validpassword").Return(service.ErrLastCompanyOwner).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"you are the last owner of a company, hand it over to another member first"}`,
+		},
+		{
+			name:   "success",
+			claims: claims,
+			body:   `{"password":"validpassword"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().DeleteAccountService(gomock.Any(), uint(1), "validpassword").Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"message":"account deleted"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodDelete, "http://test.com/api/me", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			if tt.claims != nil {
+				ctx = context.WithValue(ctx, auth.Key, tt.claims)
+			}
+			c.Request = httpRequest.WithContext(ctx)
+
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.DeleteMe(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
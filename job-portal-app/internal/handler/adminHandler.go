@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+)
+
+// GrantUserRole sets the role of the user in the URL. Admins cannot change their own role,
+// so the last admin cannot lock everyone out by accident. The user is logged out of every
+// session, since their tokens carry the old role, and gets the new one when they log in.
+func (h *handler) GrantUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	id := c.Param("userID")
+
+	uid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+	if id == claims.Subject {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "admins cannot change their own role")})
+		return
+	}
+
+	var roleData models.UpdateRoleRequest
+
+	err = json.NewDecoder(c.Request.Body).Decode(&roleData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a valid role"),
+		})
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(roleData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a valid role"),
+		})
+		return
+	}
+
+	user, err := h.service.GrantRoleService(ctx, uid, roleData.Role)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RevokeUserRole drops the user in the URL back to the candidate role and logs them out of
+// every session.
+func (h *handler) RevokeUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	id := c.Param("userID")
+
+	uid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+	if id == claims.Subject {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "admins cannot change their own role")})
+		return
+	}
+
+	user, err := h.service.RevokeRoleService(ctx, uid)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ListLockouts returns the most recent brute-force lockouts. The optional limit query
+// parameter caps how many are returned.
+func (h *handler) ListLockouts(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+			return
+		}
+	}
+
+	events, err := h.service.ListLockoutEventsService(ctx, limit)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// ListAuditEvents searches the audit log, most recent first. It can be filtered by the
+// user_id the event was done by or to, the event_type and a from/to time range in RFC 3339.
+func (h *handler) ListAuditEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	query, err := parseAuditQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, err.Error())})
+		return
+	}
+
+	events, err := h.service.ListAuditEventsService(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func parseAuditQuery(c *gin.Context) (models.AuditQuery, error) {
+	var query models.AuditQuery
+	if id := c.Query("user_id"); id != "" {
+		uid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return models.AuditQuery{}, errors.New("user_id must be a number")
+		}
+		query.UserID = uint(uid)
+	}
+	query.EventType = c.Query("event_type")
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &query.From}, {"to", &query.To}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return models.AuditQuery{}, errors.New(p.name + " must be an RFC 3339 time")
+		}
+		*p.dst = t
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return models.AuditQuery{}, errors.New("to must not be before from")
+	}
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil {
+			return models.AuditQuery{}, errors.New("limit must be a number")
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// ListOutboxMessages returns queued emails and webhooks in the status given by the query,
+// the failed (dead) ones by default. The optional limit query parameter caps how many.
+func (h *handler) ListOutboxMessages(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+			return
+		}
+	}
+
+	messages, err := h.service.ListOutboxMessagesService(ctx, c.Query("status"), limit)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// ReplayOutboxMessage queues a failed delivery again.
+func (h *handler) ReplayOutboxMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("messageID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	err = h.service.ReplayOutboxMessageService(ctx, uint(id))
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNoDeadOutboxMessage) {
+			status = http.StatusNotFound
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "delivery queued again")})
+}
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_handler_GrantUserRole(t *testing.T) {
+	adminClaims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "1"},
+		Role:             models.RoleAdmin,
+	}
+	tests := []struct {
+		name               string
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, service.UserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "missing jwt claims",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name: "admin changing own role",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com", bytes.NewBufferString(`{"role":"candidate"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, adminClaims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "1"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"admins cannot change their own role"}`,
+		},
+		{
+			name: "missing role",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com", bytes.NewBufferString(`{}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, adminClaims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "2"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide a valid role"}`,
+		},
+		{
+			name: "error from service",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com", bytes.NewBufferString(`{"role":"superuser"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, adminClaims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "2"})
+
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+				ms.EXPECT().GrantRoleService(gomock.Any(), uint64(2), "superuser").Return(models.User{}, errors.New(`unknown role "superuser"`)).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"unknown role \"superuser\""}`,
+		},
+		{
+			name: "success",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com", bytes.NewBufferString(`{"role":"recruiter","email_verified_at":null,"two_factor_enabled":false}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, adminClaims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "2"})
+
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+				ms.EXPECT().GrantRoleService(gomock.Any(), uint64(2), models.RoleRecruiter).Return(models.User{Username: "rec", Role: models.RoleRecruiter}, nil).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"username":"rec","email":"","role":"recruiter","email_verified_at":null,"two_factor_enabled":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr, ms := tt.setup()
+
+			h := &handler{
+				service: ms,
+			}
+			h.GrantUserRole(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ListAuditEvents(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name               string
+		query              string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid user id",
+			query:              "user_id=abc",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"user_id must be a number"}`,
+		},
+		{
+			name:               "invalid time",
+			query:              "from=yesterday",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"from must be an RFC 3339 time"}`,
+		},
+		{
+			name:               "range ends before it starts",
+			query:              "from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"to must not be before from"}`,
+		},
+		{
+			name:  "error from service",
+			query: "",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListAuditEventsService(gomock.Any(), models.AuditQuery{}).Return(nil, errors.New("db down")).Times(1)
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"error":"db down"}`,
+		},
+		{
+			name:  "success",
+			query: "user_id=2&event_type=login.failed&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z&limit=10",
+			setup: func(ms *service.MockUserService) {
+				want := models.AuditQuery{UserID: 2, EventType: models.AuditLoginFailed, From: from, To: to, Limit: 10}
+				ms.EXPECT().ListAuditEventsService(gomock.Any(), want).Return([]models.AuditEvent{{ID: 1, EventType: models.AuditLoginFailed, TargetUserID: func() *uint { v := uint(2); return &v }(), IP: "10.0.0.1", CreatedAt: from}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"id":1,"eventType":"login.failed","actorUserId":null,"actorApiKeyId":null,"targetUserId":2,"ip":"10.0.0.1","userAgent":"","traceId":"","createdAt":"2024-01-01T00:00:00Z"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com/api/admin/audit-events?"+tt.query, nil)
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.ListAuditEvents(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ListOutboxMessages(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid limit",
+			query:              "limit=ten",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Bad Request"}`,
+		},
+		{
+			name:  "unknown status",
+			query: "status=lost",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListOutboxMessagesService(gomock.Any(), "lost", 0).Return(nil, errors.New(`unknown status "lost"`)).Times(1)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"unknown status \"lost\""}`,
+		},
+		{
+			name:  "success",
+			query: "status=dead&limit=5",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListOutboxMessagesService(gomock.Any(), models.OutboxStatusDead, 5).Return([]models.OutboxMessage{{
+					ID:          3,
+					Kind:        models.OutboxKindEmail,
+					Destination: "test@example.com",
+					Summary:     "Your password reset code",
+					Payload:     []byte(`{"Text":"123456"}`),
+					Status:      models.OutboxStatusDead,
+					Attempts:    10,
+					LastError:   "connection refused",
+				}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"id":3,"kind":"email","destination":"test@example.com","summary":"Your password reset code","status":"dead","attempts":10,"nextAttemptAt":"0001-01-01T00:00:00Z","lastError":"connection refused","deliveredAt":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com/api/admin/outbox?"+tt.query, nil)
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.ListOutboxMessages(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ReplayOutboxMessage(t *testing.T) {
+	tests := []struct {
+		name               string
+		messageID          string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid id",
+			messageID:          "abc",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Bad Request"}`,
+		},
+		{
+			name:      "not a failed delivery",
+			messageID: "3",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ReplayOutboxMessageService(gomock.Any(), uint(3)).Return(service.ErrNoDeadOutboxMessage).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"no failed delivery with that id"}`,
+		},
+		{
+			name:      "success",
+			messageID: "3",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ReplayOutboxMessageService(gomock.Any(), uint(3)).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"message":"delivery queued again"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "messageID", Value: tt.messageID})
+
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.ReplayOutboxMessage(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
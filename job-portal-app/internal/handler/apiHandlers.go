@@ -2,18 +2,22 @@ package handler
 
 import (
 	"log"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"           // Importing the Gin framework for handling HTTP requests and responses.
 	"job-portal-api/internal/auth"       // Importing custom authentication package.
+	"job-portal-api/internal/config"     // Importing the application configuration.
 	"job-portal-api/internal/middleware" // Importing custom middleware package.
+	"job-portal-api/internal/models"     // Importing the role definitions used for authorization.
 	"job-portal-api/internal/service"    // Importing custom service package for business logic.
 )
 
 // SetupApi is a function that sets up the API routes, middleware, and handlers.
-func SetupApi(a auth.Authentication, svc service.UserService) *gin.Engine {
+func SetupApi(a auth.Authentication, svc service.UserService, limiter middleware.RateLimitStore, cache CacheMonitor, cfg config.Config) *gin.Engine {
 	r := gin.New() // Creating a new Gin engine.
 
-	m, err := middleware.NewMiddleware(a)
+	m, err := middleware.NewMiddleware(a, svc, limiter, svc, cfg.AuthConfig)
 	// Creating a new instance of the middleware with the provided authentication.
 	if err != nil {
 		log.Panic("Error setting up middleware")
@@ -26,21 +30,75 @@ func SetupApi(a auth.Authentication, svc service.UserService) *gin.Engine {
 		log.Panic("Error setting up handler")
 		// Logging an error if handler setup fails.
 	}
-	r.Use(m.Log(), gin.Recovery())
+	// the throttles key on the client IP, which must not be taken from a spoofed header
+	err = r.SetTrustedProxies(trustedProxies(cfg.AppConfig.TrustedProxies))
+	if err != nil {
+		log.Panic("Error setting up trusted proxies")
+	}
+	r.Use(m.Log(), m.Localize(), gin.Recovery())
 	r.GET("/check", m.Authenticate(Check))
+	r.GET("/.well-known/jwks.json", JWKS(a))
+	r.GET("/health", Health(cache))
+	r.GET("/metrics", Metrics(cache))
 	r.POST("/api/register", h.RegisterUser)
-	r.POST("/api/login", h.UserLogin)
-	r.POST("/api/companies", m.Authenticate(h.CreateCompany))
-	r.GET("/api/companies", m.Authenticate(h.ListCompanies))
+	r.POST("/api/login", m.Throttle(h.UserLogin, "login"))
+	r.POST("/api/login/2fa", m.Throttle(h.TwoFactorLogin, "login-2fa"))
+	r.GET("/api/oidc/:provider/login", h.OIDCLogin)
+	r.GET("/api/oidc/:provider/callback", h.OIDCCallback)
+	r.POST("/api/2fa/enrol", m.Authenticate(h.EnrolTwoFactor))
+	r.POST("/api/2fa/confirm", m.Authenticate(h.ConfirmTwoFactor))
+	r.POST("/api/2fa/disable", m.Authenticate(h.DisableTwoFactor))
+	r.GET("/api/verify-email", h.VerifyEmail)
+	r.POST("/api/verify-email/resend", m.Throttle(h.ResendVerificationEmail, "verify-email-resend"))
+	r.POST("/api/token/refresh", m.Throttle(h.RefreshToken, "token-refresh"))
+	r.GET("/api/me", m.Authenticate(h.GetMe))
+	r.PATCH("/api/me", m.Authenticate(h.UpdateMe))
+	r.DELETE("/api/me", m.Authenticate(h.DeleteMe))
+	r.GET("/api/me/export", m.Authenticate(h.ExportMe))
+	r.GET("/api/me/notifications", m.Authenticate(h.ListNotifications))
+	r.GET("/api/me/notifications/unread-count", m.Authenticate(h.UnreadNotificationCount))
+	r.POST("/api/me/notifications/read-all", m.Authenticate(h.MarkAllNotificationsRead))
+	r.POST("/api/me/notifications/:notificationID/read", m.Authenticate(h.MarkNotificationRead))
+	r.GET("/api/me/job-preferences", m.Authenticate(h.GetJobPreference))
+	r.PUT("/api/me/job-preferences", m.Authenticate(h.SaveJobPreference))
+	r.POST("/api/logout", m.Authenticate(h.Logout))
+	r.POST("/api/logout-all", m.Authenticate(h.LogoutAll))
+	r.POST("/api/companies", m.Authenticate(m.Authorize(h.CreateCompany, models.RoleAdmin, models.RoleRecruiter)))
+	r.GET("/api/companies", m.AuthenticateOptional(h.ListCompanies))
 	r.GET("/api/companies/:companyID", m.Authenticate(h.GetCompany))
-	r.POST("/api/companies/:companyID/jobs", m.Authenticate(h.CreateJobPosting))
-	r.GET("/api/companies/:companyID/jobs", m.Authenticate(h.ListJobsForCompany))
-	r.GET("/api/jobs/:jobID", m.Authenticate(h.GetJobPostingByID))
-	r.GET("/api/jobs", m.Authenticate(h.GetAllJobPostings))
-	r.POST("/api/process", h.ProcessJobApplication)
-	r.POST("/api/forget-password", h.ForgotPasswordHandler)
-	r.POST("/api/update-password", h.UpdatePasswordHandler)
-	r.POST("/api/change-password", h.ChangePasswordHandler)
+	r.POST("/api/companies/:companyID/jobs", m.Authenticate(m.Authorize(h.CreateJobPosting, models.RoleAdmin, models.RoleRecruiter, models.RoleService), models.ScopeJobsWrite))
+	r.GET("/api/companies/:companyID/jobs", m.Authenticate(h.ListJobsForCompany, models.ScopeJobsRead))
+	r.GET("/api/companies/:companyID/members", m.Authenticate(m.Authorize(h.ListCompanyMembers, models.RoleAdmin, models.RoleRecruiter)))
+	r.POST("/api/companies/:companyID/members", m.Authenticate(m.Authorize(h.AddCompanyMember, models.RoleAdmin, models.RoleRecruiter)))
+	r.DELETE("/api/companies/:companyID/members/:userID", m.Authenticate(m.Authorize(h.RemoveCompanyMember, models.RoleAdmin, models.RoleRecruiter)))
+	r.GET("/api/companies/:companyID/api-keys", m.Authenticate(m.Authorize(h.ListAPIKeys, models.RoleAdmin, models.RoleRecruiter)))
+	r.POST("/api/companies/:companyID/api-keys", m.Authenticate(m.Authorize(h.CreateAPIKey, models.RoleAdmin, models.RoleRecruiter)))
+	r.DELETE("/api/companies/:companyID/api-keys/:keyID", m.Authenticate(m.Authorize(h.RevokeAPIKey, models.RoleAdmin, models.RoleRecruiter)))
+	r.GET("/api/companies/:companyID/webhooks", m.Authenticate(m.Authorize(h.ListWebhookEndpoints, models.RoleAdmin, models.RoleRecruiter)))
+	r.POST("/api/companies/:companyID/webhooks", m.Authenticate(m.Authorize(h.CreateWebhookEndpoint, models.RoleAdmin, models.RoleRecruiter)))
+	r.DELETE("/api/companies/:companyID/webhooks/:webhookID", m.Authenticate(m.Authorize(h.DeleteWebhookEndpoint, models.RoleAdmin, models.RoleRecruiter)))
+	r.GET("/api/companies/:companyID/webhooks/:webhookID/deliveries", m.Authenticate(m.Authorize(h.ListWebhookDeliveries, models.RoleAdmin, models.RoleRecruiter)))
+	r.POST("/api/companies/:companyID/webhooks/:webhookID/test", m.Authenticate(m.Authorize(h.SendTestWebhook, models.RoleAdmin, models.RoleRecruiter)))
+	r.GET("/api/jobs/:jobID", m.AuthenticateOptional(h.GetJobPostingByID, models.ScopeJobsRead))
+	r.PUT("/api/jobs/:jobID", m.Authenticate(m.Authorize(h.UpdateJobPosting, models.RoleAdmin, models.RoleRecruiter, models.RoleService), models.ScopeJobsWrite))
+	r.POST("/api/jobs/:jobID/close", m.Authenticate(m.Authorize(h.CloseJobPosting, models.RoleAdmin, models.RoleRecruiter, models.RoleService), models.ScopeJobsWrite))
+	r.POST("/api/jobs/:jobID/interview-invites", m.Authenticate(m.Authorize(h.InviteToInterview, models.RoleAdmin, models.RoleRecruiter, models.RoleService), models.ScopeApplicationsProcess))
+	r.GET("/api/jobs", m.AuthenticateOptional(h.GetAllJobPostings, models.ScopeJobsRead))
+	r.POST("/api/jobs/:jobID/explain", m.Authenticate(h.ExplainJobApplication))
+	r.POST("/api/process", m.Authenticate(m.Authorize(h.ProcessJobApplication, models.RoleAdmin, models.RoleRecruiter, models.RoleService), models.ScopeApplicationsProcess))
+	r.POST("/api/forget-password", m.Throttle(h.ForgotPasswordHandler, "forget-password"))
+	r.POST("/api/verify-otp", m.Throttle(h.VerifyOTPHandler, "verify-otp"))
+	r.POST("/api/update-password", m.Throttle(h.UpdatePasswordHandler, "update-password"))
+	r.POST("/api/change-password", m.Authenticate(h.ChangePasswordHandler))
+	r.PUT("/api/admin/users/:userID/role", m.Authenticate(m.Authorize(h.GrantUserRole, models.RoleAdmin)))
+	r.DELETE("/api/admin/users/:userID/role", m.Authenticate(m.Authorize(h.RevokeUserRole, models.RoleAdmin)))
+	r.GET("/api/admin/api-keys", m.Authenticate(m.Authorize(h.ListAPIKeys, models.RoleAdmin)))
+	r.POST("/api/admin/api-keys", m.Authenticate(m.Authorize(h.CreateAPIKey, models.RoleAdmin)))
+	r.DELETE("/api/admin/api-keys/:keyID", m.Authenticate(m.Authorize(h.RevokeAPIKey, models.RoleAdmin)))
+	r.GET("/api/admin/lockouts", m.Authenticate(m.Authorize(h.ListLockouts, models.RoleAdmin)))
+	r.GET("/api/admin/audit-events", m.Authenticate(m.Authorize(h.ListAuditEvents, models.RoleAdmin)))
+	r.GET("/api/admin/outbox", m.Authenticate(m.Authorize(h.ListOutboxMessages, models.RoleAdmin)))
+	r.POST("/api/admin/outbox/:messageID/replay", m.Authenticate(m.Authorize(h.ReplayOutboxMessage, models.RoleAdmin)))
 	return r
 	// Returning the configured Gin engine.
 }
@@ -51,3 +109,23 @@ func Check(c *gin.Context) {
 		"Message": "ok",
 	})
 }
+
+// JWKS serves the public signing keys so that other services can validate portal tokens.
+func JWKS(a auth.Authentication) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, a.JWKS())
+	}
+}
+
+// trustedProxies parses the APP_TRUSTED_PROXIES list. It returns nil, trusting no proxy, when
+// the list is empty.
+func trustedProxies(list string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(list, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+	"job-portal-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// post sends a JSON body to the engine from the given address and returns the status code.
+func post(r http.Handler, path, body, remoteAddr string, headers map[string]string) int {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr.Code
+}
+
+func Test_SetupApi_ipLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		wantThrottled  bool
+	}{
+		{
+			name:          "spoofed X-Forwarded-For is ignored",
+			wantThrottled: true,
+		},
+		{
+			name:           "trusted proxy forwards the client ip",
+			trustedProxies: "192.0.2.1",
+			wantThrottled:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			ms.EXPECT().UserLoginService(gomock.Any(), gomock.Any()).Return(models.TokenPair{}, nil).AnyTimes()
+			cfg := config.Config{AppConfig: config.AppConfig{TrustedProxies: tt.trustedProxies}}
+			r := SetupApi(nil, ms, redis.NewMemory(), nil, cfg)
+
+			// every request comes through the same proxy and claims another client
+			throttled := false
+			for i := 0; i < 40; i++ {
+				body := fmt.Sprintf(`{"username":"u","email":"user%d@example.com","password":"secret"}`, i)
+				code := post(r, "/api/login", body, "192.0.2.1:1234", map[string]string{"X-Forwarded-For": fmt.Sprintf("198.51.100.%d", i)})
+				if code == http.StatusTooManyRequests {
+					throttled = true
+				}
+			}
+			assert.Equal(t, tt.wantThrottled, throttled)
+		})
+	}
+}
+
+func Test_SetupApi_accountFailures(t *testing.T) {
+	mc := gomock.NewController(t)
+	ms := service.NewMockUserService(mc)
+	ms.EXPECT().UserLoginService(gomock.Any(), gomock.Any()).Return(models.TokenPair{}, errors.New("invalid email or password")).Times(3)
+	ms.EXPECT().ForgetPasswordService(gomock.Any(), gomock.Any()).Return(models.ForgetPasswordResponse{}, nil).Times(1)
+	r := SetupApi(nil, ms, redis.NewMemory(), nil, config.Config{})
+
+	// failed logins for one account from different addresses
+	body := `{"username":"u","email":"jane@example.com","password":"wrong"}`
+	for i := 0; i < 3; i++ {
+		code := post(r, "/api/login", body, fmt.Sprintf("198.51.100.%d:1234", i), nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	}
+	code := post(r, "/api/login", body, "198.51.100.9:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, code)
+
+	// the failures of one endpoint do not hold up the account on another
+	code = post(r, "/api/forget-password", `{"email":"jane@example.com"}`, "198.51.100.9:1234", nil)
+	assert.Equal(t, http.StatusOK, code)
+}
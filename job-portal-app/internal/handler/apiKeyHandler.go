@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+)
+
+// apiKeyCompanyID reads the company of the key routes. The admin routes have no companyID
+// and manage service account keys, which the service identifies by company 0.
+func apiKeyCompanyID(c *gin.Context) (uint64, error) {
+	id := c.Param("companyID")
+	if id == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(id, 10, 64)
+}
+
+// CreateAPIKey creates an API key. The key is in the response and cannot be retrieved later.
+func (h *handler) CreateAPIKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, err := apiKeyCompanyID(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	var keyData models.NewAPIKeyRequest
+	err = json.NewDecoder(c.Request.Body).Decode(&keyData)
+	if err == nil {
+		err = validator.New().Struct(keyData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a name and valid scopes"),
+		})
+		return
+	}
+
+	key, err := h.service.CreateAPIKeyService(ctx, actor, cid, keyData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *handler) ListAPIKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, err := apiKeyCompanyID(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	keys, err := h.service.ListAPIKeysService(ctx, actor, cid)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *handler) RevokeAPIKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, err := apiKeyCompanyID(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+	keyID, err := strconv.ParseUint(c.Param("keyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	err = h.service.RevokeAPIKeyService(ctx, actor, cid, uint(keyID))
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "api key revoked")})
+}
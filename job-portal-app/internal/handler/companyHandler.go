@@ -7,8 +7,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
@@ -20,14 +20,14 @@ func (h *handler) GetCompany(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid is missing")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	_, ok = ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
 		return
 	}
 
@@ -35,15 +35,15 @@ func (h *handler) GetCompany(c *gin.Context) {
 
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
 		return
 	}
 
 	companyData, err := h.service.GetCompanyService(ctx, cid)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
@@ -57,27 +57,26 @@ func (h *handler) ListCompanies(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
-	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
-		return
-	}
+	_, authenticated := ctx.Value(auth.Key).(auth.Claims)
 
 	companyDetails, err := h.service.ListCompaniesService(ctx)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, companyDetails)
+	records := make([]gorm.Model, len(companyDetails))
+	for i, company := range companyDetails {
+		records[i] = company.Model
+	}
+	respondCached(c, etag("companies", records...), !authenticated, companyDetails)
 
 }
 
@@ -87,25 +86,31 @@ func (h *handler) CreateCompany(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
 
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
 		return
 	}
 
 	var companyData models.Company
 
-	err := json.NewDecoder(c.Request.Body).Decode(&companyData)
+	err = json.NewDecoder(c.Request.Body).Decode(&companyData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid name, location",
+			"error": tr(c, "please provide valid name, location"),
 		})
 		return
 	}
@@ -113,18 +118,18 @@ func (h *handler) CreateCompany(c *gin.Context) {
 	validate := validator.New()
 	err = validate.Struct(companyData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid name, location",
+			"error": tr(c, "please provide valid name, location"),
 		})
 		return
 	}
 
-	companyData, err = h.service.CreateCompanyService(ctx, companyData)
+	companyData, err = h.service.CreateCompanyService(ctx, actor, companyData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
@@ -63,7 +63,7 @@ func Test_handler_GetCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 
@@ -83,7 +83,7 @@ func Test_handler_GetCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
@@ -107,7 +107,7 @@ func Test_handler_GetCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
@@ -163,7 +163,7 @@ func Test_handler_ListCompanies(t *testing.T) {
 			expectedResponse:   `{"error":"Internal Server Error"}`,
 		},
 		{
-			name: "missing jwt claims",
+			name: "anonymous read",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
@@ -172,11 +172,15 @@ func Test_handler_ListCompanies(t *testing.T) {
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
 
-				return c, rr, nil
+				ms.EXPECT().ListCompaniesService(c.Request.Context()).Return([]models.Company{}, nil).Times(1)
+
+				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[]`,
 		},
 		{
 			name: "error while listing companies",
@@ -186,7 +190,7 @@ func Test_handler_ListCompanies(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
@@ -207,7 +211,7 @@ func Test_handler_ListCompanies(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
@@ -293,7 +297,7 @@ func Test_handler_CreateCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", reqBody)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 
@@ -312,13 +316,13 @@ func Test_handler_CreateCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", reqBody)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := service.NewMockUserService(mc)
 
-				ms.EXPECT().CreateCompanyService(c.Request.Context(), gomock.Any()).Return(models.Company{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().CreateCompanyService(c.Request.Context(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, gomock.Any()).Return(models.Company{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -334,13 +338,13 @@ func Test_handler_CreateCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", reqBody)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := service.NewMockUserService(mc)
 
-				ms.EXPECT().CreateCompanyService(c.Request.Context(), gomock.Any()).Return(models.Company{
+				ms.EXPECT().CreateCompanyService(c.Request.Context(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, gomock.Any()).Return(models.Company{
 					Model:    gorm.Model{ID: 1, CreatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC), UpdatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC)},
 					Name:     "Teksystem",
 					Location: "USA",
@@ -367,3 +371,79 @@ func Test_handler_CreateCompany(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_ListCompanies_conditional(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	companies := []models.Company{
+		{Model: gorm.Model{ID: 1, UpdatedAt: updatedAt}, Name: "Acme"},
+		{Model: gorm.Model{ID: 2, UpdatedAt: updatedAt}, Name: "Globex"},
+	}
+	tag := etag("companies", companies[0].Model, companies[1].Model)
+	tests := []struct {
+		name               string
+		anonymous          bool
+		ifNoneMatch        string
+		expectedStatusCode int
+	}{
+		{
+			name:               "unchanged",
+			ifNoneMatch:        tag,
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name:               "any version",
+			ifNoneMatch:        "*",
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name:               "company added since",
+			ifNoneMatch:        etag("companies", companies[0].Model),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "company updated since",
+			ifNoneMatch:        etag("companies", companies[0].Model, gorm.Model{ID: 2, UpdatedAt: updatedAt.Add(-time.Second)}),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "anonymous read",
+			anonymous:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "anonymous read not modified",
+			anonymous:          true,
+			ifNoneMatch:        tag,
+			expectedStatusCode: http.StatusNotModified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
+			httpRequest.Header.Set("If-None-Match", tt.ifNoneMatch)
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			if !tt.anonymous {
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			}
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			ms.EXPECT().ListCompaniesService(gomock.Any()).Return(companies, nil).Times(1)
+
+			h := &handler{
+				service: ms,
+			}
+			h.ListCompanies(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tag, rr.Header().Get("ETag"))
+			if tt.anonymous {
+				assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+			} else {
+				assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
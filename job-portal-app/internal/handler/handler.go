@@ -1,7 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/i18n"
+	"job-portal-api/internal/models"
 	"job-portal-api/internal/service"
 )
 
@@ -12,6 +20,9 @@ type handler struct {
 type Handler interface {
 	UserLogin(c *gin.Context)
 	RegisterUser(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
 
 	GetCompany(c *gin.Context)
 	ListCompanies(c *gin.Context)
@@ -21,10 +32,52 @@ type Handler interface {
 	GetAllJobPostings(c *gin.Context)
 	ListJobsForCompany(c *gin.Context)
 	CreateJobPosting(c *gin.Context)
+	UpdateJobPosting(c *gin.Context)
+	CloseJobPosting(c *gin.Context)
+	InviteToInterview(c *gin.Context)
 	ProcessJobApplication(c *gin.Context)
+	ExplainJobApplication(c *gin.Context)
+	TwoFactorLogin(c *gin.Context)
+	EnrolTwoFactor(c *gin.Context)
+	ConfirmTwoFactor(c *gin.Context)
+	DisableTwoFactor(c *gin.Context)
+	OIDCLogin(c *gin.Context)
+	OIDCCallback(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	ResendVerificationEmail(c *gin.Context)
 	ForgotPasswordHandler(c *gin.Context)
+	VerifyOTPHandler(c *gin.Context)
 	UpdatePasswordHandler(c *gin.Context)
 	ChangePasswordHandler(c *gin.Context)
+	GetMe(c *gin.Context)
+	UpdateMe(c *gin.Context)
+	ExportMe(c *gin.Context)
+	DeleteMe(c *gin.Context)
+	ListNotifications(c *gin.Context)
+	UnreadNotificationCount(c *gin.Context)
+	MarkNotificationRead(c *gin.Context)
+	MarkAllNotificationsRead(c *gin.Context)
+	GetJobPreference(c *gin.Context)
+	SaveJobPreference(c *gin.Context)
+
+	GrantUserRole(c *gin.Context)
+	RevokeUserRole(c *gin.Context)
+
+	ListCompanyMembers(c *gin.Context)
+	AddCompanyMember(c *gin.Context)
+	RemoveCompanyMember(c *gin.Context)
+	CreateAPIKey(c *gin.Context)
+	ListAPIKeys(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+	CreateWebhookEndpoint(c *gin.Context)
+	ListWebhookEndpoints(c *gin.Context)
+	DeleteWebhookEndpoint(c *gin.Context)
+	ListWebhookDeliveries(c *gin.Context)
+	SendTestWebhook(c *gin.Context)
+	ListLockouts(c *gin.Context)
+	ListAuditEvents(c *gin.Context)
+	ListOutboxMessages(c *gin.Context)
+	ReplayOutboxMessage(c *gin.Context)
 }
 
 func NewHandler(svc service.UserService) (Handler, error) {
@@ -32,3 +85,68 @@ func NewHandler(svc service.UserService) (Handler, error) {
 		service: svc,
 	}, nil
 }
+
+// actorFromClaims builds the service actor from the JWT claims of the request.
+func actorFromClaims(claims auth.Claims) (models.Actor, error) {
+	if claims.APIKeyID != 0 {
+		return models.Actor{
+			Role:      models.RoleService,
+			APIKeyID:  claims.APIKeyID,
+			CompanyID: claims.CompanyID,
+		}, nil
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return models.Actor{}, err
+	}
+	return models.Actor{
+		UserID: uint(uid),
+		Role:   claims.Role,
+	}, nil
+}
+
+// actorFromContext reads the actor from the JWT claims, aborting with 401 if there is none.
+func (h *handler) actorFromContext(c *gin.Context, traceid string) (models.Actor, bool) {
+	claims, ok := c.Request.Context().Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return models.Actor{}, false
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in token")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return models.Actor{}, false
+	}
+	return actor, true
+}
+
+// tr translates an English API message to the locale of the request.
+func tr(c *gin.Context, msg string) string {
+	return i18n.Translate(c.Request.Context(), msg)
+}
+
+// errorStatus maps service errors to an HTTP status, defaulting to fallback.
+func errorStatus(err error, fallback int) int {
+	if errors.Is(err, service.ErrNotCompanyMember) || errors.Is(err, service.ErrAdminOnly) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, service.ErrJobNotFound) {
+		return http.StatusNotFound
+	}
+	return fallback
+}
+
+// errorBody renders a service error. Password policy errors list the broken rules under the
+// request field they belong to, e.g. {"error":"...","fields":{"password":["..."]}}.
+func errorBody(c *gin.Context, err error) gin.H {
+	var passwordErr *service.PasswordError
+	if errors.As(err, &passwordErr) {
+		return gin.H{
+			"error":  tr(c, service.ErrWeakPassword.Error()),
+			"fields": gin.H{passwordErr.Field: passwordErr.Problems},
+		}
+	}
+	return gin.H{"error": tr(c, err.Error())}
+}
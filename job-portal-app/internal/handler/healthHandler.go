@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"job-portal-api/internal/redis"
+)
+
+// CacheMonitor reports the state of the cache. It is implemented by redis.Breaker.
+type CacheMonitor interface {
+	Ping(ctx context.Context) error
+	Stats() redis.BreakerStats
+}
+
+// Health reports whether the api can serve requests. A cache outage makes it degraded but
+// still healthy, since every request falls back to the database, so the response is 200
+// either way and load balancers keep sending traffic.
+func Health(cache CacheMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// while the breaker is open this fails at once, or is the probe that closes it
+		err := cache.Ping(c.Request.Context())
+		status := "ok"
+		if err != nil {
+			status = "degraded"
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{
+			"status": status,
+			"cache":  cache.Stats(),
+		})
+	}
+}
+
+// Metrics serves the state of the cache circuit breaker in the Prometheus text format.
+func Metrics(cache CacheMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats := cache.Stats()
+		up := 0
+		if stats.State == redis.BreakerClosed {
+			up = 1
+		}
+		var b bytes.Buffer
+		writeMetric(&b, "cache_up", "gauge", "Whether the cache circuit breaker is closed.", uint64(up))
+		writeMetric(&b, "cache_consecutive_failures", "gauge", "Cache calls that failed in a row.", uint64(stats.Failures))
+		writeMetric(&b, "cache_breaker_trips_total", "counter", "Times the cache circuit breaker opened.", stats.Trips)
+		writeMetric(&b, "cache_breaker_rejected_total", "counter", "Cache calls that failed fast because the circuit breaker was open.", stats.Rejected)
+		writeMetric(&b, "cache_fallback_calls_total", "counter", "Cache calls served from process memory instead.", stats.FallbackCalls)
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", b.Bytes())
+	}
+}
+
+func writeMetric(b *bytes.Buffer, name, kind, help string, value uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, kind, name, value)
+}
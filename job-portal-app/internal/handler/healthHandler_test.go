@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"job-portal-api/internal/redis"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type stubCache struct {
+	err   error
+	stats redis.BreakerStats
+}
+
+func (s stubCache) Ping(ctx context.Context) error { return s.err }
+func (s stubCache) Stats() redis.BreakerStats      { return s.stats }
+
+func Test_Health(t *testing.T) {
+	tests := []struct {
+		name             string
+		cache            stubCache
+		expectedResponse string
+	}{
+		{
+			name:             "cache up",
+			cache:            stubCache{stats: redis.BreakerStats{State: redis.BreakerClosed}},
+			expectedResponse: `{"cache":{"state":"closed","consecutive_failures":0,"trips":0,"rejected":0,"fallback_calls":0},"status":"ok"}`,
+		},
+		{
+			name:             "cache down",
+			cache:            stubCache{err: redis.ErrUnavailable, stats: redis.BreakerStats{State: redis.BreakerOpen, Failures: 5, Trips: 1, Rejected: 12}},
+			expectedResponse: `{"cache":{"state":"open","consecutive_failures":5,"trips":1,"rejected":12,"fallback_calls":0},"status":"degraded"}`,
+		},
+		{
+			name:             "cache failing",
+			cache:            stubCache{err: errors.New("connection refused"), stats: redis.BreakerStats{State: redis.BreakerClosed, Failures: 1}},
+			expectedResponse: `{"cache":{"state":"closed","consecutive_failures":1,"trips":0,"rejected":0,"fallback_calls":0},"status":"degraded"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request, _ = http.NewRequest(http.MethodGet, "http://test.com/health", nil)
+
+			Health(tt.cache)(c)
+			// a cache outage does not take the api out of the load balancer
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_Metrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		cache    stubCache
+		expected []string
+	}{
+		{
+			name:     "cache up",
+			cache:    stubCache{stats: redis.BreakerStats{State: redis.BreakerClosed, Trips: 2}},
+			expected: []string{"cache_up 1\n", "cache_breaker_trips_total 2\n", "# TYPE cache_breaker_trips_total counter\n"},
+		},
+		{
+			name:     "cache down",
+			cache:    stubCache{stats: redis.BreakerStats{State: redis.BreakerOpen, Failures: 5, Rejected: 40, FallbackCalls: 3}},
+			expected: []string{"cache_up 0\n", "cache_consecutive_failures 5\n", "cache_breaker_rejected_total 40\n", "cache_fallback_calls_total 3\n"},
+		},
+		{
+			name:     "probing",
+			cache:    stubCache{stats: redis.BreakerStats{State: redis.BreakerHalfOpen}},
+			expected: []string{"cache_up 0\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request, _ = http.NewRequest(http.MethodGet, "http://test.com/metrics", nil)
+
+			Metrics(tt.cache)(c)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			for _, line := range tt.expected {
+				if !strings.Contains(rr.Body.String(), line) {
+					t.Errorf("Metrics() body is missing %q:\n%s", line, rr.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// publicMaxAge is how long a shared cache may serve an anonymous read without revalidating.
+const publicMaxAge = time.Minute
+
+// etagVersion is part of every ETag. Bump it when the JSON of jobs or companies changes
+// shape, so responses cached before the change are not revalidated against the new ones.
+const etagVersion = "v1"
+
+// etag returns a weak ETag for a response made of records, built from their IDs and last
+// updates rather than from the encoded response, so it is known before the response is
+// serialized. Adding, removing or updating a record changes it.
+func etag(kind string, records ...gorm.Model) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s:%s:%d", kind, etagVersion, len(records))
+	for _, r := range records {
+		fmt.Fprintf(h, ":%d@%d", r.ID, r.UpdatedAt.UnixNano())
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum(nil)[:16])
+}
+
+// respondCached writes body, or 304 Not Modified when the If-None-Match of the request
+// already holds tag. Public responses, the anonymous reads, may be stored by shared caches
+// for publicMaxAge; authenticated ones only by the client, which has to revalidate them
+// every time.
+func respondCached(c *gin.Context, tag string, public bool, body interface{}) {
+	c.Header("ETag", tag)
+	c.Writer.Header().Add("Vary", "Authorization, X-API-Key")
+	if public {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(publicMaxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+	if etagMatches(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// etagMatches reports whether the If-None-Match header matches tag, using the weak
+// comparison RFC 9110 requires for it.
+func etagMatches(ifNoneMatch, tag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_respondCached(t *testing.T) {
+	tag := etag("job", gorm.Model{ID: 7})
+	tests := []struct {
+		name               string
+		public             bool
+		ifNoneMatch        string
+		expectedStatusCode int
+		expectedCache      string
+		expectedResponse   string
+	}{
+		{
+			name:               "private route",
+			expectedStatusCode: http.StatusOK,
+			expectedCache:      "private, no-cache",
+			expectedResponse:   `{"id":7}`,
+		},
+		{
+			name:               "public route",
+			public:             true,
+			expectedStatusCode: http.StatusOK,
+			expectedCache:      "public, max-age=60",
+			expectedResponse:   `{"id":7}`,
+		},
+		{
+			name:               "public route not modified",
+			public:             true,
+			ifNoneMatch:        tag,
+			expectedStatusCode: http.StatusNotModified,
+			expectedCache:      "public, max-age=60",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request, _ = http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
+			c.Request.Header.Set("If-None-Match", tt.ifNoneMatch)
+
+			respondCached(c, tag, tt.public, gin.H{"id": 7})
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tag, rr.Header().Get("ETag"))
+			assert.Equal(t, tt.expectedCache, rr.Header().Get("Cache-Control"))
+			assert.Equal(t, "Authorization, X-API-Key", rr.Header().Get("Vary"))
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -2,15 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
 )
 
 func (h *handler) GetJobPostingByID(c *gin.Context) {
@@ -19,33 +21,28 @@ func (h *handler) GetJobPostingByID(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
-	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
-		return
-	}
+	_, authenticated := ctx.Value(auth.Key).(auth.Claims)
 
 	id := c.Param("jobID")
 
 	jid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
 		return
 	}
 
 	jobData, err := h.service.GetJobPostingByIDService(ctx, jid)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": tr(c, err.Error())})
 		return
 	}
 
-	c.JSON(http.StatusOK, jobData)
+	respondCached(c, etag("job", jobData.Model), !authenticated, jobData)
 
 }
 
@@ -55,26 +52,25 @@ func (h *handler) GetAllJobPostings(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
-	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
-		return
-	}
+	_, authenticated := ctx.Value(auth.Key).(auth.Claims)
 	jobDatas, err := h.service.GetAllJobPostingsService(ctx)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, jobDatas)
+	records := make([]gorm.Model, len(jobDatas))
+	for i, job := range jobDatas {
+		records[i] = job.Model
+	}
+	respondCached(c, etag("jobs", records...), !authenticated, jobDatas)
 
 }
 
@@ -84,14 +80,14 @@ func (h *handler) ListJobsForCompany(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	_, ok = ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
 		return
 	}
 
@@ -99,15 +95,15 @@ func (h *handler) ListJobsForCompany(c *gin.Context) {
 
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
 		return
 	}
 
 	jobData, err := h.service.ListJobsForCompanyService(ctx, cid)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
@@ -122,14 +118,20 @@ func (h *handler) CreateJobPosting(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
 		return
 	}
 
@@ -137,7 +139,7 @@ func (h *handler) CreateJobPosting(c *gin.Context) {
 
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
 		return
 	}
 
@@ -145,18 +147,18 @@ func (h *handler) CreateJobPosting(c *gin.Context) {
 
 	err = json.NewDecoder(c.Request.Body).Decode(&jobData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid name, location",
+			"error": tr(c, "please provide valid name, location"),
 		})
 		return
 	}
 
-	jobDatas, err := h.service.CreateJobPostingService(ctx, jobData, cid)
+	jobDatas, err := h.service.CreateJobPostingService(ctx, actor, jobData, cid)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
@@ -164,33 +166,146 @@ func (h *handler) CreateJobPosting(c *gin.Context) {
 	c.JSON(http.StatusOK, jobDatas)
 }
 
+// UpdateJobPosting replaces the details of an existing job posting.
+func (h *handler) UpdateJobPosting(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	id := c.Param("jobID")
+
+	jid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	var jobData models.NewJobRequest
+
+	err = json.NewDecoder(c.Request.Body).Decode(&jobData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide valid job details"),
+		})
+		return
+	}
+
+	job, err := h.service.UpdateJobPostingService(ctx, actor, jid, jobData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CloseJobPosting stops a job from taking applications.
+func (h *handler) CloseJobPosting(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	jid, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	job, err := h.service.CloseJobPostingService(ctx, actor, jid)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := errorStatus(err, http.StatusBadRequest)
+		if errors.Is(err, service.ErrJobClosed) {
+			status = http.StatusConflict
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
 func (h *handler) ProcessJobApplication(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
 
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
 	var application []models.RequestJob
 
-	err := json.NewDecoder(c.Request.Body).Decode(&application)
+	err = json.NewDecoder(c.Request.Body).Decode(&application)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid application data",
+			"error": tr(c, "please provide valid application data"),
 		})
 		return
 	}
 
-	applications, err := h.service.ApplicationProcessor(ctx, application)
+	applications, err := h.service.ApplicationProcessor(ctx, actor, application)
+	if errors.Is(err, service.ErrNotCompanyMember) || errors.Is(err, service.ErrJobNotFound) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "application processing error",
+			"error": tr(c, "application processing error"),
 		})
 		return
 	}
@@ -198,3 +313,53 @@ func (h *handler) ProcessJobApplication(c *gin.Context) {
 	// Respond with a success message
 	c.JSON(http.StatusOK, applications)
 }
+
+// ExplainJobApplication evaluates one application against a job without storing it and
+// returns the outcome of every criterion.
+func (h *handler) ExplainJobApplication(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	_, ok = ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	id := c.Param("jobID")
+
+	jid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	var application models.RequestJob
+
+	err = json.NewDecoder(c.Request.Body).Decode(&application)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide valid application data"),
+		})
+		return
+	}
+
+	evaluation, err := h.service.ExplainJobApplicationService(ctx, jid, application)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
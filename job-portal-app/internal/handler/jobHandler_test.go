@@ -8,13 +8,16 @@ import (
 	"github.com/go-playground/assert/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/service"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_handler_GetJobPostingByID(t *testing.T) {
@@ -38,7 +41,7 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 			expectedResponse:   `{"error":"Internal Server Error"}`,
 		},
 		{
-			name: "missing jwt claims",
+			name: "anonymous read",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
@@ -47,11 +50,16 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
 
-				return c, rr, nil
+				ms.EXPECT().GetJobPostingByIDService(c.Request.Context(), uint64(123)).Return(models.Jobs{}, nil).Times(1)
+
+				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"Company":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"name":"","location":""},"cid":0,"minNp":0,"maxNp":0,"budget":0,"Locations":null,"TechnologyStacks":null,"WorkModes":null,"description":"","minExp":0,"maxExp":0,"Qualifications":null,"Shifts":null,"JobTypes":null,"closedAt":null}`,
 		},
 		{
 			name: "invalid job id",
@@ -61,7 +69,7 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "1ab"})
 
@@ -80,7 +88,7 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "id", Value: "123"})
@@ -102,7 +110,7 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
@@ -124,7 +132,7 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
@@ -136,7 +144,7 @@ func Test_handler_GetJobPostingByID(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"cid":0,"minNp":0,"maxNp":0,"budget":0,"Locations":null,"TechnologyStacks":null,"WorkModes":null,"description":"","minExp":0,"maxExp":0,"Qualifications":null,"Shifts":null,"JobTypes":null}`,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"Company":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"name":"","location":""},"cid":0,"minNp":0,"maxNp":0,"budget":0,"Locations":null,"TechnologyStacks":null,"WorkModes":null,"description":"","minExp":0,"maxExp":0,"Qualifications":null,"Shifts":null,"JobTypes":null,"closedAt":null}`,
 		},
 	}
 	for _, tt := range tests {
@@ -175,7 +183,7 @@ func Test_handler_GetAllJobPostings(t *testing.T) {
 			expectedResponse:   `{"error":"Internal Server Error"}`,
 		},
 		{
-			name: "missing jwt claims",
+			name: "anonymous read",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
@@ -184,11 +192,15 @@ func Test_handler_GetAllJobPostings(t *testing.T) {
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
 
-				return c, rr, nil
+				ms.EXPECT().GetAllJobPostingsService(c.Request.Context()).Return([]models.Jobs{}, nil).Times(1)
+
+				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[]`,
 		},
 		{
 			name: "error while fetching jobs from service",
@@ -198,7 +210,7 @@ func Test_handler_GetAllJobPostings(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
@@ -219,7 +231,7 @@ func Test_handler_GetAllJobPostings(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
@@ -293,7 +305,7 @@ func Test_handler_ListJobsForCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "abc"})
@@ -311,7 +323,7 @@ func Test_handler_ListJobsForCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
@@ -333,7 +345,7 @@ func Test_handler_ListJobsForCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
@@ -355,7 +367,7 @@ func Test_handler_ListJobsForCompany(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
@@ -430,7 +442,7 @@ func Test_handler_CreateJobPosting(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "invalid"})
@@ -448,7 +460,7 @@ func Test_handler_CreateJobPosting(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"invalid`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
@@ -466,14 +478,14 @@ func Test_handler_CreateJobPosting(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"name":"Software Engineer","salary":"$100,000","location":"San Francisco"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
 				mc := gomock.NewController(t)
 				ms := service.NewMockUserService(mc)
 
-				ms.EXPECT().CreateJobPostingService(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.NewJobResponse{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().CreateJobPostingService(c.Request.Context(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, gomock.Any(), gomock.Any()).Return(models.NewJobResponse{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -488,14 +500,14 @@ func Test_handler_CreateJobPosting(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
 				mc := gomock.NewController(t)
 				ms := service.NewMockUserService(mc)
 
-				ms.EXPECT().CreateJobPostingService(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.NewJobResponse{}, nil).AnyTimes()
+				ms.EXPECT().CreateJobPostingService(c.Request.Context(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, gomock.Any(), gomock.Any()).Return(models.NewJobResponse{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -546,6 +558,7 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`invalid`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 
@@ -554,6 +567,64 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   `{"error":"please provide valid application data"}`,
 		},
+		{
+			name: "missing jwt claims",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`[]`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name: "job of another company",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`[{"jid":4}]`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+				ms.EXPECT().ApplicationProcessor(gomock.Any(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, gomock.Any()).Return(nil, service.ErrNotCompanyMember).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"you do not have access to this company"}`,
+		},
+		{
+			name: "job does not exist",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`[{"jid":404}]`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+				ms.EXPECT().ApplicationProcessor(gomock.Any(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, gomock.Any()).Return(nil, service.ErrJobNotFound).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"job not found"}`,
+		},
 		{
 			name: "error from ApplicationProcessor",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
@@ -564,6 +635,7 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 
 				c.Request = httpRequest
@@ -571,7 +643,7 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 				mc := gomock.NewController(t)
 				ms := service.NewMockUserService(mc)
 
-				ms.EXPECT().ApplicationProcessor(gomock.Any(), gomock.Any()).Return(nil, errors.New("test error")).AnyTimes()
+				ms.EXPECT().ApplicationProcessor(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("test error")).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -589,6 +661,7 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 				// Add trace ID to context
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter})
 				httpRequest = httpRequest.WithContext(ctx)
 
 				// Set request
@@ -598,7 +671,7 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 				ms := service.NewMockUserService(mc)
 
 				// Expect ApplicationProcessor to return a valid response
-				ms.EXPECT().ApplicationProcessor(gomock.Any(), gomock.Any()).Return([]models.RequestJob{}, nil).AnyTimes()
+				ms.EXPECT().ApplicationProcessor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.RequestJob{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -621,3 +694,203 @@ func Test_handler_ProcessJobApplication(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_ExplainJobApplication(t *testing.T) {
+	tests := []struct {
+		name               string
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, service.UserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "missing jwt claims",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name: "invalid job id",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`{}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "abc"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Bad Request"}`,
+		},
+		{
+			name: "invalid request",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`invalid`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "1"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide valid application data"}`,
+		},
+		{
+			name: "error from service",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`{}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "1"})
+
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+				ms.EXPECT().ExplainJobApplicationService(gomock.Any(), uint64(1), gomock.Any()).Return(models.ApplicationEvaluation{}, errors.New("job not found")).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"job not found"}`,
+		},
+		{
+			name: "success",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`{"noticePeriod":45}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "1"})
+
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+				ms.EXPECT().ExplainJobApplicationService(gomock.Any(), uint64(1), models.RequestJob{NoticePeriod: 45}).Return(models.ApplicationEvaluation{
+					Jid: 1,
+					Criteria: []models.CriterionResult{
+						{Criterion: "noticePeriod", Requirement: "notice period must be 30 days or less"},
+					},
+				}, nil).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"jid":1,"eligible":false,"criteria":[{"criterion":"noticePeriod","passed":false,"requirement":"notice period must be 30 days or less"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr, ms := tt.setup()
+
+			h := &handler{
+				service: ms,
+			}
+			h.ExplainJobApplication(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_GetJobPostingByID_conditional(t *testing.T) {
+	job := models.Jobs{Model: gorm.Model{ID: 7, UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}}
+	tag := etag("job", job.Model)
+	tests := []struct {
+		name               string
+		anonymous          bool
+		ifNoneMatch        string
+		expectedStatusCode int
+	}{
+		{
+			name:               "first read",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not modified",
+			ifNoneMatch:        `W/"other", ` + tag,
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name:               "strong form of the etag",
+			ifNoneMatch:        strings.TrimPrefix(tag, "W/"),
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name:               "modified",
+			ifNoneMatch:        etag("job", gorm.Model{ID: 7}),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "anonymous read",
+			anonymous:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "anonymous read not modified",
+			anonymous:          true,
+			ifNoneMatch:        tag,
+			expectedStatusCode: http.StatusNotModified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
+			httpRequest.Header.Set("If-None-Match", tt.ifNoneMatch)
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			if !tt.anonymous {
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			}
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "7"})
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			ms.EXPECT().GetJobPostingByIDService(gomock.Any(), uint64(7)).Return(job, nil).Times(1)
+
+			h := &handler{
+				service: ms,
+			}
+			h.GetJobPostingByID(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tag, rr.Header().Get("ETag"))
+			if tt.anonymous {
+				assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+			} else {
+				assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+			}
+			if tt.expectedStatusCode == http.StatusNotModified {
+				assert.Equal(t, "", rr.Body.String())
+			}
+		})
+	}
+}
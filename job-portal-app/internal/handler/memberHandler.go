@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+)
+
+func (h *handler) ListCompanyMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	id := c.Param("companyID")
+
+	cid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	members, err := h.service.ListCompanyMembersService(ctx, actor, cid)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// AddCompanyMember adds a user to the company, or changes the rights of an existing member.
+func (h *handler) AddCompanyMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	id := c.Param("companyID")
+
+	cid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	var memberData models.NewMemberRequest
+
+	err = json.NewDecoder(c.Request.Body).Decode(&memberData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a valid userId and role"),
+		})
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(memberData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a valid userId and role"),
+		})
+		return
+	}
+
+	member, err := h.service.AddCompanyMemberService(ctx, actor, cid, memberData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *handler) RemoveCompanyMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+	uid, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	err = h.service.RemoveCompanyMemberService(ctx, actor, cid, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "member removed")})
+}
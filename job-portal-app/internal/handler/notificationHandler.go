@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+)
+
+// parseNotificationQuery reads the limit, before and unread query parameters of
+// GET /api/me/notifications.
+func parseNotificationQuery(c *gin.Context) (models.NotificationQuery, error) {
+	var query models.NotificationQuery
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return query, errors.New("limit must be a number")
+		}
+		query.Limit = limit
+	}
+	if v := c.Query("before"); v != "" {
+		before, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return query, errors.New("before must be a number")
+		}
+		query.Before = uint(before)
+	}
+	if v := c.Query("unread"); v != "" {
+		unread, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, errors.New("unread must be true or false")
+		}
+		query.UnreadOnly = unread
+	}
+	return query, nil
+}
+
+// ListNotifications returns a page of the logged in user's notifications, newest first.
+func (h *handler) ListNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	query, err := parseNotificationQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, err.Error())})
+		return
+	}
+
+	page, err := h.service.ListNotificationsService(ctx, actor.UserID, query)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *handler) UnreadNotificationCount(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	count, err := h.service.UnreadNotificationCountService(ctx, actor.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unreadCount": count})
+}
+
+func (h *handler) MarkNotificationRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("notificationID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	err = h.service.MarkNotificationReadService(ctx, actor.UserID, uint(id))
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "notification marked read")})
+}
+
+func (h *handler) MarkAllNotificationsRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	marked, err := h.service.MarkAllNotificationsReadService(ctx, actor.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+func (h *handler) GetJobPreference(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	preference, err := h.service.GetJobPreferenceService(ctx, actor.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+	if preference.UserID == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": tr(c, "no job preference saved")})
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+// SaveJobPreference stores what the logged in user is looking for, in the shape of an
+// application. They are notified of new jobs it would pass.
+func (h *handler) SaveJobPreference(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	var criteria models.RequestJob
+	err := json.NewDecoder(c.Request.Body).Decode(&criteria)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide valid job preferences"),
+		})
+		return
+	}
+
+	preference, err := h.service.SaveJobPreferenceService(ctx, actor.UserID, criteria)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+// InviteToInterview sends a candidate an interview invitation for the job.
+func (h *handler) InviteToInterview(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	jid, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	var invite models.InterviewInviteRequest
+	err = json.NewDecoder(c.Request.Body).Decode(&invite)
+	if err == nil {
+		err = validator.New().Struct(invite)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a candidate and an interview time"),
+		})
+		return
+	}
+
+	err = h.service.InviteToInterviewService(ctx, actor, jid, invite)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := errorStatus(err, http.StatusBadRequest)
+		switch {
+		case errors.Is(err, service.ErrCandidateNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrJobClosed):
+			status = http.StatusConflict
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": tr(c, "interview invitation sent")})
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_handler_ListNotifications(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid limit",
+			query:              "limit=ten",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"limit must be a number"}`,
+		},
+		{
+			name:               "invalid unread",
+			query:              "unread=maybe",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"unread must be true or false"}`,
+		},
+		{
+			name:  "error from service",
+			query: "before=9",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListNotificationsService(gomock.Any(), uint(1), models.NotificationQuery{Before: 9}).Return(models.NotificationPage{}, errors.New("could not fetch the notifications")).Times(1)
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"error":"could not fetch the notifications"}`,
+		},
+		{
+			name:  "success",
+			query: "limit=1&unread=true",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListNotificationsService(gomock.Any(), uint(1), models.NotificationQuery{Limit: 1, UnreadOnly: true}).Return(models.NotificationPage{
+					Notifications: []models.Notification{
+						{ID: 8, UserID: 1, Type: models.NotificationJobMatch, Title: "New job matching your preferences", Body: "Backend engineer", Data: map[string]string{"jobId": "3"}},
+					},
+					UnreadCount: 2,
+					NextBefore:  8,
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"notifications":[{"id":8,"type":"job_match","title":"New job matching your preferences","body":"Backend engineer","data":{"jobId":"3"},"readAt":null,"createdAt":"0001-01-01T00:00:00Z"}],"unreadCount":2,"nextBefore":8}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext("")
+			c.Request.URL.RawQuery = tt.query
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.ListNotifications(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_MarkNotificationRead(t *testing.T) {
+	tests := []struct {
+		name               string
+		notificationID     string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid id",
+			notificationID:     "abc",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Bad Request"}`,
+		},
+		{
+			name:           "someone else's notification",
+			notificationID: "7",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().MarkNotificationReadService(gomock.Any(), uint(1), uint(7)).Return(service.ErrNotificationNotFound).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"notification not found"}`,
+		},
+		{
+			name:           "success",
+			notificationID: "7",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().MarkNotificationReadService(gomock.Any(), uint(1), uint(7)).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"message":"notification marked read"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext("", gin.Param{Key: "notificationID", Value: tt.notificationID})
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.MarkNotificationRead(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_GetJobPreference(t *testing.T) {
+	tests := []struct {
+		name               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "nothing saved",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().GetJobPreferenceService(gomock.Any(), uint(1)).Return(models.JobPreference{}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"no job preference saved"}`,
+		},
+		{
+			name: "success",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().GetJobPreferenceService(gomock.Any(), uint(1)).Return(models.JobPreference{UserID: 1, Criteria: models.RequestJob{Budget: 500000, LocationsIDs: []uint{1}}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"criteria":{"name":"","jid":0,"noticePeriod":0,"budget":500000,"locationsIDs":[1],"technologyStackIDs":null,"workModeIDs":null,"description":"","experience":0,"qualificationIDs":null,"shiftIDs":null,"jobTypeIDs":null},"updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext("")
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.GetJobPreference(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_InviteToInterview(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "no candidate",
+			body:               `{"scheduledAt":"2024-03-01T10:00:00Z"}`,
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide a candidate and an interview time"}`,
+		},
+		{
+			name: "unknown candidate",
+			body: `{"candidateId":4,"scheduledAt":"2024-03-01T10:00:00Z"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().InviteToInterviewService(gomock.Any(), gomock.Any(), uint64(9), gomock.Any()).Return(service.ErrCandidateNotFound).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"candidate not found"}`,
+		},
+		{
+			name: "closed job",
+			body: `{"candidateId":4,"scheduledAt":"2024-03-01T10:00:00Z"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().InviteToInterviewService(gomock.Any(), gomock.Any(), uint64(9), gomock.Any()).Return(service.ErrJobClosed).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"job is already closed"}`,
+		},
+		{
+			name: "success",
+			body: `{"candidateId":4,"scheduledAt":"2024-03-01T10:00:00Z","message":"See you there"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().InviteToInterviewService(gomock.Any(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, uint64(9), models.InterviewInviteRequest{
+					CandidateID: 4,
+					ScheduledAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+					Message:     "See you there",
+				}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse:   `{"message":"interview invitation sent"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext(tt.body, gin.Param{Key: "jobID", Value: "9"})
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.InviteToInterview(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
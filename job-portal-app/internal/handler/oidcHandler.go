@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/service"
+)
+
+// OIDCLogin redirects the user to the identity provider to sign in.
+func (h *handler) OIDCLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	authURL, err := h.service.StartOIDCLoginService(ctx, c.Param("provider"))
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Msg("failed to start oidc login")
+		c.AbortWithStatusJSON(oidcErrorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback is where the identity provider sends the user back to. It responds with the
+// portal's tokens, like a password login.
+func (h *handler) OIDCCallback(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	// the provider reports a cancelled or refused login as an error parameter (RFC 6749 4.1.2.1)
+	if providerErr := c.Query("error"); providerErr != "" {
+		log.Warn().Str("trace id", traceid).Str("error", providerErr).Str("description", c.Query("error_description")).Msg("identity provider refused the login")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, "sign in was cancelled or refused by the identity provider")})
+		return
+	}
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "code and state are required")})
+		return
+	}
+
+	tokens, err := h.service.OIDCCallbackService(ctx, c.Param("provider"), code, state)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Msg("oidc login failed")
+		status := oidcErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			c.AbortWithStatusJSON(status, gin.H{"error": tr(c, "login failed")})
+			return
+		}
+		c.AbortWithStatusJSON(status, gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnknownOIDCProvider):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrOIDCEmailNotVerified):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrOIDCLoginFailed):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
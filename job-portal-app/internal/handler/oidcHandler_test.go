@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_OIDCCallback(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "login refused by the provider",
+			query:              "?error=access_denied&state=state",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"sign in was cancelled or refused by the identity provider"}`,
+		},
+		{
+			name:               "missing code",
+			query:              "?state=state",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"code and state are required"}`,
+		},
+		{
+			name:  "unknown provider",
+			query: "?code=code&state=state",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().OIDCCallbackService(gomock.Any(), "acme", "code", "state").Return(models.TokenPair{}, service.ErrUnknownOIDCProvider).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"unknown identity provider"}`,
+		},
+		{
+			name:  "email not verified by the provider",
+			query: "?code=code&state=state",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().OIDCCallbackService(gomock.Any(), "acme", "code", "state").Return(models.TokenPair{}, service.ErrOIDCEmailNotVerified).Times(1)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"the identity provider did not confirm your email address"}`,
+		},
+		{
+			name:  "success",
+			query: "?code=code&state=state",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().OIDCCallbackService(gomock.Any(), "acme", "code", "state").Return(models.TokenPair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"token":"access","refresh_token":"refresh","expires_in":900}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com/api/oidc/acme/callback"+tt.query, nil)
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "provider", Value: "acme"})
+
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.OIDCCallback(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+)
+
+// twoFactorStatus maps 2FA service errors to an HTTP status.
+func twoFactorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPassword),
+		errors.Is(err, service.ErrInvalidChallenge),
+		errors.Is(err, service.ErrInvalidTwoFactorCode):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrNoPendingTOTP), errors.Is(err, service.ErrTwoFactorNotEnabled):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// TwoFactorLogin is the second login step for users with 2FA enabled.
+func (h *handler) TwoFactorLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	var loginData models.TwoFactorLoginRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&loginData)
+	if err == nil {
+		err = validator.New().Struct(loginData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide the challenge token and code")})
+		return
+	}
+
+	tokens, err := h.service.VerifyTwoFactorLoginService(ctx, loginData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(twoFactorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// EnrolTwoFactor starts TOTP enrolment and returns the secret and provisioning URI.
+func (h *handler) EnrolTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	var passwordData models.PasswordConfirmation
+	err := json.NewDecoder(c.Request.Body).Decode(&passwordData)
+	if err == nil {
+		err = validator.New().Struct(passwordData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide your password")})
+		return
+	}
+
+	enrolment, err := h.service.EnrolTOTPService(ctx, actor.UserID, passwordData.Password)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(twoFactorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, enrolment)
+}
+
+// ConfirmTwoFactor enables 2FA once the user proves their authenticator works.
+func (h *handler) ConfirmTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	var codeData models.TOTPCodeRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&codeData)
+	if err == nil {
+		err = validator.New().Struct(codeData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide the code from your authenticator")})
+		return
+	}
+
+	codes, err := h.service.ConfirmTOTPService(ctx, actor.UserID, codeData.Code)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(twoFactorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// DisableTwoFactor turns 2FA off after checking the user's password.
+func (h *handler) DisableTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	var passwordData models.PasswordConfirmation
+	err := json.NewDecoder(c.Request.Body).Decode(&passwordData)
+	if err == nil {
+		err = validator.New().Struct(passwordData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide your password")})
+		return
+	}
+
+	err = h.service.DisableTOTPService(ctx, actor.UserID, passwordData.Password)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(twoFactorStatus(err), gin.H{"error": tr(c, err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "two-factor authentication disabled")})
+}
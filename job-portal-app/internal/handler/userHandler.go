@@ -2,13 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
 )
 
 func (h *handler) UserLogin(c *gin.Context) {
@@ -17,7 +20,7 @@ func (h *handler) UserLogin(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
@@ -26,24 +29,134 @@ func (h *handler) UserLogin(c *gin.Context) {
 
 	err := json.NewDecoder(c.Request.Body).Decode(&userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid email and password",
+			"error": tr(c, "please provide valid email and password"),
 		})
 		return
 	}
 
-	token, err := h.service.UserLoginService(ctx, userData)
+	tokens, err := h.service.UserLoginService(ctx, userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			status = http.StatusForbidden
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RefreshToken exchanges a refresh token for a new access and refresh token pair.
+func (h *handler) RefreshToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	var refreshData models.RefreshTokenRequest
+
+	err := json.NewDecoder(c.Request.Body).Decode(&refreshData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a refresh token"),
+		})
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(refreshData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": tr(c, "please provide a refresh token"),
+		})
+		return
+	}
+
+	tokens, err := h.service.RefreshTokenService(ctx, refreshData.RefreshToken)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-	})
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes the access token of the request and the session it belongs to.
+func (h *handler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	err := h.service.LogoutService(ctx, claims)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, "failed to log out"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "logged out")})
+}
+
+// LogoutAll revokes every session of the authenticated user, including the current one.
+func (h *handler) LogoutAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceid).Msg("invalid subject in claims")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+
+	err = h.service.LogoutAllService(ctx, actor.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, "failed to log out"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "all sessions logged out")})
 }
 
 func (h *handler) RegisterUser(c *gin.Context) {
@@ -53,7 +166,7 @@ func (h *handler) RegisterUser(c *gin.Context) {
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
@@ -61,9 +174,9 @@ func (h *handler) RegisterUser(c *gin.Context) {
 
 	err := json.NewDecoder(c.Request.Body).Decode(&userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide all details",
+			"error": tr(c, "please provide all details"),
 		})
 		return
 	}
@@ -71,19 +184,17 @@ func (h *handler) RegisterUser(c *gin.Context) {
 	validate := validator.New()
 	err = validate.Struct(userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide all details",
+			"error": tr(c, "please provide all details"),
 		})
 		return
 	}
 
 	userDetails, err := h.service.RegisterUserService(ctx, userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorBody(c, err))
 		return
 	}
 
@@ -91,13 +202,78 @@ func (h *handler) RegisterUser(c *gin.Context) {
 
 }
 
+// VerifyEmail is the target of the link emailed at registration.
+func (h *handler) VerifyEmail(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "verification token is missing")})
+		return
+	}
+
+	err := h.service.VerifyEmailService(ctx, token)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			status = http.StatusBadRequest
+		}
+		c.AbortWithStatusJSON(status, gin.H{"error": tr(c, err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Email verified successfully")})
+}
+
+// ResendVerificationEmail sends a new verification link to an unverified account.
+func (h *handler) ResendVerificationEmail(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	var resendData models.ResendVerificationRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&resendData)
+	if err == nil {
+		err = validator.New().Struct(resendData)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide a valid email")})
+		return
+	}
+
+	err = h.service.ResendVerificationService(ctx, resendData.Email)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": tr(c, err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "If the email is registered and not verified yet, a new link has been sent to it")})
+}
+
 func (h *handler) ForgotPasswordHandler(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
@@ -105,100 +281,165 @@ func (h *handler) ForgotPasswordHandler(c *gin.Context) {
 	var forgetPasswordData models.ForgetPasswordRequest
 
 	err := json.NewDecoder(c.Request.Body).Decode(&forgetPasswordData)
+	if err == nil {
+		err = validator.New().Struct(forgetPasswordData)
+	}
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
+		log.Error().Err(err).Str("trace id", traceid).Send()
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide a valid email",
+			"error": tr(c, "please provide a valid email"),
 		})
 		return
 	}
 
 	// Call the service method to handle forget password logic
-	_, err = h.service.ForgetPasswordService(ctx, forgetPasswordData)
+	resp, err := h.service.ForgetPasswordService(ctx, forgetPasswordData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrOTPCooldown) {
+			status = http.StatusTooManyRequests
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": tr(c, err.Error()),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Password reset instructions sent to the provided email address.",
-	})
+	resp.Message = tr(c, resp.Message)
+	c.JSON(http.StatusOK, resp)
 }
 
-func (h *handler) UpdatePasswordHandler(c *gin.Context) {
+// VerifyOTPHandler exchanges the emailed OTP for a reset token.
+func (h *handler) VerifyOTPHandler(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
 
-	var updatePasswordData models.ResetPasswordRequest
-	err := json.NewDecoder(c.Request.Body).Decode(&updatePasswordData)
+	var verifyData models.VerifyOTPRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&verifyData)
+	if err == nil {
+		err = validator.New().Struct(verifyData)
+	}
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.JSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide a valid email and OTP")})
 		return
 	}
 
-	// Verify OTP before allowing password update
-	err = h.service.VerifyOTPService(ctx, updatePasswordData.Email, updatePasswordData.OTP)
+	resp, err := h.service.VerifyOTPService(ctx, verifyData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OTP"})
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		switch {
+		case errors.Is(err, service.ErrOTPLocked):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": tr(c, err.Error())})
+		case errors.Is(err, service.ErrInvalidOTP):
+			c.JSON(http.StatusBadRequest, gin.H{"error": tr(c, err.Error())})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": tr(c, "Failed to verify OTP")})
+		}
 		return
 	}
-	// Update the password in the database
-	err = h.service.UpdatePasswordService(ctx, updatePasswordData.Email, updatePasswordData.NewPassword)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdatePasswordHandler sets a new password using the reset token from VerifyOTPHandler.
+func (h *handler) UpdatePasswordHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+
+	var updatePasswordData models.ResetPasswordRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&updatePasswordData)
+	if err == nil {
+		err = validator.New().Struct(updatePasswordData)
+	}
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.JSON(http.StatusBadRequest, gin.H{"error": tr(c, "Invalid request payload")})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+	err = h.service.ResetPasswordService(ctx, updatePasswordData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		switch {
+		case errors.Is(err, service.ErrInvalidResetToken), errors.Is(err, service.ErrPasswordMismatch), errors.Is(err, service.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, errorBody(c, err))
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": tr(c, "Failed to update password")})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Password updated successfully")})
 }
 
-// ChangePasswordHandler handles requests to update the password.
+// ChangePasswordHandler changes the password of the logged in user.
 func (h *handler) ChangePasswordHandler(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceID, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
 		log.Error().Msg("traceid missing from context")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 		})
 		return
 	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
+	actor, err := actorFromClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Msg("invalid subject in token")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, http.StatusText(http.StatusUnauthorized))})
+		return
+	}
 
 	var changePasswordData models.ChangePasswordRequest
-	err := json.NewDecoder(c.Request.Body).Decode(&changePasswordData)
+	err = json.NewDecoder(c.Request.Body).Decode(&changePasswordData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceID)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		log.Error().Err(err).Str("trace id", traceID).Send()
+		c.JSON(http.StatusBadRequest, gin.H{"error": tr(c, "Invalid request payload")})
 		return
 	}
 
-	// Verify old password before allowing the password update
-	err = h.service.VerifyOldPassword(ctx, changePasswordData.Email, changePasswordData.OldPassword)
+	validate := validator.New()
+	err = validate.Struct(changePasswordData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceID)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid old password"})
+		log.Error().Err(err).Str("trace id", traceID).Send()
+		c.JSON(http.StatusBadRequest, gin.H{"error": tr(c, "please provide all details")})
 		return
 	}
 
-	// Update the password in the database
-	err = h.service.UpdatePasswordService(ctx, changePasswordData.Email, changePasswordData.NewPassword)
+	err = h.service.ChangePasswordService(ctx, actor.UserID, claims.SessionID, changePasswordData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceID)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		log.Error().Err(err).Str("trace id", traceID).Send()
+		switch {
+		case errors.Is(err, service.ErrInvalidOldPassword):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": tr(c, "Invalid old password")})
+		case errors.Is(err, service.ErrPasswordMismatch), errors.Is(err, service.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, errorBody(c, err))
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": tr(c, "Failed to update password")})
+		}
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Password updated successfully")})
 }
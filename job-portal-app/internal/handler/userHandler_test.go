@@ -6,8 +6,10 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/mock/gomock"
 	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/service"
@@ -79,6 +81,29 @@ func Test_handler_RegisterUser(t *testing.T) {
 			expectedResponse:   `{"error":"test service error"}`,
 		},
 
+		{
+			name: "password breaks the policy",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"username": "testuser","email": "testuser@example.com","password": "testuser"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+
+				ms.EXPECT().RegisterUserService(c.Request.Context(), gomock.Any()).Return(models.User{}, &service.PasswordError{
+					Field:    "password",
+					Problems: []string{"it must contain a digit", "it must not contain your username"},
+				}).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"password does not meet the password policy","fields":{"password":["it must contain a digit","it must not contain your username"]}}`,
+		},
 		{
 			name: "success",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
@@ -108,7 +133,7 @@ func Test_handler_RegisterUser(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"ID":1,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"username":"sandeep","email":"sandeep@gmail.com"}`,
+			expectedResponse:   `{"ID":1,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"username":"sandeep","email":"sandeep@gmail.com","role":"","email_verified_at":null,"two_factor_enabled":false}`,
 		},
 	}
 	for _, tt := range tests {
@@ -154,12 +179,12 @@ func Test_handler_UserLogin(t *testing.T) {
 				ms := service.NewMockUserService(mc)
 
 				// Expect the UserLoginService to be called and return a valid token
-				ms.EXPECT().UserLoginService(c.Request.Context(), gomock.Any()).Return("validtoken", nil).AnyTimes()
+				ms.EXPECT().UserLoginService(c.Request.Context(), gomock.Any()).Return(models.TokenPair{AccessToken: "validtoken", RefreshToken: "refresh", ExpiresIn: 900}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"token":"validtoken"}`,
+			expectedResponse:   `{"token":"validtoken","refresh_token":"refresh","expires_in":900}`,
 		},
 		{
 			name: "missing trace id",
@@ -195,7 +220,7 @@ func Test_handler_UserLogin(t *testing.T) {
 				ms := service.NewMockUserService(mc)
 
 				// Expect the UserLoginService to be called and return an error
-				ms.EXPECT().UserLoginService(c.Request.Context(), gomock.Any()).Return("", errors.New("test service error")).AnyTimes()
+				ms.EXPECT().UserLoginService(c.Request.Context(), gomock.Any()).Return(models.TokenPair{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -218,3 +243,269 @@ func Test_handler_UserLogin(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_ChangePasswordHandler(t *testing.T) {
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "1"},
+		Role:             models.RoleCandidate,
+		SessionID:        "current-session",
+	}
+	body := `{"old_password":"validpassword","new_password":"newpassword","confirm_password":"newpassword"}`
+	tests := []struct {
+		name               string
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, service.UserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "missing jwt claims",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(body))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name: "missing fields",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(`{"old_password":"validpassword"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, claims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide all details"}`,
+		},
+		{
+			name: "invalid old password",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(body))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, claims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+
+				ms.EXPECT().ChangePasswordService(c.Request.Context(), uint(1), "current-session", gomock.Any()).Return(service.ErrInvalidOldPassword).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Invalid old password"}`,
+		},
+		{
+			name: "confirmation does not match",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(body))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, claims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+
+				ms.EXPECT().ChangePasswordService(c.Request.Context(), uint(1), "current-session", gomock.Any()).Return(service.ErrPasswordMismatch).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"new password and confirmation do not match"}`,
+		},
+		{
+			name: "success",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(body))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, claims)
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := service.NewMockUserService(mc)
+
+				ms.EXPECT().ChangePasswordService(c.Request.Context(), uint(1), "current-session", gomock.Any()).Return(nil).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"message":"Password updated successfully"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr, ms := tt.setup()
+
+			h := &handler{
+				service: ms,
+			}
+			h.ChangePasswordHandler(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_VerifyOTPHandler(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "otp is not six digits",
+			body:               `{"email":"sandeep@gmail.com","otp":"1234"}`,
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide a valid email and OTP"}`,
+		},
+		{
+			name: "wrong otp",
+			body: `{"email":"sandeep@gmail.com","otp":"012345"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().VerifyOTPService(gomock.Any(), models.VerifyOTPRequest{Email: "sandeep@gmail.com", OTP: "012345"}).Return(models.VerifyOTPResponse{}, service.ErrInvalidOTP).Times(1)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"invalid or expired OTP"}`,
+		},
+		{
+			name: "locked out",
+			body: `{"email":"sandeep@gmail.com","otp":"012345"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().VerifyOTPService(gomock.Any(), gomock.Any()).Return(models.VerifyOTPResponse{}, service.ErrOTPLocked).Times(1)
+			},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedResponse:   `{"error":"too many failed attempts, please try again later"}`,
+		},
+		{
+			name: "success",
+			body: `{"email":"sandeep@gmail.com","otp":"012345"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().VerifyOTPService(gomock.Any(), gomock.Any()).Return(models.VerifyOTPResponse{ResetToken: "reset"}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"reset_token":"reset"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.VerifyOTPHandler(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ForgotPasswordHandler_localized(t *testing.T) {
+	tests := []struct {
+		name                    string
+		acceptLanguage          string
+		body                    string
+		setup                   func(ms *service.MockUserService)
+		expectedStatusCode      int
+		expectedResponse        string
+		expectedContentLanguage string
+	}{
+		{
+			name:                    "no preference",
+			body:                    `{"email":"not an email"}`,
+			setup:                   func(ms *service.MockUserService) {},
+			expectedStatusCode:      http.StatusBadRequest,
+			expectedResponse:        `{"error":"please provide a valid email"}`,
+			expectedContentLanguage: "en",
+		},
+		{
+			name:                    "hindi error",
+			acceptLanguage:          "hi-IN,hi;q=0.9,en;q=0.8",
+			body:                    `{"email":"not an email"}`,
+			setup:                   func(ms *service.MockUserService) {},
+			expectedStatusCode:      http.StatusBadRequest,
+			expectedResponse:        `{"error":"कृपया मान्य ईमेल दें"}`,
+			expectedContentLanguage: "hi",
+		},
+		{
+			name:           "english preferred over hindi",
+			acceptLanguage: "hi;q=0.5, en-GB",
+			body:           `{"email":"jane@example.com"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ForgetPasswordService(gomock.Any(), gomock.Any()).Return(models.ForgetPasswordResponse{Message: "If the email is registered, an OTP has been sent to it"}, nil).Times(1)
+			},
+			expectedStatusCode:      http.StatusOK,
+			expectedResponse:        `{"message":"If the email is registered, an OTP has been sent to it"}`,
+			expectedContentLanguage: "en",
+		},
+		{
+			name:           "hindi message",
+			acceptLanguage: "fr, hi",
+			body:           `{"email":"jane@example.com"}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ForgetPasswordService(gomock.Any(), gomock.Any()).Return(models.ForgetPasswordResponse{Message: "If the email is registered, an OTP has been sent to it"}, nil).Times(1)
+			},
+			expectedStatusCode:      http.StatusOK,
+			expectedResponse:        `{"message":"यदि यह ईमेल पंजीकृत है, तो उस पर एक OTP भेज दिया गया है"}`,
+			expectedContentLanguage: "hi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{
+				service: ms,
+			}
+			m := &middleware.Mid{}
+			r := gin.New()
+			r.Use(m.Log(), m.Localize())
+			r.POST("/api/forgot-password", h.ForgotPasswordHandler)
+
+			rr := httptest.NewRecorder()
+			httpRequest, _ := http.NewRequest(http.MethodPost, "/api/forgot-password", bytes.NewBufferString(tt.body))
+			httpRequest.Header.Set("Accept-Language", tt.acceptLanguage)
+			r.ServeHTTP(rr, httpRequest)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			assert.Equal(t, tt.expectedContentLanguage, rr.Header().Get("Content-Language"))
+		})
+	}
+}
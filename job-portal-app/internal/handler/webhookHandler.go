@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+)
+
+// webhookStatus maps a webhook service error to a status, 404 for unknown endpoints.
+func webhookStatus(err error) int {
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return http.StatusNotFound
+	}
+	return errorStatus(err, http.StatusBadRequest)
+}
+
+// webhookParams reads the company and webhook ids of the webhook routes.
+func webhookParams(c *gin.Context) (uint64, uint, error) {
+	cid, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.ParseUint(c.Param("webhookID"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cid, uint(id), nil
+}
+
+// CreateWebhookEndpoint registers a webhook endpoint. The signing secret is in the response
+// and cannot be retrieved later.
+func (h *handler) CreateWebhookEndpoint(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	var data models.NewWebhookEndpointRequest
+	err = json.NewDecoder(c.Request.Body).Decode(&data)
+	if err == nil {
+		err = validator.New().Struct(data)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": tr(c, "please provide a url and valid events"),
+		})
+		return
+	}
+
+	endpoint, err := h.service.CreateWebhookEndpointService(ctx, actor, cid, data)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(webhookStatus(err), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+func (h *handler) ListWebhookEndpoints(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	endpoints, err := h.service.ListWebhookEndpointsService(ctx, actor, cid)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(webhookStatus(err), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+func (h *handler) DeleteWebhookEndpoint(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, id, err := webhookParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	err = h.service.DeleteWebhookEndpointService(ctx, actor, cid, id)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(webhookStatus(err), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "webhook endpoint deleted")})
+}
+
+// ListWebhookDeliveries returns the delivery log of an endpoint. The optional limit query
+// parameter caps the number of attempts returned.
+func (h *handler) ListWebhookDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, id, err := webhookParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+	var limit int
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, "limit must be a number")})
+			return
+		}
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveriesService(ctx, actor, cid, id, limit)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(webhookStatus(err), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// SendTestWebhook queues a webhook.test event to the endpoint. It is delivered like any
+// other event and shows up in the delivery log.
+func (h *handler) SendTestWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+		})
+		return
+	}
+	actor, ok := h.actorFromContext(c, traceid)
+	if !ok {
+		return
+	}
+
+	cid, id, err := webhookParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": tr(c, http.StatusText(http.StatusBadRequest))})
+		return
+	}
+
+	err = h.service.SendTestWebhookService(ctx, actor, cid, id)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(webhookStatus(err), gin.H{
+			"error": tr(c, err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": tr(c, "test event queued")})
+}
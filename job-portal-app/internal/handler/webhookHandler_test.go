@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var recruiterClaims = auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter}
+
+func newWebhookTestContext(body string, params ...gin.Param) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(body))
+	ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+	ctx = context.WithValue(ctx, auth.Key, recruiterClaims)
+	c.Request = httpRequest.WithContext(ctx)
+	c.Params = append(c.Params, params...)
+	return c, rr
+}
+
+func Test_handler_CreateWebhookEndpoint(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "unknown event",
+			body:               `{"url":"https://hooks.example.com/jobs","events":["job.deleted"]}`,
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide a url and valid events"}`,
+		},
+		{
+			name:               "no events",
+			body:               `{"url":"https://hooks.example.com/jobs","events":[]}`,
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide a url and valid events"}`,
+		},
+		{
+			name: "not an owner",
+			body: `{"url":"https://hooks.example.com/jobs","events":["job.created"]}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().CreateWebhookEndpointService(gomock.Any(), gomock.Any(), uint64(1), gomock.Any()).Return(models.NewWebhookEndpointResponse{}, service.ErrNotCompanyMember).Times(1)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"you do not have access to this company"}`,
+		},
+		{
+			name: "success",
+			body: `{"url":"https://hooks.example.com/jobs","events":["job.created","application.accepted"]}`,
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().CreateWebhookEndpointService(gomock.Any(), models.Actor{UserID: 1, Role: models.RoleRecruiter}, uint64(1), models.NewWebhookEndpointRequest{
+					URL:    "https://hooks.example.com/jobs",
+					Events: []string{models.WebhookEventJobCreated, models.WebhookEventApplicationAccepted},
+				}).Return(models.NewWebhookEndpointResponse{
+					WebhookEndpoint: models.WebhookEndpoint{ID: 3, CompanyID: 1, URL: "https://hooks.example.com/jobs", Secret: "whsec_abc", Events: []string{models.WebhookEventJobCreated}},
+					Secret:          "whsec_abc",
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse:   `{"id":3,"companyId":1,"url":"https://hooks.example.com/jobs","events":["job.created"],"createdBy":0,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","secret":"whsec_abc"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext(tt.body, gin.Param{Key: "companyID", Value: "1"})
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.CreateWebhookEndpoint(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ListWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name               string
+		webhookID          string
+		query              string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid id",
+			webhookID:          "abc",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Bad Request"}`,
+		},
+		{
+			name:               "invalid limit",
+			webhookID:          "3",
+			query:              "limit=ten",
+			setup:              func(ms *service.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"limit must be a number"}`,
+		},
+		{
+			name:      "unknown endpoint",
+			webhookID: "3",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListWebhookDeliveriesService(gomock.Any(), gomock.Any(), uint64(1), uint(3), 0).Return(nil, service.ErrWebhookNotFound).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"webhook endpoint not found"}`,
+		},
+		{
+			name:      "success",
+			webhookID: "3",
+			query:     "limit=1",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().ListWebhookDeliveriesService(gomock.Any(), gomock.Any(), uint64(1), uint(3), 1).Return([]models.WebhookDelivery{
+					{ID: 8, EndpointID: 3, OutboxID: 12, Event: models.WebhookEventJobCreated, Attempt: 2, StatusCode: 500, Error: "webhook responded with 500 Internal Server Error", DurationMs: 41},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"id":8,"endpointId":3,"outboxId":12,"event":"job.created","attempt":2,"statusCode":500,"error":"webhook responded with 500 Internal Server Error","durationMs":41,"createdAt":"0001-01-01T00:00:00Z"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext("", gin.Param{Key: "companyID", Value: "1"}, gin.Param{Key: "webhookID", Value: tt.webhookID})
+			c.Request.URL.RawQuery = tt.query
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.ListWebhookDeliveries(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_SendTestWebhook(t *testing.T) {
+	tests := []struct {
+		name               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "unknown endpoint",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().SendTestWebhookService(gomock.Any(), gomock.Any(), uint64(1), uint(3)).Return(service.ErrWebhookNotFound).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"webhook endpoint not found"}`,
+		},
+		{
+			name: "error queueing",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().SendTestWebhookService(gomock.Any(), gomock.Any(), uint64(1), uint(3)).Return(errors.New("failed to queue the message")).Times(1)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"failed to queue the message"}`,
+		},
+		{
+			name: "success",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().SendTestWebhookService(gomock.Any(), gomock.Any(), uint64(1), uint(3)).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedResponse:   `{"message":"test event queued"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext("", gin.Param{Key: "companyID", Value: "1"}, gin.Param{Key: "webhookID", Value: "3"})
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.SendTestWebhook(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_CloseJobPosting(t *testing.T) {
+	tests := []struct {
+		name               string
+		setup              func(ms *service.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "already closed",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().CloseJobPostingService(gomock.Any(), gomock.Any(), uint64(9)).Return(models.Jobs{}, service.ErrJobClosed).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"job is already closed"}`,
+		},
+		{
+			name: "not a member",
+			setup: func(ms *service.MockUserService) {
+				ms.EXPECT().CloseJobPostingService(gomock.Any(), gomock.Any(), uint64(9)).Return(models.Jobs{}, service.ErrNotCompanyMember).Times(1)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"you do not have access to this company"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rr := newWebhookTestContext("", gin.Param{Key: "jobID", Value: "9"})
+			mc := gomock.NewController(t)
+			ms := service.NewMockUserService(mc)
+			tt.setup(ms)
+
+			h := &handler{
+				service: ms,
+			}
+			h.CloseJobPosting(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package i18n
+
+// hindi translates the API messages to Hindi.
+var hindi = map[string]string{
+	// generic
+	"Bad Request":                "अमान्य अनुरोध",
+	"Unauthorized":               "अनधिकृत",
+	"Forbidden":                  "वर्जित",
+	"Not Found":                  "नहीं मिला",
+	"Internal Server Error":      "सर्वर में आंतरिक त्रुटि",
+	"Too Many Requests":          "बहुत अधिक अनुरोध",
+	"Invalid request payload":    "अनुरोध का डेटा अमान्य है",
+	"please provide all details": "कृपया सभी विवरण दें",
+	"limit must be a number":     "limit एक संख्या होनी चाहिए",
+	"expected authorization header format: Bearer <token>": "Authorization हेडर का प्रारूप Bearer <token> होना चाहिए",
+	"api keys cannot be used for this endpoint":            "इस endpoint के लिए API कुंजी का उपयोग नहीं किया जा सकता",
+	"api key is missing the %s scope":                      "API कुंजी में %s scope नहीं है",
+	"api key revoked":                                      "API कुंजी रद्द कर दी गई",
+	"invalid api key":                                      "API कुंजी अमान्य है",
+	"too many attempts, please try again later":            "बहुत अधिक प्रयास, कृपया बाद में पुनः प्रयास करें",
+	"too many failed attempts, please try again later":     "बहुत अधिक असफल प्रयास, कृपया बाद में पुनः प्रयास करें",
+	"you do not have access to this company":               "आपके पास इस कंपनी की पहुँच नहीं है",
+	"only admins can do this":                              "यह केवल व्यवस्थापक कर सकते हैं",
+
+	// registration, login and sessions
+	"please provide valid email and password":            "कृपया मान्य ईमेल और पासवर्ड दें",
+	"please provide a valid email":                       "कृपया मान्य ईमेल दें",
+	"please provide a valid username or email":           "कृपया मान्य उपयोगकर्ता नाम या ईमेल दें",
+	"please provide your password":                       "कृपया अपना पासवर्ड दें",
+	"please provide a refresh token":                     "कृपया रिफ्रेश टोकन दें",
+	"login failed":                                       "लॉगिन असफल रहा",
+	"invalid password":                                   "पासवर्ड गलत है",
+	"invalid password provided":                          "दिया गया पासवर्ड गलत है",
+	"invalid refresh token":                              "रिफ्रेश टोकन अमान्य है",
+	"logged out":                                         "लॉग आउट हो गए",
+	"all sessions logged out":                            "सभी सत्रों से लॉग आउट हो गए",
+	"failed to log out":                                  "लॉग आउट असफल रहा",
+	"username is already taken":                          "यह उपयोगकर्ता नाम पहले से लिया जा चुका है",
+	"email is already in use":                            "यह ईमेल पहले से उपयोग में है",
+	"the email address cannot be changed":                "ईमेल पता बदला नहीं जा सकता",
+	"password does not meet the password policy":         "पासवर्ड, पासवर्ड नीति के अनुरूप नहीं है",
+	"please verify your email address first":             "कृपया पहले अपना ईमेल पता सत्यापित करें",
+	"please verify your email address before logging in": "कृपया लॉगिन करने से पहले अपना ईमेल पता सत्यापित करें",
+	"verification token is missing":                      "सत्यापन टोकन नहीं मिला",
+	"invalid or expired verification link":               "सत्यापन लिंक अमान्य है या उसकी अवधि समाप्त हो गई है",
+	"Email verified successfully":                        "ईमेल सफलतापूर्वक सत्यापित हो गया",
+	"failed to send verification email":                  "सत्यापन ईमेल भेजने में असफल",
+	"account deleted":                                    "खाता हटा दिया गया",
+	"could not export the account":                       "खाते का निर्यात नहीं हो सका",
+	"If the email is registered and not verified yet, a new link has been sent to it": "यदि यह ईमेल पंजीकृत है और अभी तक सत्यापित नहीं है, तो उस पर एक नया लिंक भेज दिया गया है",
+
+	// passwords and OTP
+	"If the email is registered, an OTP has been sent to it": "यदि यह ईमेल पंजीकृत है, तो उस पर एक OTP भेज दिया गया है",
+	"please provide a valid email and OTP":                   "कृपया मान्य ईमेल और OTP दें",
+	"please wait before requesting another OTP":              "कृपया दूसरा OTP माँगने से पहले प्रतीक्षा करें",
+	"invalid or expired OTP":                                 "OTP अमान्य है या उसकी अवधि समाप्त हो गई है",
+	"invalid or expired reset token":                         "रीसेट टोकन अमान्य है या उसकी अवधि समाप्त हो गई है",
+	"failed to generate OTP":                                 "OTP बनाने में असफल",
+	"failed to send OTP via email":                           "ईमेल द्वारा OTP भेजने में असफल",
+	"failed to verify OTP":                                   "OTP सत्यापित करने में असफल",
+	"Failed to verify OTP":                                   "OTP सत्यापित करने में असफल",
+	"Password updated successfully":                          "पासवर्ड सफलतापूर्वक बदल दिया गया",
+	"Failed to update password":                              "पासवर्ड बदलने में असफल",
+	"Invalid old password":                                   "पुराना पासवर्ड गलत है",
+	"invalid old password":                                   "पुराना पासवर्ड गलत है",
+	"new password and confirmation do not match":             "नया पासवर्ड और पुष्टि मेल नहीं खाते",
+
+	// two-factor authentication and identity providers
+	"please provide the code from your authenticator":           "कृपया अपने authenticator ऐप का कोड दें",
+	"please provide the challenge token and code":               "कृपया challenge टोकन और कोड दें",
+	"invalid two-factor code":                                   "दो-चरणीय सत्यापन कोड अमान्य है",
+	"invalid or expired login challenge, please log in again":   "लॉगिन challenge अमान्य है या समाप्त हो गया है, कृपया फिर से लॉगिन करें",
+	"start two-factor enrolment first":                          "पहले दो-चरणीय सत्यापन का नामांकन शुरू करें",
+	"two-factor authentication is not enabled":                  "दो-चरणीय सत्यापन सक्षम नहीं है",
+	"two-factor authentication disabled":                        "दो-चरणीय सत्यापन बंद कर दिया गया",
+	"unknown identity provider":                                 "अज्ञात पहचान प्रदाता",
+	"code and state are required":                               "code और state आवश्यक हैं",
+	"could not sign in with the identity provider":              "पहचान प्रदाता से साइन इन नहीं हो सका",
+	"sign in was cancelled or refused by the identity provider": "पहचान प्रदाता ने साइन इन रद्द या अस्वीकार कर दिया",
+	"the identity provider did not confirm your email address":  "पहचान प्रदाता ने आपके ईमेल पते की पुष्टि नहीं की",
+	"invalid or expired login request, please try again":        "लॉगिन अनुरोध अमान्य है या समाप्त हो गया है, कृपया पुनः प्रयास करें",
+
+	// companies, members and administration
+	"please provide valid name, location":    "कृपया मान्य नाम और स्थान दें",
+	"please provide a valid role":            "कृपया मान्य भूमिका दें",
+	"please provide a valid userId and role": "कृपया मान्य userId और भूमिका दें",
+	"admins cannot change their own role":    "व्यवस्थापक अपनी भूमिका नहीं बदल सकते",
+	"user not found":                         "उपयोगकर्ता नहीं मिला",
+	"member removed":                         "सदस्य हटा दिया गया",
+	"a company must keep at least one owner": "कंपनी में कम से कम एक स्वामी होना चाहिए",
+	"you are the last owner of a company, hand it over to another member first":       "आप कंपनी के अंतिम स्वामी हैं, पहले इसे किसी अन्य सदस्य को सौंपें",
+	"please provide a name and valid scopes":                                          "कृपया नाम और मान्य scopes दें",
+	"only recruiters can be added to a company, an admin has to grant the role first": "केवल रिक्रूटर को कंपनी में जोड़ा जा सकता है, पहले किसी व्यवस्थापक को यह भूमिका देनी होगी",
+
+	// jobs and applications
+	"please provide valid job details":                 "कृपया नौकरी का मान्य विवरण दें",
+	"please provide valid application data":            "कृपया आवेदन का मान्य डेटा दें",
+	"application processing error":                     "आवेदन संसाधित करने में त्रुटि",
+	"job not found":                                    "नौकरी नहीं मिली",
+	"job is already closed":                            "यह नौकरी पहले ही बंद हो चुकी है",
+	"candidate not found":                              "उम्मीदवार नहीं मिला",
+	"please provide valid job preferences":             "कृपया मान्य नौकरी प्राथमिकताएँ दें",
+	"no job preference saved":                          "कोई नौकरी प्राथमिकता सहेजी नहीं गई है",
+	"please provide a candidate and an interview time": "कृपया उम्मीदवार और साक्षात्कार का समय दें",
+	"interview invitation sent":                        "साक्षात्कार का निमंत्रण भेज दिया गया",
+
+	// notifications
+	"notification not found":                         "सूचना नहीं मिली",
+	"notification marked read":                       "सूचना पढ़ी गई के रूप में चिह्नित",
+	"before must be a number":                        "before एक संख्या होनी चाहिए",
+	"unread must be true or false":                   "unread का मान true या false होना चाहिए",
+	"Interview invitation":                           "साक्षात्कार का निमंत्रण",
+	"You are invited to interview for %q on %s.":     "आपको %q के लिए %s को साक्षात्कार के लिए आमंत्रित किया गया है।",
+	"New job matching your preferences":              "आपकी प्राथमिकताओं से मेल खाती नई नौकरी",
+	"Application update":                             "आवेदन की जानकारी",
+	"Your application for %q has been shortlisted.":  "%q के लिए आपका आवेदन शॉर्टलिस्ट कर लिया गया है।",
+	"Your application for %q was not taken forward.": "%q के लिए आपका आवेदन आगे नहीं बढ़ाया गया।",
+
+	// webhooks and the outbox
+	"please provide a url and valid events":                 "कृपया url और मान्य events दें",
+	"the webhook url must be an absolute http or https url": "webhook url एक पूर्ण http या https url होना चाहिए",
+	"webhook endpoint not found":                            "webhook endpoint नहीं मिला",
+	"webhook endpoint deleted":                              "webhook endpoint हटा दिया गया",
+	"test event queued":                                     "परीक्षण event कतार में जोड़ दिया गया",
+	"no failed delivery with that id":                       "इस id के साथ कोई असफल डिलीवरी नहीं है",
+	"delivery queued again":                                 "डिलीवरी फिर से कतार में जोड़ दी गई",
+}
@@ -0,0 +1,103 @@
+// Package i18n translates the user facing messages of the API and picks the locale of a
+// request. Messages are written in English in the code and the English text is the key of
+// the catalogs, so a message missing from a catalog is shown in English.
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales.
+const (
+	English = "en"
+	Hindi   = "hi"
+
+	Default = English
+)
+
+// Locales lists the supported locales, the default first.
+var Locales = []string{English, Hindi}
+
+// catalogs holds the translations of every locale but English.
+var catalogs = map[string]map[string]string{
+	Hindi: hindi,
+}
+
+type contextKey struct{}
+
+// Normalize returns the supported locale of a language tag such as "hi-IN", and false when
+// the language is not supported.
+func Normalize(tag string) (string, bool) {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	for _, l := range Locales {
+		if l == lang {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the supported locale the Accept-Language header prefers most, the default
+// when it names none of them.
+func Negotiate(acceptLanguage string) string {
+	type choice struct {
+		locale string
+		q      float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := Normalize(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		choices = append(choices, choice{locale: locale, q: q})
+	}
+	if len(choices) == 0 {
+		return Default
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	return choices[0].locale
+}
+
+// WithLocale returns a copy of ctx carrying the locale messages are translated to.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of ctx, the default when it has none.
+func FromContext(ctx context.Context) string {
+	locale, ok := ctx.Value(contextKey{}).(string)
+	if !ok || locale == "" {
+		return Default
+	}
+	return locale
+}
+
+// T translates an English message to locale.
+func T(locale, msg string) string {
+	if translated, ok := catalogs[locale][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Translate translates an English message to the locale of ctx.
+func Translate(ctx context.Context, msg string) string {
+	return T(FromContext(ctx), msg)
+}
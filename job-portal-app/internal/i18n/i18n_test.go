@@ -0,0 +1,83 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{
+			name: "no header",
+			want: Default,
+		},
+		{
+			name:           "single supported language",
+			acceptLanguage: "hi",
+			want:           Hindi,
+		},
+		{
+			name:           "region of a supported language",
+			acceptLanguage: "hi-IN",
+			want:           Hindi,
+		},
+		{
+			name:           "region with an underscore and upper case",
+			acceptLanguage: "HI_in",
+			want:           Hindi,
+		},
+		{
+			name:           "unsupported language",
+			acceptLanguage: "fr-FR",
+			want:           Default,
+		},
+		{
+			name:           "highest q-value wins",
+			acceptLanguage: "en;q=0.5, hi;q=0.8",
+			want:           Hindi,
+		},
+		{
+			name:           "missing q-value counts as 1",
+			acceptLanguage: "hi;q=0.9, en",
+			want:           English,
+		},
+		{
+			name:           "equal q-values keep the header order",
+			acceptLanguage: "hi-IN;q=0.7, en-GB;q=0.7",
+			want:           Hindi,
+		},
+		{
+			name:           "unsupported languages are skipped",
+			acceptLanguage: "fr-CH, fr;q=0.9, hi;q=0.8, *;q=0.5",
+			want:           Hindi,
+		},
+		{
+			name:           "q=0 excludes a language",
+			acceptLanguage: "hi;q=0, en;q=0.1",
+			want:           English,
+		},
+		{
+			name:           "malformed q-value is skipped",
+			acceptLanguage: "hi;q=high, en;q=0.2",
+			want:           English,
+		},
+		{
+			name:           "wildcard alone",
+			acceptLanguage: "*",
+			want:           Default,
+		},
+		{
+			name:           "wildcard preferred over a supported language",
+			acceptLanguage: "*, hi;q=0.5",
+			want:           Hindi,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FileMailer is for development. It writes every message as an .eml file to a directory,
+// or to the log when no directory is set, instead of sending it.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer returns a FileMailer writing to dir, creating it if needed. An empty dir
+// logs the messages.
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if from == "" {
+		from = "job-portal@localhost"
+	}
+	if dir != "" {
+		err := os.MkdirAll(dir, 0o700)
+		if err != nil {
+			return nil, fmt.Errorf("creating MAIL_DIR: %w", err)
+		}
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	raw, err := msg.encode(m.from, now)
+	if err != nil {
+		return err
+	}
+	if m.dir == "" {
+		log.Info().Strs("to", msg.To).Str("subject", msg.Subject).Str("text", msg.Text).Msg("email not sent, MAIL_DRIVER is log")
+		return nil
+	}
+
+	name := filepath.Join(m.dir, fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), randomHex(4)))
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(raw)
+	return errors.Join(err, f.Close())
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"job-portal-api/internal/config"
+)
+
+// Values of config.MailConfig.Driver.
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+	DriverLog  = "log"
+)
+
+// Message is an email with a plain text and an HTML version of the same body.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the Mailer selected by cfg.Driver.
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg)
+	case DriverFile:
+		return NewFileMailer(cfg.Dir, cfg.From)
+	case DriverLog, "":
+		return NewFileMailer("", cfg.From)
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory keeps the messages it is asked to send, for tests.
+type Memory struct {
+	mu   sync.Mutex
+	sent []Message
+	// Err, when set, is returned by Send instead of keeping the message.
+	Err error
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	_, err := msg.recipients()
+	if err != nil {
+		return err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (m *Memory) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
@@ -0,0 +1,116 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// recipients parses the To addresses of msg, so nothing but an address ends up in the header.
+func (msg Message) recipients() ([]string, error) {
+	if len(msg.To) == 0 {
+		return nil, errors.New("message has no recipients")
+	}
+	addrs := make([]string, 0, len(msg.To))
+	for _, to := range msg.To {
+		addr, err := netmail.ParseAddress(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		addrs = append(addrs, addr.Address)
+	}
+	return addrs, nil
+}
+
+// encode renders msg as a multipart/alternative MIME message.
+func (msg Message) encode(from string, now time.Time) ([]byte, error) {
+	to, err := msg.recipients()
+	if err != nil {
+		return nil, err
+	}
+	sender, err := netmail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, p := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		if p.content == "" {
+			continue
+		}
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		_, err = qp.Write([]byte(p.content))
+		if err != nil {
+			return nil, err
+		}
+		err = qp.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = parts.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&out, "%s: %s\r\n", key, value)
+	}
+	header("From", sender.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(sender.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", parts.Boundary()))
+	out.WriteString("\r\n")
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// mailAddress returns the bare address of an address that may carry a display name.
+func mailAddress(addr string) (string, error) {
+	parsed, err := netmail.ParseAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Address, nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", randomHex(16), domain)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,173 @@
+package mail
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodedMessage is an encoded message read back with the standard library.
+type decodedMessage struct {
+	header netmail.Header
+	// parts are the decoded bodies by content type
+	parts map[string]string
+}
+
+func decode(t *testing.T, raw []byte) decodedMessage {
+	t.Helper()
+	m, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", m.Header.Get("Content-Type"), err)
+	}
+	decoded := decodedMessage{header: m.Header, parts: map[string]string{}}
+	parts := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		p, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		// the reader undoes the quoted-printable encoding
+		body, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("reading the %q part: %v", p.Header.Get("Content-Type"), err)
+		}
+		decoded.parts[p.Header.Get("Content-Type")] = string(body)
+	}
+	return decoded
+}
+
+func TestMessage_encode(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	// long is over the line length limit of 998 characters until it is quoted-printable encoded
+	long := strings.Repeat("a line well over the seventy six characters quoted-printable allows ", 16)
+	tests := []struct {
+		name        string
+		from        string
+		msg         Message
+		wantFrom    string
+		wantTo      string
+		wantSubject string
+		wantParts   map[string]string
+	}{
+		{
+			name:        "ascii",
+			from:        "Job Portal <no-reply@jobs.example.com>",
+			msg:         Message{To: []string{"jane@example.com"}, Subject: "Your code", Text: "Code: 123456", HTML: "<p>Code: <b>123456</b></p>"},
+			wantFrom:    `"Job Portal" <no-reply@jobs.example.com>`,
+			wantTo:      "jane@example.com",
+			wantSubject: "Your code",
+			wantParts: map[string]string{
+				"text/plain; charset=utf-8": "Code: 123456",
+				"text/html; charset=utf-8":  "<p>Code: <b>123456</b></p>",
+			},
+		},
+		{
+			name:        "utf-8 subject and body",
+			from:        "no-reply@jobs.example.com",
+			msg:         Message{To: []string{"Jane Doe <jane@example.com>", "john@example.com"}, Subject: "पासवर्ड रीसेट कोड ✓", Text: "आपका कोड: १२३४५६\r\n" + long},
+			wantFrom:    "<no-reply@jobs.example.com>",
+			wantTo:      "jane@example.com, john@example.com",
+			wantSubject: "पासवर्ड रीसेट कोड ✓",
+			wantParts: map[string]string{
+				"text/plain; charset=utf-8": "आपका कोड: १२३४५६\r\n" + long,
+			},
+		},
+		{
+			name:        "header injection in the subject",
+			from:        "no-reply@jobs.example.com",
+			msg:         Message{To: []string{"jane@example.com"}, Subject: "Hello\r\nBcc: attacker@example.com", Text: "Hi"},
+			wantFrom:    "<no-reply@jobs.example.com>",
+			wantTo:      "jane@example.com",
+			wantSubject: "Hello\r\nBcc: attacker@example.com",
+			wantParts:   map[string]string{"text/plain; charset=utf-8": "Hi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := tt.msg.encode(tt.from, now)
+			if err != nil {
+				t.Fatalf("encode() error = %v", err)
+			}
+			got := decode(t, raw)
+			if from := got.header.Get("From"); from != tt.wantFrom {
+				t.Errorf("From = %q, want %q", from, tt.wantFrom)
+			}
+			if to := got.header.Get("To"); to != tt.wantTo {
+				t.Errorf("To = %q, want %q", to, tt.wantTo)
+			}
+			if bcc := got.header.Get("Bcc"); bcc != "" {
+				t.Errorf("Bcc = %q, want no Bcc header", bcc)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(got.header.Get("Subject"))
+			if err != nil || subject != tt.wantSubject {
+				t.Errorf("Subject = %q, %v, want %q", subject, err, tt.wantSubject)
+			}
+			if date, err := got.header.Date(); err != nil || !date.Equal(now) {
+				t.Errorf("Date = %v, %v, want %v", date, err, now)
+			}
+			if id := got.header.Get("Message-ID"); !strings.HasSuffix(id, "@jobs.example.com>") {
+				t.Errorf("Message-ID = %q", id)
+			}
+			if len(got.parts) != len(tt.wantParts) {
+				t.Errorf("parts = %q, want %q", got.parts, tt.wantParts)
+			}
+			for contentType, want := range tt.wantParts {
+				if got.parts[contentType] != want {
+					t.Errorf("%s part = %q, want %q", contentType, got.parts[contentType], want)
+				}
+			}
+			for _, line := range strings.Split(string(raw), "\r\n") {
+				if len(line) > 998 {
+					t.Errorf("line of %d characters, the limit is 998", len(line))
+				}
+			}
+		})
+	}
+}
+
+func TestMessage_encodeRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   []string
+	}{
+		{
+			name: "no recipients",
+			from: "no-reply@jobs.example.com",
+		},
+		{
+			name: "header injection in a recipient",
+			from: "no-reply@jobs.example.com",
+			to:   []string{"jane@example.com\r\nBcc: attacker@example.com"},
+		},
+		{
+			name: "several addresses in one recipient",
+			from: "no-reply@jobs.example.com",
+			to:   []string{"jane@example.com, attacker@example.com"},
+		},
+		{
+			name: "invalid sender",
+			from: "no-reply@jobs.example.com\r\nBcc: attacker@example.com",
+			to:   []string{"jane@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := Message{To: tt.to, Subject: "Hello", Text: "Hi"}
+			if raw, err := msg.encode(tt.from, time.Now()); err == nil {
+				t.Errorf("encode() = %q, want an error", raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"job-portal-api/internal/config"
+)
+
+// Values of config.MailConfig.TLS.
+const (
+	// TLSStartTLS upgrades a plain connection, usually on port 587.
+	TLSStartTLS = "starttls"
+	// TLSImplicit connects over TLS from the start, usually on port 465.
+	TLSImplicit = "tls"
+	// TLSNone sends in the clear and is only meant for a local relay.
+	TLSNone = "none"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// SMTPMailer sends emails through an SMTP server, opening a connection per message.
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	tls      string
+	from     string
+	// rootCAs verify the certificate of the server, the system roots when nil
+	rootCAs *x509.CertPool
+}
+
+// NewSMTPMailer checks the SMTP settings in cfg.
+func NewSMTPMailer(cfg config.MailConfig) (*SMTPMailer, error) {
+	if cfg.Host == "" || cfg.Port == 0 {
+		return nil, errors.New("the smtp mail driver needs MAIL_HOST and MAIL_PORT")
+	}
+	if cfg.From == "" {
+		return nil, errors.New("the smtp mail driver needs MAIL_FROM")
+	}
+	mode := strings.ToLower(cfg.TLS)
+	switch mode {
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TLS %q, use starttls, tls or none", cfg.TLS)
+	}
+	return &SMTPMailer{
+		host:     cfg.Host,
+		port:     cfg.Port,
+		username: cfg.Username,
+		password: cfg.Password,
+		tls:      mode,
+		from:     cfg.From,
+	}, nil
+}
+
+// Send delivers msg. The context bounds the whole exchange with the server.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	raw, err := msg.encode(m.from, time.Now())
+	if err != nil {
+		return err
+	}
+	to, err := msg.recipients()
+	if err != nil {
+		return err
+	}
+
+	c, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if m.tls == TLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		err = c.StartTLS(m.tlsConfig())
+		if err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if m.username != "" {
+		// PlainAuth refuses to send the password over an unencrypted connection to a remote host
+		err = c.Auth(smtp.PlainAuth("", m.username, m.password, m.host))
+		if err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	sender, _ := mailAddress(m.from)
+	err = c.Mail(sender)
+	if err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		err = c.Rcpt(rcpt)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(raw)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (m *SMTPMailer) tlsConfig() *tls.Config {
+	return &tls.Config{ServerName: m.host, RootCAs: m.rootCAs}
+}
+
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if m.tls == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: m.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connecting to smtp server: %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connecting to smtp server: %w", err)
+	}
+	return c, nil
+}
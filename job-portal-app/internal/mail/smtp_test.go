@@ -0,0 +1,330 @@
+package mail
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"job-portal-api/internal/config"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCertificate returns a self-signed certificate for 127.0.0.1 and a pool that trusts it.
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "smtp test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// smtpSession is what an smtpStub was sent.
+type smtpSession struct {
+	// authTLS is whether the connection was encrypted when the credentials were sent
+	authTLS bool
+	auth    string
+	from    string
+	rcpt    []string
+	data    string
+	quit    bool
+}
+
+// smtpStub is an SMTP server on a local port that accepts one message.
+type smtpStub struct {
+	ln       net.Listener
+	config   *tls.Config
+	implicit bool
+	starttls bool
+	done     chan struct{}
+
+	mu      sync.Mutex
+	session smtpSession
+}
+
+func newSMTPStub(t *testing.T, cert tls.Certificate, implicit, starttls bool) *smtpStub {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &smtpStub{
+		ln:       ln,
+		config:   &tls.Config{Certificates: []tls.Certificate{cert}},
+		implicit: implicit,
+		starttls: starttls,
+		done:     make(chan struct{}),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *smtpStub) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) result() smtpSession {
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { conn.Close() }()
+	encrypted := s.implicit
+	if s.implicit {
+		conn = tls.Server(conn, s.config)
+	}
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 127.0.0.1 ESMTP test")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			lines := []string{"127.0.0.1"}
+			if s.starttls && !encrypted {
+				lines = append(lines, "STARTTLS")
+			}
+			lines = append(lines, "AUTH PLAIN", "8BITMIME")
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				_ = tp.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 ready to start TLS")
+			conn = tls.Server(conn, s.config)
+			tp = textproto.NewConn(conn)
+			encrypted = true
+		case "AUTH":
+			_, initial, _ := strings.Cut(arg, " ")
+			credentials, _ := base64.StdEncoding.DecodeString(initial)
+			s.mu.Lock()
+			s.session.authTLS = encrypted
+			s.session.auth = string(credentials)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			s.mu.Lock()
+			s.session.from = addressOf(arg)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			s.session.rcpt = append(s.session.rcpt, addressOf(arg))
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.session.data = string(data)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			s.mu.Lock()
+			s.session.quit = true
+			s.mu.Unlock()
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// addressOf returns the address in the angle brackets of a MAIL or RCPT argument.
+func addressOf(arg string) string {
+	start, end := strings.Index(arg, "<"), strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	cert, pool := testCertificate(t)
+	tests := []struct {
+		name string
+		tls  string
+		// offerStartTLS is whether the server advertises STARTTLS
+		offerStartTLS bool
+		username      string
+		untrusted     bool
+		wantErr       string
+		wantTLS       bool
+	}{
+		{
+			name:          "starttls",
+			tls:           TLSStartTLS,
+			offerStartTLS: true,
+			username:      "jane",
+			wantTLS:       true,
+		},
+		{
+			name:     "implicit tls",
+			tls:      TLSImplicit,
+			username: "jane",
+			wantTLS:  true,
+		},
+		{
+			name: "no tls to a local relay",
+			tls:  TLSNone,
+		},
+		{
+			name:     "credentials sent in the clear to a local relay",
+			tls:      TLSNone,
+			username: "jane",
+		},
+		{
+			name:    "server without starttls",
+			tls:     TLSStartTLS,
+			wantErr: "does not support STARTTLS",
+		},
+		{
+			name:          "untrusted certificate",
+			tls:           TLSStartTLS,
+			offerStartTLS: true,
+			untrusted:     true,
+			wantErr:       "certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newSMTPStub(t, cert, tt.tls == TLSImplicit, tt.offerStartTLS)
+			m, err := NewSMTPMailer(config.MailConfig{
+				Host:     "127.0.0.1",
+				Port:     stub.port(),
+				Username: tt.username,
+				Password: "secret",
+				TLS:      tt.tls,
+				From:     "Job Portal <no-reply@jobs.example.com>",
+			})
+			if err != nil {
+				t.Fatalf("NewSMTPMailer() error = %v", err)
+			}
+			if !tt.untrusted {
+				m.rootCAs = pool
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			msg := Message{To: []string{"Jane Doe <jane@example.com>", "john@example.com"}, Subject: "Your code", Text: "Code: 123456"}
+			err = m.Send(ctx, msg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Send() error = %v, want %q", err, tt.wantErr)
+				}
+				if got := stub.result(); got.data != "" {
+					t.Errorf("the message was sent after a failure: %q", got.data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			got := stub.result()
+			if tt.username != "" {
+				if got.auth != "\x00jane\x00secret" {
+					t.Errorf("AUTH = %q", got.auth)
+				}
+				if got.authTLS != tt.wantTLS {
+					t.Errorf("credentials sent over TLS = %v, want %v", got.authTLS, tt.wantTLS)
+				}
+			} else if got.auth != "" {
+				t.Errorf("AUTH = %q without credentials", got.auth)
+			}
+			if got.from != "no-reply@jobs.example.com" {
+				t.Errorf("MAIL FROM = %q", got.from)
+			}
+			if strings.Join(got.rcpt, ",") != "jane@example.com,john@example.com" {
+				t.Errorf("RCPT TO = %q", got.rcpt)
+			}
+			decoded := decode(t, []byte(got.data))
+			if decoded.header.Get("Subject") != "Your code" || decoded.parts["text/plain; charset=utf-8"] != "Code: 123456" {
+				t.Errorf("DATA = %q", got.data)
+			}
+			if !got.quit {
+				t.Errorf("the connection was not closed with QUIT")
+			}
+		})
+	}
+}
+
+func TestNewSMTPMailer(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.MailConfig
+	}{
+		{
+			name: "no host",
+			cfg:  config.MailConfig{Port: 587, From: "no-reply@jobs.example.com", TLS: TLSStartTLS},
+		},
+		{
+			name: "no sender",
+			cfg:  config.MailConfig{Host: "smtp.example.com", Port: 587, TLS: TLSStartTLS},
+		},
+		{
+			name: "unknown tls mode",
+			cfg:  config.MailConfig{Host: "smtp.example.com", Port: 587, From: "no-reply@jobs.example.com", TLS: "ssl"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSMTPMailer(tt.cfg); err == nil {
+				t.Errorf("NewSMTPMailer() succeeded")
+			}
+		})
+	}
+}
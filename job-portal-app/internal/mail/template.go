@@ -0,0 +1,92 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"job-portal-api/internal/i18n"
+)
+
+// Names of the templates in the templates directory. Every locale has its own directory
+// with a .txt and a .html file of each template, both defining the subject; the HTML body
+// is rendered inside the layout.html of the locale.
+const (
+	TemplateOTP                = "otp"
+	TemplateVerifyEmail        = "verify_email"
+	TemplateConfirmEmailChange = "confirm_email_change"
+	TemplateEmailChangeNotice  = "email_change_notice"
+)
+
+// OTPData fills TemplateOTP.
+type OTPData struct {
+	Code    string
+	Minutes int
+}
+
+// LinkData fills the templates that send a link.
+type LinkData struct {
+	Link  string
+	Hours int
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+type template struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates holds the parsed templates by locale and name.
+var templates = mustParseTemplates(i18n.Locales, TemplateOTP, TemplateVerifyEmail, TemplateConfirmEmailChange, TemplateEmailChangeNotice)
+
+func mustParseTemplates(locales []string, names ...string) map[string]map[string]template {
+	parsed := make(map[string]map[string]template, len(locales))
+	for _, locale := range locales {
+		dir := "templates/" + locale + "/"
+		layout := htmltemplate.Must(htmltemplate.ParseFS(templateFS, dir+"layout.html"))
+		parsed[locale] = make(map[string]template, len(names))
+		for _, name := range names {
+			html := htmltemplate.Must(htmltemplate.Must(layout.Clone()).ParseFS(templateFS, dir+name+".html"))
+			text := texttemplate.Must(texttemplate.New(name+".txt").ParseFS(templateFS, dir+name+".txt"))
+			parsed[locale][name] = template{text: text, html: html}
+		}
+	}
+	return parsed
+}
+
+// Render fills the named template of the locale with data, falling back to the default
+// locale for an unsupported one. The recipients are left to the caller.
+func Render(locale, name string, data any) (Message, error) {
+	localized, ok := templates[locale]
+	if !ok {
+		localized = templates[i18n.Default]
+	}
+	t, ok := localized[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	err := t.text.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+	err = t.text.Execute(&text, data)
+	if err != nil {
+		return Message{}, err
+	}
+	err = t.html.ExecuteTemplate(&html, "layout.html", data)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
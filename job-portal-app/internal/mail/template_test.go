@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"job-portal-api/internal/i18n"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	link := "https://jobs.example.com/verify?token=abc&email=jane%40example.com"
+	tests := []struct {
+		name string
+		data any
+		// want is in both bodies of every locale, wantHTML in the HTML body only
+		want     []string
+		wantHTML string
+	}{
+		{
+			name: TemplateOTP,
+			data: OTPData{Code: "482913", Minutes: 10},
+			want: []string{"482913", "10"},
+		},
+		{
+			name:     TemplateVerifyEmail,
+			data:     LinkData{Link: link, Hours: 24},
+			want:     []string{"24"},
+			wantHTML: `href="https://jobs.example.com/verify?token=abc&amp;email=jane%40example.com"`,
+		},
+		{
+			name:     TemplateConfirmEmailChange,
+			data:     LinkData{Link: link, Hours: 24},
+			want:     []string{"24"},
+			wantHTML: `href="https://jobs.example.com/verify?token=abc&amp;email=jane%40example.com"`,
+		},
+		{
+			name: TemplateEmailChangeNotice,
+			data: LinkData{Link: link, Hours: 24},
+		},
+	}
+	for _, locale := range i18n.Locales {
+		subjects := map[string]bool{}
+		for _, tt := range tests {
+			t.Run(locale+"/"+tt.name, func(t *testing.T) {
+				msg, err := Render(locale, tt.name, tt.data)
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if msg.Subject == "" || strings.ContainsAny(msg.Subject, "\r\n") {
+					t.Errorf("Subject = %q", msg.Subject)
+				}
+				if subjects[msg.Subject] {
+					t.Errorf("Subject %q is used by another template", msg.Subject)
+				}
+				subjects[msg.Subject] = true
+				if msg.Text == "" || msg.HTML == "" {
+					t.Fatalf("Render() = %+v, want both bodies", msg)
+				}
+				for _, body := range []string{msg.Text, msg.HTML} {
+					if strings.Contains(body, "<no value>") {
+						t.Errorf("body has a missing value: %q", body)
+					}
+					for _, want := range tt.want {
+						if !strings.Contains(body, want) {
+							t.Errorf("body %q does not contain %q", body, want)
+						}
+					}
+				}
+				if tt.wantHTML != "" && !strings.Contains(msg.HTML, tt.wantHTML) {
+					t.Errorf("HTML %q does not contain %q", msg.HTML, tt.wantHTML)
+				}
+				if !strings.Contains(msg.HTML, "<title>"+msg.Subject+"</title>") {
+					t.Errorf("HTML is not rendered in the layout: %q", msg.HTML)
+				}
+				if locale != i18n.Default {
+					fallback, _ := Render(i18n.Default, tt.name, tt.data)
+					if msg.Subject == fallback.Subject || msg.Text == fallback.Text {
+						t.Errorf("Render() is not translated to %s: %+v", locale, msg)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRender_fallbacks(t *testing.T) {
+	want, err := Render(i18n.Default, TemplateOTP, OTPData{Code: "482913", Minutes: 10})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got, err := Render("fr", TemplateOTP, OTPData{Code: "482913", Minutes: 10})
+	if err != nil || got.Subject != want.Subject || got.Text != want.Text || got.HTML != want.HTML {
+		t.Errorf("Render() of an unsupported locale = %+v, %v, want %+v", got, err, want)
+	}
+	if _, err := Render(i18n.Default, "welcome", nil); err == nil {
+		t.Errorf("Render() of an unknown template succeeded")
+	}
+}
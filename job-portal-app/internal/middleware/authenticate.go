@@ -3,15 +3,21 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/i18n"
+	"job-portal-api/internal/models"
 )
 
-func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
+// Authenticate accepts a bearer JWT, or an API key as a bearer token or in the X-API-Key
+// header. API keys are only accepted on routes that list scopes, and the key must hold all
+// of them: m.Authenticate(h.GetAllJobPostings, models.ScopeJobsRead).
+func (m *Mid) Authenticate(next gin.HandlerFunc, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
@@ -20,11 +26,16 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 			log.Error().Msg("trace id is missing")
 
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": http.StatusText(http.StatusInternalServerError),
+				"error": tr(c, http.StatusText(http.StatusInternalServerError)),
 			})
 			return
 		}
 
+		if apiKey := apiKeyFromRequest(c.Request); apiKey != "" {
+			m.authenticateAPIKey(c, next, apiKey, scopes, traceID)
+			return
+		}
+
 		authHeader := c.Request.Header.Get("Authorization")
 
 		// Splitting the Authorization header based on the space character.
@@ -35,14 +46,14 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 			// If the header format doesn't match required format, log and send an error
 			err := errors.New("expected authorization header format: Bearer <token>")
 			log.Error().Err(err).Str("Trace Id", traceID).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tr(c, err.Error())})
 			return
 		}
-		claims, err := m.auth.ValidateToken(parts[1])
+		claims, err := m.auth.ValidateToken(ctx, parts[1])
 		if err != nil {
 			log.Error().Err(err).Str("trace id", traceID).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": http.StatusText(http.StatusInternalServerError),
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": tr(c, http.StatusText(http.StatusUnauthorized)),
 			})
 			return
 		}
@@ -54,7 +65,67 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 		req := c.Request.WithContext(ctx)
 		c.Request = req
 
+		// the locale the user saved wins over the one their client asks for
+		if locale, ok := i18n.Normalize(claims.Locale); ok {
+			setLocale(c, locale)
+		}
+
 		next(c)
 
 	}
 }
+
+func (m *Mid) authenticateAPIKey(c *gin.Context, next gin.HandlerFunc, apiKey string, scopes []string, traceID string) {
+	ctx := c.Request.Context()
+	if len(scopes) == 0 || m.apiKeys == nil {
+		log.Error().Str("Trace Id", traceID).Msg("api key used on a route that does not accept them")
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": tr(c, "api keys cannot be used for this endpoint")})
+		return
+	}
+
+	claims, err := m.apiKeys.ValidateAPIKey(ctx, apiKey)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceID).Send()
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": tr(c, http.StatusText(http.StatusUnauthorized)),
+		})
+		return
+	}
+	for _, scope := range scopes {
+		if !claims.HasScope(scope) {
+			log.Error().Str("Trace Id", traceID).Uint("api key", claims.APIKeyID).Str("scope", scope).Msg("api key is missing a scope")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf(tr(c, "api key is missing the %s scope"), scope)})
+			return
+		}
+	}
+
+	ctx = context.WithValue(ctx, auth.Key, claims)
+	c.Request = c.Request.WithContext(ctx)
+	next(c)
+}
+
+// AuthenticateOptional is Authenticate for routes that can also be read anonymously.
+// Requests without credentials reach next with no claims in the context; requests with
+// credentials must pass Authenticate, so a bad token is still rejected.
+func (m *Mid) AuthenticateOptional(next gin.HandlerFunc, scopes ...string) gin.HandlerFunc {
+	authenticate := m.Authenticate(next, scopes...)
+	return func(c *gin.Context) {
+		if apiKeyFromRequest(c.Request) == "" && c.GetHeader("Authorization") == "" {
+			next(c)
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// apiKeyFromRequest returns the API key of the request, or an empty string if it carries none.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" && strings.HasPrefix(parts[1], models.APIKeyPrefix) {
+		return parts[1]
+	}
+	return ""
+}
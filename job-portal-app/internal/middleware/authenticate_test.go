@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/i18n"
+	"job-portal-api/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// apiKeyStub validates the keys it holds and rejects every other key.
+type apiKeyStub map[string]auth.Claims
+
+func (s apiKeyStub) ValidateAPIKey(ctx context.Context, key string) (auth.Claims, error) {
+	claims, ok := s[key]
+	if !ok {
+		return auth.Claims{}, errors.New("invalid api key")
+	}
+	return claims, nil
+}
+
+var testAPIKeys = apiKeyStub{
+	"jpk_reader": {Role: models.RoleService, APIKeyID: 1, CompanyID: 1, Scopes: []string{models.ScopeJobsRead}},
+}
+
+// testRequest returns a gin context for a request carrying a trace id and the given headers.
+func testRequest(headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
+	for k, v := range headers {
+		httpRequest.Header.Set(k, v)
+	}
+	ctx := context.WithValue(httpRequest.Context(), TraceIDKey, "123")
+	c.Request = httpRequest.WithContext(ctx)
+	return c, rr
+}
+
+// ok is the handler behind the middleware; it echoes the subject it was called for.
+func ok(c *gin.Context) {
+	claims, _ := c.Request.Context().Value(auth.Key).(auth.Claims)
+	c.String(http.StatusOK, claims.Subject)
+}
+
+func TestMid_Authenticate(t *testing.T) {
+	userClaims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleCandidate}
+	tests := []struct {
+		name               string
+		headers            map[string]string
+		locale             string
+		scopes             []string
+		setup              func(mockAuth *auth.MockAuthentication)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "missing authorization header",
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"expected authorization header format: Bearer \u003ctoken\u003e"}`,
+		},
+		{
+			name:    "invalid token",
+			headers: map[string]string{"Authorization": "Bearer bad"},
+			setup: func(mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateToken(gomock.Any(), "bad").Return(auth.Claims{}, errors.New("invalid token")).Times(1)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:    "valid token",
+			headers: map[string]string{"Authorization": "Bearer good"},
+			setup: func(mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateToken(gomock.Any(), "good").Return(userClaims, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "1",
+		},
+		{
+			name:               "api key on a route without scopes",
+			headers:            map[string]string{"X-API-Key": "jpk_reader"},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"api keys cannot be used for this endpoint"}`,
+		},
+		{
+			name:               "unknown api key",
+			headers:            map[string]string{"X-API-Key": "jpk_other"},
+			scopes:             []string{models.ScopeJobsRead},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:               "api key missing a scope",
+			headers:            map[string]string{"Authorization": "Bearer jpk_reader"},
+			scopes:             []string{models.ScopeJobsWrite},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"api key is missing the jobs:write scope"}`,
+		},
+		{
+			name:               "api key missing a scope in hindi",
+			headers:            map[string]string{"X-API-Key": "jpk_reader"},
+			locale:             i18n.Hindi,
+			scopes:             []string{models.ScopeJobsWrite},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"API कुंजी में jobs:write scope नहीं है"}`,
+		},
+		{
+			name:               "api key as a bearer token",
+			headers:            map[string]string{"Authorization": "Bearer jpk_reader"},
+			scopes:             []string{models.ScopeJobsRead},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mc := gomock.NewController(t)
+			mockAuth := auth.NewMockAuthentication(mc)
+			tt.setup(mockAuth)
+			m := &Mid{auth: mockAuth, apiKeys: testAPIKeys}
+			c, rr := testRequest(tt.headers)
+			if tt.locale != "" {
+				c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), tt.locale))
+			}
+
+			m.Authenticate(ok, tt.scopes...)(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func TestMid_AuthenticateOptional(t *testing.T) {
+	userClaims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleCandidate}
+	tests := []struct {
+		name               string
+		headers            map[string]string
+		setup              func(mockAuth *auth.MockAuthentication)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "no credentials",
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "",
+		},
+		{
+			name:    "valid token",
+			headers: map[string]string{"Authorization": "Bearer good"},
+			setup: func(mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateToken(gomock.Any(), "good").Return(userClaims, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "1",
+		},
+		{
+			name:    "invalid token",
+			headers: map[string]string{"Authorization": "Bearer bad"},
+			setup: func(mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateToken(gomock.Any(), "bad").Return(auth.Claims{}, errors.New("invalid token")).Times(1)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:               "malformed authorization header",
+			headers:            map[string]string{"Authorization": "Basic abc"},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"expected authorization header format: Bearer \u003ctoken\u003e"}`,
+		},
+		{
+			name:               "valid api key",
+			headers:            map[string]string{"X-API-Key": "jpk_reader"},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "",
+		},
+		{
+			name:               "unknown api key",
+			headers:            map[string]string{"X-API-Key": "jpk_unknown"},
+			setup:              func(mockAuth *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mc := gomock.NewController(t)
+			mockAuth := auth.NewMockAuthentication(mc)
+			tt.setup(mockAuth)
+			m := &Mid{auth: mockAuth, apiKeys: testAPIKeys}
+			c, rr := testRequest(tt.headers)
+
+			m.AuthenticateOptional(ok, models.ScopeJobsRead)(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestMid_Authorize(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleRecruiter, EmailVerified: true}
+	unverified := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "2"}, Role: models.RoleRecruiter}
+	apiKey := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "apikey:1"}, Role: models.RoleService, APIKeyID: 1, CompanyID: 1}
+	tests := []struct {
+		name                 string
+		claims               *auth.Claims
+		roles                []string
+		requireVerifiedEmail bool
+		expectedStatusCode   int
+		expectedResponse     string
+	}{
+		{
+			name:               "not authenticated",
+			roles:              []string{models.RoleRecruiter},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:               "role allowed",
+			claims:             &recruiter,
+			roles:              []string{models.RoleAdmin, models.RoleRecruiter},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "1",
+		},
+		{
+			name:               "role not allowed",
+			claims:             &recruiter,
+			roles:              []string{models.RoleAdmin},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"Forbidden"}`,
+		},
+		{
+			name:                 "email not verified",
+			claims:               &unverified,
+			roles:                []string{models.RoleRecruiter},
+			requireVerifiedEmail: true,
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponse:     `{"error":"please verify your email address first"}`,
+		},
+		{
+			name:               "email verification not required",
+			claims:             &unverified,
+			roles:              []string{models.RoleRecruiter},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "2",
+		},
+		{
+			name:               "api key on a route for users only",
+			claims:             &apiKey,
+			roles:              []string{models.RoleAdmin, models.RoleRecruiter},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"Forbidden"}`,
+		},
+		{
+			name:                 "api key on a route open to keys",
+			claims:               &apiKey,
+			roles:                []string{models.RoleAdmin, models.RoleRecruiter, models.RoleService},
+			requireVerifiedEmail: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponse:     "apikey:1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			m := &Mid{requireVerifiedEmail: tt.requireVerifiedEmail}
+			c, rr := testRequest(nil)
+			if tt.claims != nil {
+				c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), auth.Key, *tt.claims))
+			}
+
+			m.Authorize(ok, tt.roles...)(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
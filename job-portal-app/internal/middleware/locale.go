@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"job-portal-api/internal/i18n"
+)
+
+// Localize picks the locale of the request from its Accept-Language header. Authenticate
+// replaces it with the locale saved by the user, if they chose one.
+func (m *Mid) Localize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		setLocale(c, i18n.Negotiate(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+func setLocale(c *gin.Context, locale string) {
+	c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+	c.Header("Content-Language", locale)
+}
+
+// tr translates an English API message to the locale of the request.
+func tr(c *gin.Context, msg string) string {
+	return i18n.Translate(c.Request.Context(), msg)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
 )
 
 type key string
@@ -18,6 +19,7 @@ func (m *Mid) Log() gin.HandlerFunc {
 		ctx := c.Request.Context()
 
 		ctx = context.WithValue(ctx, TraceIDKey, uuidStr)
+		ctx = models.WithClient(ctx, models.Client{IP: c.ClientIP(), UserAgent: c.Request.UserAgent(), TraceID: uuidStr})
 
 		c.Request = c.Request.WithContext(ctx)
 
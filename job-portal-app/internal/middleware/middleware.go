@@ -2,14 +2,25 @@ package middleware
 
 import (
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
 )
 
 type Mid struct {
-	auth auth.Authentication
+	auth    auth.Authentication
+	apiKeys auth.APIKeyValidator
+	// limiter and lockouts back Throttle; without a limiter nothing is throttled.
+	limiter  RateLimitStore
+	lockouts LockoutRecorder
+	// requireVerifiedEmail makes Authorize reject users who have not verified their email.
+	requireVerifiedEmail bool
 }
 
-func NewMiddleware(a auth.Authentication) (Mid, error) {
+func NewMiddleware(a auth.Authentication, apiKeys auth.APIKeyValidator, limiter RateLimitStore, lockouts LockoutRecorder, cfg config.AuthConfig) (Mid, error) {
 	return Mid{
-		auth: a,
+		auth:                 a,
+		apiKeys:              apiKeys,
+		limiter:              limiter,
+		lockouts:             lockouts,
+		requireVerifiedEmail: cfg.EmailVerification == config.EmailVerificationPrivileged,
 	}, nil
 }
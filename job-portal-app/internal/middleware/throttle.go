@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+)
+
+// Limits for the throttled endpoints. Every IP gets ipLimit requests per ipWindow. Failed
+// attempts are counted per IP and per account on each endpoint: after failureDelayAfter
+// failures each new attempt has to wait twice as long as the previous one, and after
+// failureLockAfter failures the subject is locked out. An IP's lockouts within strikeTTL last
+// twice as long as the last. An account only gets accountCooldown each time, so that anyone
+// who knows a user's email cannot keep them locked out for long.
+const (
+	ipWindow          = time.Minute
+	ipLimit           = 30
+	failureWindow     = 15 * time.Minute
+	failureDelayAfter = 3
+	failureLockAfter  = 10
+	maxFailureDelay   = 5 * time.Minute
+	baseLockout       = 5 * time.Minute
+	maxLockout        = 24 * time.Hour
+	strikeTTL         = 24 * time.Hour
+	accountCooldown   = 5 * time.Minute
+)
+
+// RateLimitStore keeps the sliding windows and lockouts. It is implemented by the redis client.
+type RateLimitStore interface {
+	SlidingWindowAdd(key string, window time.Duration) (int64, time.Time, error)
+	SlidingWindowCount(key string, window time.Duration) (int64, time.Time, error)
+	ResetWindow(key string) error
+	Lock(key string, ttl time.Duration) error
+	LockedFor(key string) (time.Duration, error)
+	IncrStrikes(key string, ttl time.Duration) (int64, error)
+}
+
+// LockoutRecorder stores lockouts for admins to review.
+type LockoutRecorder interface {
+	RecordLockoutEvent(ctx context.Context, event models.LockoutEvent) error
+}
+
+type throttleSubject struct {
+	kind string // "ip" or "account"
+	key  string
+	// endpoint scopes the failures and lockouts of an account, failing to log in does not
+	// stop the user from resetting their password.
+	endpoint string
+}
+
+func (s throttleSubject) String() string {
+	if s.endpoint != "" {
+		return s.kind + ":" + s.endpoint + ":" + s.key
+	}
+	return s.kind + ":" + s.key
+}
+
+// Throttle protects endpoints that check secrets against brute force. A response of 400, 401
+// or 403 counts as a failed attempt; a successful one clears the account's failures. When
+// the store is unavailable requests are let through rather than locking everyone out.
+// Clients are told apart by gin's ClientIP, so forwarded headers only count when the engine
+// trusts the proxy that sent them.
+func (m *Mid) Throttle(next gin.HandlerFunc, endpoint string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		traceID, ok := ctx.Value(TraceIDKey).(string)
+		if !ok {
+			log.Error().Msg("trace id is missing")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": tr(c, http.StatusText(http.StatusInternalServerError)),
+			})
+			return
+		}
+		if m.limiter == nil {
+			next(c)
+			return
+		}
+
+		subjects := []throttleSubject{{kind: "ip", key: c.ClientIP()}}
+		if account := accountFromBody(c.Request); account != "" {
+			subjects = append(subjects, throttleSubject{kind: "account", key: account, endpoint: endpoint})
+		}
+
+		retryAfter, err := m.throttled(endpoint, subjects)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceID).Msg("rate limiter unavailable")
+		}
+		if retryAfter > 0 {
+			log.Warn().Str("Trace Id", traceID).Str("endpoint", endpoint).Dur("retry after", retryAfter).Msg("request throttled")
+			tooManyRequests(c, retryAfter)
+			return
+		}
+
+		next(c)
+
+		switch status := c.Writer.Status(); {
+		case status == http.StatusBadRequest || status == http.StatusUnauthorized || status == http.StatusForbidden:
+			m.recordFailure(ctx, endpoint, subjects, traceID)
+		case status < http.StatusMultipleChoices:
+			// the IP keeps its failures, it may be trying many accounts
+			for _, subject := range subjects {
+				if subject.kind != "account" {
+					continue
+				}
+				err = m.limiter.ResetWindow("fail:" + subject.String())
+				if err != nil {
+					log.Error().Err(err).Str("Trace Id", traceID).Msg("failed to reset failed attempts")
+				}
+			}
+		}
+	}
+}
+
+// throttled returns how long the caller has to wait, or 0 if the request may go ahead.
+func (m *Mid) throttled(endpoint string, subjects []throttleSubject) (time.Duration, error) {
+	for _, subject := range subjects {
+		locked, err := m.limiter.LockedFor(subject.String())
+		if err != nil {
+			return 0, err
+		}
+		if locked > 0 {
+			return locked, nil
+		}
+	}
+
+	hits, oldest, err := m.limiter.SlidingWindowAdd(fmt.Sprintf("ip:%s:%s", endpoint, subjects[0].key), ipWindow)
+	if err != nil {
+		return 0, err
+	}
+	if hits > ipLimit {
+		return time.Until(oldest.Add(ipWindow)), nil
+	}
+
+	for _, subject := range subjects {
+		failures, latest, err := m.limiter.SlidingWindowCount("fail:"+subject.String(), failureWindow)
+		if err != nil {
+			return 0, err
+		}
+		if failures < failureDelayAfter {
+			continue
+		}
+		if wait := time.Until(latest.Add(failureDelay(failures))); wait > 0 {
+			return wait, nil
+		}
+	}
+	return 0, nil
+}
+
+func (m *Mid) recordFailure(ctx context.Context, endpoint string, subjects []throttleSubject, traceID string) {
+	for _, subject := range subjects {
+		failures, _, err := m.limiter.SlidingWindowAdd("fail:"+subject.String(), failureWindow)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceID).Msg("failed to record failed attempt")
+			return
+		}
+		if failures < failureLockAfter {
+			continue
+		}
+
+		var strikes int64
+		ttl := accountCooldown
+		if subject.kind != "account" {
+			strikes, err = m.limiter.IncrStrikes(subject.String(), strikeTTL)
+			if err != nil {
+				log.Error().Err(err).Str("Trace Id", traceID).Msg("failed to count lockouts")
+				strikes = 1
+			}
+			ttl = lockoutDuration(strikes)
+		}
+		err = m.limiter.Lock(subject.String(), ttl)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceID).Msg("failed to lock out")
+			continue
+		}
+		_ = m.limiter.ResetWindow("fail:" + subject.String())
+
+		log.Warn().Str("Trace Id", traceID).Str("endpoint", endpoint).Str("subject", subject.kind).Dur("ttl", ttl).Msg("locked out after too many failed attempts")
+		if m.lockouts != nil {
+			err = m.lockouts.RecordLockoutEvent(ctx, models.LockoutEvent{
+				Endpoint:    endpoint,
+				Subject:     subject.kind,
+				Key:         subject.key,
+				Failures:    failures,
+				Strikes:     strikes,
+				LockedUntil: time.Now().Add(ttl),
+			})
+			if err != nil {
+				log.Error().Err(err).Str("Trace Id", traceID).Msg("failed to record lockout")
+			}
+		}
+	}
+}
+
+// failureDelay doubles with every failure past failureDelayAfter: 1s, 2s, 4s...
+func failureDelay(failures int64) time.Duration {
+	delay := time.Second << uint(failures-failureDelayAfter)
+	if delay > maxFailureDelay || delay <= 0 {
+		return maxFailureDelay
+	}
+	return delay
+}
+
+func lockoutDuration(strikes int64) time.Duration {
+	ttl := baseLockout << uint(strikes-1)
+	if ttl > maxLockout || ttl <= 0 {
+		return maxLockout
+	}
+	return ttl
+}
+
+func tooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", fmt.Sprint(int(math.Ceil(retryAfter.Seconds()))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error": tr(c, "too many attempts, please try again later"),
+	})
+}
+
+// accountFromBody reads the email from a JSON body and puts the body back for the handler.
+func accountFromBody(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return ""
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(payload.Email))
+}
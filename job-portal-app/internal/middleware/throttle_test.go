@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+	"testing"
+	"time"
+)
+
+type lockoutLog []models.LockoutEvent
+
+func (l *lockoutLog) RecordLockoutEvent(ctx context.Context, event models.LockoutEvent) error {
+	*l = append(*l, event)
+	return nil
+}
+
+func TestMid_recordFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject throttleSubject
+		// want is the lockout after the first and after the second round of failures
+		want []time.Duration
+	}{
+		{
+			name:    "ip lockouts grow",
+			subject: throttleSubject{kind: "ip", key: "198.51.100.1"},
+			want:    []time.Duration{baseLockout, 2 * baseLockout},
+		},
+		{
+			name:    "accounts only get a cooldown",
+			subject: throttleSubject{kind: "account", key: "jane@example.com", endpoint: "login"},
+			want:    []time.Duration{accountCooldown, accountCooldown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := redis.NewMemory()
+			var lockouts lockoutLog
+			m := &Mid{limiter: store, lockouts: &lockouts}
+			for round, want := range tt.want {
+				for i := 0; i < failureLockAfter; i++ {
+					m.recordFailure(context.Background(), "login", []throttleSubject{tt.subject}, "123")
+				}
+				got, err := store.LockedFor(tt.subject.String())
+				if err != nil {
+					t.Fatalf("LockedFor() error = %v", err)
+				}
+				if got > want || got < want-time.Second {
+					t.Errorf("round %d: locked for %v, want %v", round, got, want)
+				}
+			}
+			if len(lockouts) != len(tt.want) || lockouts[0].Key != tt.subject.key {
+				t.Errorf("recorded lockouts = %+v", lockouts)
+			}
+		})
+	}
+}
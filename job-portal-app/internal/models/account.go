@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// UpdateProfileRequest is the payload of PATCH /api/me. Fields left out are not changed.
+// Changing the email needs the current password, if the account has one.
+type UpdateProfileRequest struct {
+	Username *string `json:"username" validate:"omitempty,min=3,max=50"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+	// Locale is one of i18n.Locales, or "" to follow Accept-Language again.
+	Locale          *string `json:"locale" validate:"omitempty,oneof=en hi"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+// DeleteAccountRequest confirms account deletion. Password may be empty for accounts that
+// only sign in through an identity provider.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// AccountExport is everything the portal holds about a user, as returned by
+// GET /api/me/export. Secrets such as password and token hashes are left out.
+type AccountExport struct {
+	ExportedAt         time.Time          `json:"exported_at"`
+	User               User               `json:"user"`
+	Sessions           []ExportedSession  `json:"sessions"`
+	CompanyMemberships []CompanyMember    `json:"company_memberships"`
+	APIKeysCreated     []APIKey           `json:"api_keys_created"`
+	ExternalIdentities []ExternalIdentity `json:"external_identities"`
+	LockoutEvents      []LockoutEvent     `json:"lockout_events"`
+	Notifications      []Notification     `json:"notifications"`
+	JobPreference      *JobPreference     `json:"job_preference"`
+	AuditEvents        []AuditEvent       `json:"audit_events"`
+}
+
+type ExportedSession struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
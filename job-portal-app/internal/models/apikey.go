@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Scopes an API key can be granted. Users are governed by their role instead.
+const (
+	ScopeJobsRead            = "jobs:read"
+	ScopeJobsWrite           = "jobs:write"
+	ScopeApplicationsProcess = "applications:process"
+)
+
+// RoleService is the role of requests authenticated with an API key. No user holds it.
+const RoleService = "service"
+
+// APIKeyPrefix starts every API key, which is how they are told apart from JWTs.
+const APIKeyPrefix = "jpk_"
+
+// ValidScope reports whether scope is one of the known scopes.
+func ValidScope(scope string) bool {
+	switch scope {
+	case ScopeJobsRead, ScopeJobsWrite, ScopeApplicationsProcess:
+		return true
+	}
+	return false
+}
+
+// APIKey lets a machine client call the api without a user's password. A key belongs to a
+// company, or to a service account when CompanyID is nil; service account keys can only be
+// created by admins and only read jobs. Only the SHA-256 hash of the key is stored, the key itself is shown once.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-" gorm:"uniqueIndex"`
+	CompanyID  *uint      `json:"companyId" gorm:"index"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json"`
+	CreatedBy  uint       `json:"createdBy"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type NewAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=jobs:read jobs:write applications:process"`
+	// ExpiresInDays is optional; keys without it do not expire.
+	ExpiresInDays int `json:"expiresInDays" validate:"min=0"`
+}
+
+// NewAPIKeyResponse is the only response that contains the key itself.
+type NewAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
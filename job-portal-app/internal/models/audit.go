@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Types of AuditEvent.
+const (
+	AuditLoginSucceeded       = "login.succeeded"
+	AuditLoginFailed          = "login.failed"
+	AuditTwoFactorChallenged  = "login.2fa_challenged"
+	AuditTwoFactorFailed      = "login.2fa_failed"
+	AuditPasswordChanged      = "password.changed"
+	AuditPasswordChangeFailed = "password.change_failed"
+	AuditPasswordReset        = "password.reset"
+	AuditOTPIssued            = "otp.issued"
+	AuditOTPVerified          = "otp.verified"
+	AuditOTPFailed            = "otp.failed"
+	AuditRoleGranted          = "role.granted"
+	AuditRoleRevoked          = "role.revoked"
+	AuditAPIKeyCreated        = "api_key.created"
+	AuditAPIKeyRevoked        = "api_key.revoked"
+	AuditAPIKeyUsed           = "api_key.used"
+	AuditAPIKeyRejected       = "api_key.rejected"
+	AuditAccountDeleted       = "account.deleted"
+	AuditWebhookCreated       = "webhook.created"
+	AuditWebhookDeleted       = "webhook.deleted"
+	AuditJobCreated           = "job.created"
+	AuditJobClosed            = "job.closed"
+	AuditApplicationsScreened = "applications.screened"
+)
+
+// AuditEvent is an entry of the security audit log. Rows are only ever inserted; the
+// database refuses updates and deletes. ActorUserID or ActorAPIKeyID is who made the
+// request, if they were authenticated, and TargetUserID the account the event is about.
+type AuditEvent struct {
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	EventType     string            `json:"eventType" gorm:"index"`
+	ActorUserID   *uint             `json:"actorUserId" gorm:"index"`
+	ActorAPIKeyID *uint             `json:"actorApiKeyId"`
+	TargetUserID  *uint             `json:"targetUserId" gorm:"index"`
+	IP            string            `json:"ip"`
+	UserAgent     string            `json:"userAgent"`
+	TraceID       string            `json:"traceId"`
+	Details       map[string]string `json:"details,omitempty" gorm:"serializer:json"`
+	CreatedAt     time.Time         `json:"createdAt" gorm:"index"`
+}
+
+// AuditQuery filters the audit log. UserID matches both the actor and the target; zero
+// values do not filter.
+type AuditQuery struct {
+	UserID    uint
+	EventType string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
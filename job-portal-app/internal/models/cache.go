@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// CacheInvalidationChannel is the Postgres channel every write to a cached job or company
+// is announced on. The payload is the CacheInvalidation encoded as JSON.
+const CacheInvalidationChannel = "cache_invalidation"
+
+// Kinds of cached records.
+const (
+	CacheKindJob     = "job"
+	CacheKindCompany = "company"
+)
+
+// CacheInvalidation records that a job or company changed, so that every instance evicts
+// its cached copies. It is written in the same transaction as the change; an instance that
+// missed the notification while its listener was disconnected catches up from this table.
+type CacheInvalidation struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Kind      string    `json:"kind"`
+	EntityID  uint      `json:"entityId"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
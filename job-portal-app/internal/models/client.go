@@ -0,0 +1,24 @@
+package models
+
+import "context"
+
+// Client identifies where a request came from and its trace id, for the audit log. The
+// logging middleware puts it in the request context.
+type Client struct {
+	IP        string
+	UserAgent string
+	TraceID   string
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx carrying the client of the request.
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client of the request, if ctx carries one.
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
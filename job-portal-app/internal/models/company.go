@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -10,6 +12,27 @@ type Company struct {
 	Location string `json:"location" validate:"required"`
 }
 
+// Rights a user can hold inside a company. Owners manage the member list, recruiters post
+// and edit jobs and screen applications.
+const (
+	MemberRoleOwner     = "owner"
+	MemberRoleRecruiter = "recruiter"
+)
+
+// CompanyMember links a user to a company.
+type CompanyMember struct {
+	gorm.Model
+	CompanyID uint   `json:"companyId" gorm:"uniqueIndex:idx_company_member"`
+	UserID    uint   `json:"userId" gorm:"uniqueIndex:idx_company_member"`
+	Role      string `json:"role"`
+}
+
+// NewMemberRequest is the payload owners send to add or update a member of their company.
+type NewMemberRequest struct {
+	UserID uint   `json:"userId" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=owner recruiter"`
+}
+
 type Jobs struct {
 	gorm.Model
 	Company          Company            `gorm:"ForeignKey:cid"`
@@ -26,6 +49,8 @@ type Jobs struct {
 	Qualifications   []Qualifications   `gorm:"many2many:jobs_qualifications;"`
 	Shifts           []Shifts           `gorm:"many2many:jobs_shifts;"`
 	JobTypes         []JobTypes         `gorm:"many2many:jobs_jobTypes;"`
+	// ClosedAt is set once the job stops taking applications.
+	ClosedAt *time.Time `json:"closedAt"`
 }
 
 type NewJobRequest struct {
@@ -57,12 +82,30 @@ type RequestJob struct {
 	QualificationIDs   []uint `json:"qualificationIDs"`
 	ShiftIDs           []uint `json:"shiftIDs"`
 	JobTypeIDs         []uint `json:"jobTypeIDs"`
+	// CandidateID is the account of the applicant, if they have one. They are notified of
+	// the outcome.
+	CandidateID uint `json:"candidateId,omitempty"`
 }
 
 type NewJobResponse struct {
 	ID uint
 }
 
+// CriterionResult is the outcome of checking a single job criterion against an application.
+// Requirement describes what would have to change for the criterion to pass.
+type CriterionResult struct {
+	Criterion   string `json:"criterion"`
+	Passed      bool   `json:"passed"`
+	Requirement string `json:"requirement,omitempty"`
+}
+
+// ApplicationEvaluation is the full evaluation of an application against a job posting.
+type ApplicationEvaluation struct {
+	Jid      uint64            `json:"jid"`
+	Eligible bool              `json:"eligible"`
+	Criteria []CriterionResult `json:"criteria"`
+}
+
 type Locations struct {
 	gorm.Model
 	Name string `json:"name" gorm:"unique"`
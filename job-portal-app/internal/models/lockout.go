@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// LockoutEvent records that an IP address or account was locked out after too many failed
+// attempts, so admins can review brute-force activity. Strikes counts the recent lockouts of
+// an IP; accounts only get a short cooldown and have none.
+type LockoutEvent struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Endpoint    string    `json:"endpoint"`
+	Subject     string    `json:"subject"` // "ip" or "account"
+	Key         string    `json:"key" gorm:"index"`
+	Failures    int64     `json:"failures"`
+	Strikes     int64     `json:"strikes"`
+	LockedUntil time.Time `json:"lockedUntil"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"index"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Types of Notification.
+const (
+	NotificationApplicationStatus = "application_status"
+	NotificationJobMatch          = "job_match"
+	NotificationInterviewInvite   = "interview_invite"
+)
+
+// Notification is an entry of a user's in-app inbox. Data holds the ids the client needs to
+// link to what the notification is about, e.g. "jobId".
+type Notification struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	UserID    uint              `json:"-" gorm:"index:idx_notifications_user,priority:1"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty" gorm:"serializer:json"`
+	ReadAt    *time.Time        `json:"readAt" gorm:"index:idx_notifications_user,priority:2"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// NotificationQuery selects a page of a user's notifications, newest first. Before is the id
+// the page starts below, 0 for the first page. A Limit of 0 returns every notification.
+type NotificationQuery struct {
+	UserID     uint
+	Before     uint
+	UnreadOnly bool
+	Limit      int
+}
+
+// NotificationPage is the response of GET /api/me/notifications. NextBefore is passed as
+// the before parameter to get the next page, it is 0 on the last page.
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int64          `json:"unreadCount"`
+	NextBefore    uint           `json:"nextBefore"`
+}
+
+// JobPreference is what a candidate is looking for, stored in the shape of an application.
+// New jobs are screened against it like an application, and the candidate is notified of
+// the ones it would pass.
+type JobPreference struct {
+	UserID    uint       `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Criteria  RequestJob `json:"criteria" gorm:"serializer:json"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// InterviewInviteRequest invites a candidate with an account to interview for a job.
+type InterviewInviteRequest struct {
+	CandidateID uint      `json:"candidateId" validate:"required"`
+	ScheduledAt time.Time `json:"scheduledAt" validate:"required"`
+	Message     string    `json:"message" validate:"max=2000"`
+}
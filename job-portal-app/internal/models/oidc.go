@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ExternalIdentity links an account at an OpenID Connect provider to a portal user. The
+// provider's subject identifies the account; the email is kept for reference only.
+type ExternalIdentity struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	UserID    uint   `json:"userId" gorm:"index"`
+	Provider  string `json:"provider" gorm:"uniqueIndex:idx_external_identity"`
+	Subject   string `json:"subject" gorm:"uniqueIndex:idx_external_identity"`
+	Email     string `json:"email"`
+	CreatedAt time.Time
+}
+
+// OIDCLoginState is an authorization request that has been sent to a provider and not yet
+// answered. It is keyed by the SHA-256 hash of the state parameter and used once.
+type OIDCLoginState struct {
+	StateHash    string `gorm:"primaryKey"`
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of outbox messages.
+const (
+	OutboxKindEmail   = "email"
+	OutboxKindWebhook = "webhook"
+	// OutboxKindJobMatch sends a new job to the notifications of the candidates it matches.
+	OutboxKindJobMatch = "job_match"
+)
+
+// States of an outbox message. A message that keeps failing ends up dead until an admin
+// replays it.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// OutboxMessage is an email, webhook or job match queued in the same transaction as the
+// change that caused it and delivered by the outbox dispatcher. The payload can hold one-time
+// codes and links, so it is never returned by the api and is dropped once delivered.
+type OutboxMessage struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+	// Kind is one of the OutboxKind* constants.
+	Kind string `json:"kind"`
+	// Destination is the email address or webhook url.
+	Destination string `json:"destination"`
+	// Summary is the email subject or webhook event.
+	Summary       string          `json:"summary"`
+	Payload       json.RawMessage `json:"-" gorm:"type:jsonb"`
+	Status        string          `json:"status" gorm:"index:idx_outbox_due,priority:1"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt" gorm:"index:idx_outbox_due,priority:2"`
+	LastError     string          `json:"lastError"`
+	DeliveredAt   *time.Time      `json:"deliveredAt"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
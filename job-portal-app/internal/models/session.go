@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Session is one login of a user. Every access token carries the session ID as its "sid"
+// claim and every refresh token belongs to exactly one session, so revoking the session
+// logs that device out.
+type Session struct {
+	ID        string `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// RefreshToken is a single-use refresh token. Only the SHA-256 hash of the token is stored.
+// Using a token marks it as used and issues its successor in the same session; presenting
+// a used token again is treated as theft and revokes the whole session.
+type RefreshToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	SessionID string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// RevokedToken is an entry in the access token revocation list, keyed by the token's jti.
+// Entries can be dropped once ExpiresAt has passed.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+// TokenPair is returned on login and on refresh. When the user has 2FA enabled, login
+// returns only a ChallengeToken, which is exchanged for the tokens at /api/login/2fa.
+type TokenPair struct {
+	AccessToken    string `json:"token,omitempty"`
+	RefreshToken   string `json:"refresh_token,omitempty"`
+	ExpiresIn      int    `json:"expires_in,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// RecoveryCode is a one time code that can replace a TOTP code when the authenticator is
+// lost. Only the SHA-256 hash is stored; the codes are shown once when 2FA is confirmed.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	CodeHash  string `gorm:"uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PasswordConfirmation protects enrolling and disabling 2FA. Password may be empty for
+// accounts that only sign in through an identity provider.
+type PasswordConfirmation struct {
+	Password string `json:"password"`
+}
+
+// TOTPEnrolment is returned when enrolment starts. The provisioning URI is what the
+// authenticator app's QR code encodes.
+type TOTPEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorLoginRequest is the second login step. Code is either a TOTP code or an unused
+// recovery code.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
@@ -1,40 +1,105 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type NewUser struct {
 	Username string `json:"username" validate:"required"`
-	Email    string `json:"email" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
 
+// Roles a user can hold. Every new account starts as a candidate; admins grant the others.
+const (
+	RoleAdmin     = "admin"
+	RoleRecruiter = "recruiter"
+	RoleCandidate = "candidate"
+)
+
+// ValidRole reports whether role is one of the known roles.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleRecruiter, RoleCandidate:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	gorm.Model
 	Username     string `json:"username" gorm:"unique"`
 	Email        string `json:"email" gorm:"unique"`
 	PasswordHash string `json:"-"`
+	Role         string `json:"role" gorm:"not null;default:candidate"`
+	// EmailVerifiedAt is set once the user opens the link sent to their email.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// PendingEmail is the address the user asked to change to. It replaces Email once the
+	// link sent to it is opened.
+	PendingEmail *string `json:"pending_email,omitempty"`
+	// Locale is the language of the emails and API messages sent to the user. Empty follows
+	// the Accept-Language header of their requests.
+	Locale string `json:"locale,omitempty"`
+	// TOTPSecret is the active 2FA secret and TOTPPendingSecret one that is being enrolled
+	// but not confirmed yet. TOTPLastStep is the last accepted time step, so a code cannot
+	// be replayed.
+	TwoFactorEnabled  bool   `json:"two_factor_enabled"`
+	TOTPSecret        string `json:"-"`
+	TOTPPendingSecret string `json:"-"`
+	TOTPLastStep      int64  `json:"-"`
+}
+
+// Actor identifies the authenticated user a service call is made on behalf of. Requests
+// made with an API key have no user; they carry the key and, for company keys, its company.
+type Actor struct {
+	UserID    uint
+	Role      string
+	APIKeyID  uint
+	CompanyID uint
+}
+
+// UpdateRoleRequest is the payload admins send to grant a role to a user.
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required"`
 }
 
 type ForgetPasswordRequest struct {
-	Email string `json:"email"`
+	Email string `json:"email" validate:"required,email"`
 }
 
 type ForgetPasswordResponse struct {
 	Message string `json:"message"`
 }
 
+// VerifyOTPRequest exchanges the emailed OTP for a reset token.
+type VerifyOTPRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	OTP   string `json:"otp" validate:"required,len=6,numeric"`
+}
+
+type VerifyOTPResponse struct {
+	ResetToken string `json:"reset_token"`
+}
+
 // ResetPasswordRequest represents the structure of the request for OTP-based password reset.
+// ResetToken comes from a successful OTP verification and can only be used once.
 type ResetPasswordRequest struct {
-	Email           string `json:"email"`
-	OTP             string `json:"otp"`
-	NewPassword     string `json:"new_password"`
-	ConfirmPassword string `json:"confirm_password"`
+	ResetToken      string `json:"reset_token" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
 }
 
 // ChangePasswordRequest represents the request payload for changing the password.
+// The user is always the one the token was issued to.
 type ChangePasswordRequest struct {
-	Email           string `json:"email"`
-	OldPassword     string `json:"old_password"`
-	NewPassword     string `json:"new_password"`
-	ConfirmPassword string `json:"confirm_password"`
+	OldPassword     string `json:"old_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
+}
+
+// ResendVerificationRequest asks for a new email verification link.
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
 }
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// Events a webhook endpoint can subscribe to. WebhookEventTest is only sent by the "send
+// test event" action and needs no subscription.
+const (
+	WebhookEventJobCreated          = "job.created"
+	WebhookEventJobClosed           = "job.closed"
+	WebhookEventApplicationAccepted = "application.accepted"
+	WebhookEventApplicationRejected = "application.rejected"
+	WebhookEventTest                = "webhook.test"
+)
+
+// WebhookSecretPrefix starts every webhook signing secret.
+const WebhookSecretPrefix = "whsec_"
+
+// ValidWebhookEvent reports whether an endpoint can subscribe to event.
+func ValidWebhookEvent(event string) bool {
+	switch event {
+	case WebhookEventJobCreated, WebhookEventJobClosed, WebhookEventApplicationAccepted, WebhookEventApplicationRejected:
+		return true
+	}
+	return false
+}
+
+// WebhookEndpoint is a url of a company that is told about the events it subscribed to.
+// Every delivery is signed with the secret, which is shown once when the endpoint is created.
+type WebhookEndpoint struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CompanyID uint      `json:"companyId" gorm:"index"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events" gorm:"serializer:json"`
+	CreatedBy uint      `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Subscribed reports whether the endpoint receives event.
+func (e WebhookEndpoint) Subscribed(event string) bool {
+	for _, ev := range e.Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one attempt at delivering an event to an endpoint. StatusCode is 0 when
+// no response was received.
+type WebhookDelivery struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EndpointID uint      `json:"endpointId" gorm:"index"`
+	OutboxID   uint      `json:"outboxId"`
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WebhookEvent is the body posted to an endpoint.
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// WebhookJobData is the data of the job events.
+type WebhookJobData struct {
+	JobID       uint       `json:"jobId"`
+	CompanyID   uint       `json:"companyId"`
+	Description string     `json:"description"`
+	ClosedAt    *time.Time `json:"closedAt,omitempty"`
+}
+
+// WebhookApplicationData is the data of the application events.
+type WebhookApplicationData struct {
+	JobID     uint64 `json:"jobId"`
+	CompanyID uint   `json:"companyId"`
+	Name      string `json:"name"`
+}
+
+type NewWebhookEndpointRequest struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=job.created job.closed application.accepted application.rejected"`
+}
+
+// NewWebhookEndpointResponse is the only response that contains the signing secret.
+type NewWebhookEndpointResponse struct {
+	WebhookEndpoint
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,482 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+)
+
+// ErrUnavailable is returned without calling the cache while the circuit breaker is open.
+var ErrUnavailable = errors.New("cache is unavailable")
+
+// States of the circuit breaker.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerStats is a snapshot of the circuit breaker, for health checks and metrics.
+type BreakerStats struct {
+	State string `json:"state"`
+	// Failures is the number of calls that failed in a row.
+	Failures int `json:"consecutive_failures"`
+	// Trips is how many times the breaker opened since the process started.
+	Trips uint64 `json:"trips"`
+	// Rejected is how many calls failed fast because the breaker was open.
+	Rejected uint64 `json:"rejected"`
+	// FallbackCalls is how many calls were served from process memory instead of the cache.
+	FallbackCalls uint64     `json:"fallback_calls"`
+	OpenedAt      *time.Time `json:"opened_at,omitempty"`
+}
+
+// Breaker is a Store that stops calling a failing cache. After threshold failures in a row
+// it opens, and every call fails fast with ErrUnavailable. Once cooldown has passed one call
+// is let through as a probe: if it succeeds the breaker closes, otherwise it stays open for
+// another cooldown.
+//
+// Callers treat the jobs and companies in the cache as optional and read the database when
+// it fails. The OTPs, reset tokens and rate limits cannot be read from anywhere else, so
+// Breaker keeps them in process memory while the cache fails. That memory is not shared
+// between replicas, which is still better than refusing every password reset and turning
+// off brute force protection.
+type Breaker struct {
+	store     Store
+	fallback  *Memory
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	trips     uint64
+	rejected  uint64
+	fallbacks uint64
+	// stale holds the jobs and companies that changed while their cached copies could not
+	// be deleted. They are never read from the cache and are deleted once it is back.
+	staleJobs      map[uint64]bool
+	staleCompanies map[uint64]bool
+}
+
+var _ Store = (*Breaker)(nil)
+
+// NewBreaker wraps store in a circuit breaker configured by cfg.
+func NewBreaker(store Store, cfg config.RedisConfig) *Breaker {
+	threshold := cfg.BreakerThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{
+		store:     store,
+		fallback:  NewMemory(),
+		threshold: threshold,
+		cooldown:  cfg.BreakerCooldown,
+		now:       time.Now,
+		state:     BreakerClosed,
+
+		staleJobs:      map[uint64]bool{},
+		staleCompanies: map[uint64]bool{},
+	}
+}
+
+// Stats returns the current state of the breaker.
+func (b *Breaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := BreakerStats{
+		State:         b.state,
+		Failures:      b.failures,
+		Trips:         b.trips,
+		Rejected:      b.rejected,
+		FallbackCalls: b.fallbacks,
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
+
+// allow reports whether a call may go to the cache.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerClosed:
+		return true
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) >= b.cooldown {
+			b.state = BreakerHalfOpen
+			b.probing = true
+			return true
+		}
+	}
+	// half-open lets only the probe through
+	b.rejected++
+	return false
+}
+
+// record updates the breaker with the outcome of a call that was allowed.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// a caller that gave up says nothing about the cache. A probe that was given up on is
+	// let through again by the next call, as the cooldown has already passed.
+	if errors.Is(err, context.Canceled) {
+		if b.probing {
+			b.probing = false
+			b.state = BreakerOpen
+		}
+		return
+	}
+	if b.probing {
+		b.probing = false
+		if err != nil {
+			b.state = BreakerOpen
+			b.openedAt = b.now()
+			log.Warn().Err(err).Msg("cache probe failed, circuit breaker stays open")
+			return
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		log.Info().Msg("cache recovered, circuit breaker closed")
+		go b.deleteStale()
+		return
+	}
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == BreakerClosed && b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.trips++
+		log.Error().Err(err).Int("failures", b.failures).Msg("cache is failing, circuit breaker opened")
+	}
+}
+
+// do calls fn unless the breaker is open.
+func (b *Breaker) do(fn func() error) error {
+	if !b.allow() {
+		return ErrUnavailable
+	}
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// degraded is called before a call is served from the fallback instead of the cache.
+func (b *Breaker) degraded() {
+	b.mu.Lock()
+	b.fallbacks++
+	b.mu.Unlock()
+}
+
+// Ping checks the cache. While the breaker is open it doubles as the probe, so polling a
+// health check closes the breaker as soon as the cache is back.
+func (b *Breaker) Ping(ctx context.Context) error {
+	return b.do(func() error { return b.store.Ping(ctx) })
+}
+
+// SetJob caches job. A stale copy it replaces is no longer stale.
+func (b *Breaker) SetJob(ctx context.Context, job models.Jobs, ttl time.Duration) error {
+	err := b.do(func() error { return b.store.SetJob(ctx, job, ttl) })
+	if err == nil {
+		b.setStale(b.staleJobs, uint64(job.ID), false)
+	}
+	return err
+}
+
+func (b *Breaker) GetJob(ctx context.Context, jid uint64) (job models.Jobs, ok bool, err error) {
+	if b.isStale(b.staleJobs, jid) {
+		return models.Jobs{}, false, nil
+	}
+	err = b.do(func() error {
+		job, ok, err = b.store.GetJob(ctx, jid)
+		return err
+	})
+	return job, ok, err
+}
+
+// DeleteJob remembers the job when its cached copy cannot be deleted, and deletes it once
+// the cache is back.
+func (b *Breaker) DeleteJob(ctx context.Context, jid uint64) error {
+	err := b.do(func() error { return b.store.DeleteJob(ctx, jid) })
+	b.setStale(b.staleJobs, jid, err != nil)
+	return err
+}
+
+func (b *Breaker) SetCompany(ctx context.Context, company models.Company, ttl time.Duration) error {
+	err := b.do(func() error { return b.store.SetCompany(ctx, company, ttl) })
+	if err == nil {
+		b.setStale(b.staleCompanies, uint64(company.ID), false)
+	}
+	return err
+}
+
+func (b *Breaker) GetCompany(ctx context.Context, cid uint64) (company models.Company, ok bool, err error) {
+	if b.isStale(b.staleCompanies, cid) {
+		return models.Company{}, false, nil
+	}
+	err = b.do(func() error {
+		company, ok, err = b.store.GetCompany(ctx, cid)
+		return err
+	})
+	return company, ok, err
+}
+
+// DeleteCompany is DeleteJob for companies.
+func (b *Breaker) DeleteCompany(ctx context.Context, cid uint64) error {
+	err := b.do(func() error { return b.store.DeleteCompany(ctx, cid) })
+	b.setStale(b.staleCompanies, cid, err != nil)
+	return err
+}
+
+func (b *Breaker) isStale(ids map[uint64]bool, id uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return ids[id]
+}
+
+func (b *Breaker) setStale(ids map[uint64]bool, id uint64, stale bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if stale {
+		ids[id] = true
+	} else {
+		delete(ids, id)
+	}
+}
+
+// deleteStale deletes the cached copies that could not be deleted while the cache was down.
+func (b *Breaker) deleteStale() {
+	ctx := context.Background()
+	b.mu.Lock()
+	jobs := make([]uint64, 0, len(b.staleJobs))
+	for id := range b.staleJobs {
+		jobs = append(jobs, id)
+	}
+	companies := make([]uint64, 0, len(b.staleCompanies))
+	for id := range b.staleCompanies {
+		companies = append(companies, id)
+	}
+	b.mu.Unlock()
+	for _, id := range jobs {
+		_ = b.DeleteJob(ctx, id)
+	}
+	for _, id := range companies {
+		_ = b.DeleteCompany(ctx, id)
+	}
+	if len(jobs)+len(companies) > 0 {
+		log.Info().Int("jobs", len(jobs)).Int("companies", len(companies)).Msg("deleted cache entries that went stale during the outage")
+	}
+}
+
+// The password reset state is written to the fallback when the cache fails. Reads look in
+// the fallback as well when the cache has nothing, so an OTP sent during an outage can
+// still be used after the cache is back.
+
+func (b *Breaker) SaveOTP(ctx context.Context, email, otpHash string, ttl time.Duration) error {
+	err := b.do(func() error { return b.store.SaveOTP(ctx, email, otpHash, ttl) })
+	if err != nil {
+		b.degraded()
+		return b.fallback.SaveOTP(ctx, email, otpHash, ttl)
+	}
+	return nil
+}
+
+func (b *Breaker) GetOTP(ctx context.Context, email string) (otpHash string, err error) {
+	err = b.do(func() error {
+		otpHash, err = b.store.GetOTP(ctx, email)
+		return err
+	})
+	if err != nil || otpHash == "" {
+		if err != nil {
+			b.degraded()
+		}
+		return b.fallback.GetOTP(ctx, email)
+	}
+	return otpHash, nil
+}
+
+// DeleteOTP only fails when the cache is up but could not delete the OTP, since it could
+// then still be used.
+func (b *Breaker) DeleteOTP(ctx context.Context, email string) error {
+	_ = b.fallback.DeleteOTP(ctx, email)
+	err := b.do(func() error { return b.store.DeleteOTP(ctx, email) })
+	if errors.Is(err, ErrUnavailable) {
+		b.degraded()
+		return nil
+	}
+	return err
+}
+
+func (b *Breaker) StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (allowed bool, err error) {
+	err = b.do(func() error {
+		allowed, err = b.store.StartOTPCooldown(ctx, email, cooldown)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return b.fallback.StartOTPCooldown(ctx, email, cooldown)
+	}
+	return allowed, nil
+}
+
+// GetOTPAttempts returns the larger of the two counts, so failures made during an outage
+// still count once the cache is back.
+func (b *Breaker) GetOTPAttempts(ctx context.Context, email string) (attempts int64, err error) {
+	local, _ := b.fallback.GetOTPAttempts(ctx, email)
+	err = b.do(func() error {
+		attempts, err = b.store.GetOTPAttempts(ctx, email)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return local, nil
+	}
+	if local > attempts {
+		return local, nil
+	}
+	return attempts, nil
+}
+
+func (b *Breaker) IncrOTPAttempts(ctx context.Context, email string, window time.Duration) (attempts int64, err error) {
+	err = b.do(func() error {
+		attempts, err = b.store.IncrOTPAttempts(ctx, email, window)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return b.fallback.IncrOTPAttempts(ctx, email, window)
+	}
+	return attempts, nil
+}
+
+func (b *Breaker) ResetOTPAttempts(ctx context.Context, email string) error {
+	_ = b.fallback.ResetOTPAttempts(ctx, email)
+	err := b.do(func() error { return b.store.ResetOTPAttempts(ctx, email) })
+	if err != nil {
+		b.degraded()
+	}
+	return nil
+}
+
+func (b *Breaker) SaveResetToken(ctx context.Context, tokenHash, email string, ttl time.Duration) error {
+	err := b.do(func() error { return b.store.SaveResetToken(ctx, tokenHash, email, ttl) })
+	if err != nil {
+		b.degraded()
+		return b.fallback.SaveResetToken(ctx, tokenHash, email, ttl)
+	}
+	return nil
+}
+
+func (b *Breaker) GetResetToken(ctx context.Context, tokenHash string) (email string, err error) {
+	err = b.do(func() error {
+		email, err = b.store.GetResetToken(ctx, tokenHash)
+		return err
+	})
+	if err != nil || email == "" {
+		if err != nil {
+			b.degraded()
+		}
+		return b.fallback.GetResetToken(ctx, tokenHash)
+	}
+	return email, nil
+}
+
+func (b *Breaker) ConsumeResetToken(ctx context.Context, tokenHash string) (email string, err error) {
+	err = b.do(func() error {
+		email, err = b.store.ConsumeResetToken(ctx, tokenHash)
+		return err
+	})
+	if err != nil || email == "" {
+		if err != nil {
+			b.degraded()
+		}
+		return b.fallback.ConsumeResetToken(ctx, tokenHash)
+	}
+	return email, nil
+}
+
+// The rate limits are kept in the fallback while the cache fails. They start over when the
+// cache is back, which only gives an attacker one fresh window.
+
+func (b *Breaker) SlidingWindowAdd(key string, window time.Duration) (count int64, oldest time.Time, err error) {
+	err = b.do(func() error {
+		count, oldest, err = b.store.SlidingWindowAdd(key, window)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return b.fallback.SlidingWindowAdd(key, window)
+	}
+	return count, oldest, nil
+}
+
+func (b *Breaker) SlidingWindowCount(key string, window time.Duration) (count int64, newest time.Time, err error) {
+	err = b.do(func() error {
+		count, newest, err = b.store.SlidingWindowCount(key, window)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return b.fallback.SlidingWindowCount(key, window)
+	}
+	return count, newest, nil
+}
+
+func (b *Breaker) ResetWindow(key string) error {
+	_ = b.fallback.ResetWindow(key)
+	err := b.do(func() error { return b.store.ResetWindow(key) })
+	if err != nil {
+		b.degraded()
+	}
+	return nil
+}
+
+func (b *Breaker) Lock(key string, ttl time.Duration) error {
+	err := b.do(func() error { return b.store.Lock(key, ttl) })
+	if err != nil {
+		b.degraded()
+		return b.fallback.Lock(key, ttl)
+	}
+	return nil
+}
+
+func (b *Breaker) LockedFor(key string) (ttl time.Duration, err error) {
+	local, _ := b.fallback.LockedFor(key)
+	err = b.do(func() error {
+		ttl, err = b.store.LockedFor(key)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return local, nil
+	}
+	if local > ttl {
+		return local, nil
+	}
+	return ttl, nil
+}
+
+func (b *Breaker) IncrStrikes(key string, ttl time.Duration) (strikes int64, err error) {
+	err = b.do(func() error {
+		strikes, err = b.store.IncrStrikes(key, ttl)
+		return err
+	})
+	if err != nil {
+		b.degraded()
+		return b.fallback.IncrStrikes(key, ttl)
+	}
+	return strikes, nil
+}
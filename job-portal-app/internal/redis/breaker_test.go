@@ -0,0 +1,265 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errConnRefused = errors.New("dial tcp: connection refused")
+
+// failingStore is a Memory whose pings and job calls return err while it is set. It counts
+// the calls that got to it.
+type failingStore struct {
+	*Memory
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (s *failingStore) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *failingStore) result() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.err
+}
+
+func (s *failingStore) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func (s *failingStore) Ping(ctx context.Context) error { return s.result() }
+
+func (s *failingStore) GetJob(ctx context.Context, jid uint64) (models.Jobs, bool, error) {
+	if err := s.result(); err != nil {
+		return models.Jobs{}, false, err
+	}
+	return s.Memory.GetJob(ctx, jid)
+}
+
+func (s *failingStore) DeleteJob(ctx context.Context, jid uint64) error {
+	if err := s.result(); err != nil {
+		return err
+	}
+	return s.Memory.DeleteJob(ctx, jid)
+}
+
+var breakerConfig = config.RedisConfig{BreakerThreshold: 2, BreakerCooldown: time.Minute}
+
+// newTestBreaker returns a breaker in front of a failing store, on a clock the test moves.
+func newTestBreaker(cfg config.RedisConfig) (*Breaker, *failingStore, *testClock) {
+	store := &failingStore{Memory: NewMemory()}
+	clock := newTestClock()
+	b := NewBreaker(store, cfg)
+	b.now = clock.Now
+	return b, store, clock
+}
+
+func TestBreaker_states(t *testing.T) {
+	// step is one ping through the breaker, made after advance with the store returning err
+	type step struct {
+		advance   time.Duration
+		err       error
+		wantErr   error
+		wantState string
+	}
+	tests := []struct {
+		name      string
+		steps     []step
+		wantCalls int
+		wantTrips uint64
+	}{
+		{
+			name: "opens after the threshold",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{wantErr: ErrUnavailable, wantState: BreakerOpen},
+			},
+			wantCalls: 2,
+			wantTrips: 1,
+		},
+		{
+			name: "a success resets the failures",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "stays open during the cooldown",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{advance: time.Minute - time.Second, wantErr: ErrUnavailable, wantState: BreakerOpen},
+			},
+			wantCalls: 2,
+			wantTrips: 1,
+		},
+		{
+			name: "a successful probe closes",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{advance: time.Minute, wantState: BreakerClosed},
+				{wantState: BreakerClosed},
+			},
+			wantCalls: 4,
+			wantTrips: 1,
+		},
+		{
+			name: "a failed probe opens for another cooldown",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{advance: time.Minute, err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{advance: 30 * time.Second, wantErr: ErrUnavailable, wantState: BreakerOpen},
+				{advance: 30 * time.Second, wantState: BreakerClosed},
+			},
+			wantCalls: 4,
+			wantTrips: 1,
+		},
+		{
+			name: "a canceled probe lets the next call probe",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{advance: time.Minute, err: context.Canceled, wantErr: context.Canceled, wantState: BreakerOpen},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+				{advance: time.Minute, wantState: BreakerClosed},
+			},
+			wantCalls: 5,
+			wantTrips: 1,
+		},
+		{
+			name: "canceled calls are neither failures nor successes",
+			steps: []step{
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerClosed},
+				{err: context.Canceled, wantErr: context.Canceled, wantState: BreakerClosed},
+				{err: errConnRefused, wantErr: errConnRefused, wantState: BreakerOpen},
+			},
+			wantCalls: 3,
+			wantTrips: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, store, clock := newTestBreaker(breakerConfig)
+			for i, s := range tt.steps {
+				clock.Advance(s.advance)
+				store.setErr(s.err)
+				err := b.Ping(context.Background())
+				if !errors.Is(err, s.wantErr) {
+					t.Fatalf("step %d: Ping() error = %v, want %v", i, err, s.wantErr)
+				}
+				if state := b.Stats().State; state != s.wantState {
+					t.Fatalf("step %d: state = %q, want %q", i, state, s.wantState)
+				}
+			}
+			if calls := store.Calls(); calls != tt.wantCalls {
+				t.Errorf("the store was called %d times, want %d", calls, tt.wantCalls)
+			}
+			if trips := b.Stats().Trips; trips != tt.wantTrips {
+				t.Errorf("trips = %d, want %d", trips, tt.wantTrips)
+			}
+		})
+	}
+}
+
+// blockingStore is a store whose pings wait until release is closed.
+type blockingStore struct {
+	*Memory
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStore) Ping(ctx context.Context) error {
+	s.started <- struct{}{}
+	<-s.release
+	return nil
+}
+
+func TestBreaker_halfOpenLetsOneProbeThrough(t *testing.T) {
+	store := &blockingStore{Memory: NewMemory(), started: make(chan struct{}, 1), release: make(chan struct{})}
+	clock := newTestClock()
+	b := NewBreaker(store, breakerConfig)
+	b.now = clock.Now
+	b.state = BreakerOpen
+	b.openedAt = clock.Now()
+	clock.Advance(time.Minute)
+
+	probe := make(chan error)
+	go func() { probe <- b.Ping(context.Background()) }()
+	<-store.started
+	if state := b.Stats().State; state != BreakerHalfOpen {
+		t.Fatalf("state during the probe = %q, want %q", state, BreakerHalfOpen)
+	}
+	// other calls fail fast while the probe is in flight
+	if err := b.Ping(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Ping() during the probe error = %v, want %v", err, ErrUnavailable)
+	}
+	close(store.release)
+	if err := <-probe; err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	stats := b.Stats()
+	if stats.State != BreakerClosed || stats.Rejected != 1 {
+		t.Errorf("stats after the probe = %+v", stats)
+	}
+}
+
+func TestBreaker_staleEntries(t *testing.T) {
+	ctx := context.Background()
+	b, store, clock := newTestBreaker(config.RedisConfig{BreakerThreshold: 1, BreakerCooldown: time.Minute})
+	_ = store.Memory.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: 4}, Description: "old"}, time.Hour)
+
+	// the job changes while its cached copy cannot be deleted
+	store.setErr(errConnRefused)
+	if err := b.DeleteJob(ctx, 4); err == nil {
+		t.Fatalf("DeleteJob() during the outage succeeded")
+	}
+	store.setErr(nil)
+	clock.Advance(time.Minute)
+
+	// the stale copy is not read, and the read does not use up the probe
+	calls := store.Calls()
+	if _, ok, _ := b.GetJob(ctx, 4); ok {
+		t.Errorf("GetJob() returned the stale copy")
+	}
+	if store.Calls() != calls {
+		t.Errorf("GetJob() of a stale job called the store")
+	}
+
+	// the probe closes the breaker and the stale copy is deleted in the background
+	if err := b.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for b.isStale(b.staleJobs, 4) {
+		if time.Now().After(deadline) {
+			t.Fatalf("the stale job was not deleted after the cache came back")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, ok, _ := store.Memory.GetJob(ctx, 4); ok {
+		t.Errorf("the stale copy is still in the cache")
+	}
+	if _, ok, _ := b.GetJob(ctx, 4); ok {
+		t.Errorf("GetJob() after the flush found a job")
+	}
+}
@@ -0,0 +1,314 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"job-portal-api/internal/models"
+)
+
+// sweepInterval is how often Memory drops expired entries that were never read again.
+const sweepInterval = time.Minute
+
+// Memory implements Store in the process, for development and tests. Entries expire like
+// they do in Redis, but nothing is shared between processes, so it is not suitable for
+// more than one replica.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	windows   map[string]memoryWindow
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time // zero for entries that never expire
+}
+
+// memoryWindow holds the hits of a sliding rate limiting window.
+type memoryWindow struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries: map[string]memoryEntry{},
+		windows: map[string]memoryWindow{},
+		now:     time.Now,
+	}
+}
+
+// Ping always succeeds, the memory is always there.
+func (m *Memory) Ping(ctx context.Context) error { return nil }
+
+// get returns the value of key if it is set and not expired. m.mu must be held.
+func (m *Memory) get(key string) (string, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !e.expiresAt.IsZero() && !m.now().Before(e.expiresAt) {
+		delete(m.entries, key)
+		return "", false
+	}
+	return e.value, true
+}
+
+// set stores value under key for ttl, or forever when ttl is 0. m.mu must be held.
+func (m *Memory) set(key, value string, ttl time.Duration) {
+	now := m.now()
+	if now.After(m.nextSweep) {
+		m.sweep(now)
+		m.nextSweep = now.Add(sweepInterval)
+	}
+	e := memoryEntry{value: value}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+	m.entries[key] = e
+}
+
+// incr adds one to the counter under key. A new counter expires after ttl. m.mu must be held.
+func (m *Memory) incr(key string, ttl time.Duration) (int64, error) {
+	value, ok := m.get(key)
+	if !ok {
+		m.set(key, "1", ttl)
+		return 1, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of %q is not a counter", key)
+	}
+	n++
+	// the counter keeps its expiry
+	e := m.entries[key]
+	e.value = strconv.FormatInt(n, 10)
+	m.entries[key] = e
+	return n, nil
+}
+
+func (m *Memory) sweep(now time.Time) {
+	for key, e := range m.entries {
+		if !e.expiresAt.IsZero() && !now.Before(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	for key, w := range m.windows {
+		if len(w.hits) == 0 || !now.Before(w.hits[len(w.hits)-1].Add(w.window)) {
+			delete(m.windows, key)
+		}
+	}
+}
+
+func (m *Memory) SetJob(ctx context.Context, job models.Jobs, ttl time.Duration) error {
+	return m.setJSON(jobKey(uint64(job.ID)), job, ttl)
+}
+
+func (m *Memory) GetJob(ctx context.Context, jid uint64) (models.Jobs, bool, error) {
+	var job models.Jobs
+	ok, err := m.getJSON(jobKey(jid), &job)
+	return job, ok, err
+}
+
+func (m *Memory) DeleteJob(ctx context.Context, jid uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, jobKey(jid))
+	return nil
+}
+
+func (m *Memory) SetCompany(ctx context.Context, company models.Company, ttl time.Duration) error {
+	return m.setJSON(companyKey(uint64(company.ID)), company, ttl)
+}
+
+func (m *Memory) GetCompany(ctx context.Context, cid uint64) (models.Company, bool, error) {
+	var company models.Company
+	ok, err := m.getJSON(companyKey(cid), &company)
+	return company, ok, err
+}
+
+func (m *Memory) DeleteCompany(ctx context.Context, cid uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, companyKey(cid))
+	return nil
+}
+
+// setJSON stores v encoded, so callers never share a cached value.
+func (m *Memory) setJSON(key string, v interface{}, ttl time.Duration) error {
+	val, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(key, string(val), ttl)
+	return nil
+}
+
+// getJSON decodes the value of key into v and reports whether the key was set.
+func (m *Memory) getJSON(key string, v interface{}) (bool, error) {
+	m.mu.Lock()
+	value, ok := m.get(key)
+	m.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	err := json.Unmarshal([]byte(value), v)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *Memory) SaveOTP(ctx context.Context, email, otpHash string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(otpKey(email), otpHash, ttl)
+	return nil
+}
+
+func (m *Memory) GetOTP(ctx context.Context, email string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	otpHash, _ := m.get(otpKey(email))
+	return otpHash, nil
+}
+
+func (m *Memory) DeleteOTP(ctx context.Context, email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, otpKey(email))
+	return nil
+}
+
+func (m *Memory) StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.get(otpCooldownKey(email)); ok {
+		return false, nil
+	}
+	m.set(otpCooldownKey(email), "1", cooldown)
+	return true, nil
+}
+
+func (m *Memory) GetOTPAttempts(ctx context.Context, email string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.get(otpAttemptsKey(email))
+	if !ok {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func (m *Memory) IncrOTPAttempts(ctx context.Context, email string, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.incr(otpAttemptsKey(email), window)
+}
+
+func (m *Memory) ResetOTPAttempts(ctx context.Context, email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, otpAttemptsKey(email))
+	return nil
+}
+
+func (m *Memory) SaveResetToken(ctx context.Context, tokenHash, email string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(resetTokenKey(tokenHash), email, ttl)
+	return nil
+}
+
+func (m *Memory) GetResetToken(ctx context.Context, tokenHash string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	email, _ := m.get(resetTokenKey(tokenHash))
+	return email, nil
+}
+
+func (m *Memory) ConsumeResetToken(ctx context.Context, tokenHash string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	email, _ := m.get(resetTokenKey(tokenHash))
+	delete(m.entries, resetTokenKey(tokenHash))
+	return email, nil
+}
+
+// prune drops the hits of key older than window and returns what is left. m.mu must be held.
+func (m *Memory) prune(key string, window time.Duration) memoryWindow {
+	cutoff := m.now().Add(-window)
+	w := m.windows[key]
+	w.window = window
+	i := 0
+	for i < len(w.hits) && !w.hits[i].After(cutoff) {
+		i++
+	}
+	w.hits = w.hits[i:]
+	return w
+}
+
+func (m *Memory) SlidingWindowAdd(key string, window time.Duration) (int64, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key = rateLimitKey(key)
+	w := m.prune(key, window)
+	w.hits = append(w.hits, m.now())
+	m.windows[key] = w
+	return int64(len(w.hits)), w.hits[0], nil
+}
+
+func (m *Memory) SlidingWindowCount(key string, window time.Duration) (int64, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key = rateLimitKey(key)
+	w := m.prune(key, window)
+	if len(w.hits) == 0 {
+		delete(m.windows, key)
+		return 0, time.Time{}, nil
+	}
+	m.windows[key] = w
+	return int64(len(w.hits)), w.hits[len(w.hits)-1], nil
+}
+
+func (m *Memory) ResetWindow(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.windows, rateLimitKey(key))
+	return nil
+}
+
+func (m *Memory) Lock(key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(rateLimitKey("lock:"+key), "1", ttl)
+	return nil
+}
+
+func (m *Memory) LockedFor(key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key = rateLimitKey("lock:" + key)
+	if _, ok := m.get(key); !ok {
+		return 0, nil
+	}
+	expiresAt := m.entries[key].expiresAt
+	if expiresAt.IsZero() {
+		return 0, nil
+	}
+	return expiresAt.Sub(m.now()), nil
+}
+
+func (m *Memory) IncrStrikes(key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.incr(rateLimitKey("strikes:"+key), ttl)
+}
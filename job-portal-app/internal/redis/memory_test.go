@@ -0,0 +1,287 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClock is a clock that only moves when told to.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestMemory returns a Memory on a clock the test moves.
+func newTestMemory() (*Memory, *testClock) {
+	clock := newTestClock()
+	m := NewMemory()
+	m.now = clock.Now
+	return m, clock
+}
+
+func TestMemory_expiry(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		// set stores a value for ttl and get reports whether it is still there
+		set func(m *Memory, ttl time.Duration)
+		get func(m *Memory) bool
+	}{
+		{
+			name: "job",
+			ttl:  time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_ = m.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: 1}}, ttl)
+			},
+			get: func(m *Memory) bool {
+				_, ok, _ := m.GetJob(ctx, 1)
+				return ok
+			},
+		},
+		{
+			name: "company",
+			ttl:  time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_ = m.SetCompany(ctx, models.Company{Model: gorm.Model{ID: 1}}, ttl)
+			},
+			get: func(m *Memory) bool {
+				_, ok, _ := m.GetCompany(ctx, 1)
+				return ok
+			},
+		},
+		{
+			name: "otp",
+			ttl:  5 * time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_ = m.SaveOTP(ctx, "jane@example.com", "hash", ttl)
+			},
+			get: func(m *Memory) bool {
+				otpHash, _ := m.GetOTP(ctx, "jane@example.com")
+				return otpHash != ""
+			},
+		},
+		{
+			name: "otp cooldown",
+			ttl:  time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_, _ = m.StartOTPCooldown(ctx, "jane@example.com", ttl)
+			},
+			get: func(m *Memory) bool {
+				started, _ := m.StartOTPCooldown(ctx, "jane@example.com", time.Minute)
+				return !started
+			},
+		},
+		{
+			name: "otp attempts",
+			ttl:  10 * time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_, _ = m.IncrOTPAttempts(ctx, "jane@example.com", ttl)
+			},
+			get: func(m *Memory) bool {
+				attempts, _ := m.GetOTPAttempts(ctx, "jane@example.com")
+				return attempts > 0
+			},
+		},
+		{
+			name: "reset token",
+			ttl:  15 * time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_ = m.SaveResetToken(ctx, "token", "jane@example.com", ttl)
+			},
+			get: func(m *Memory) bool {
+				email, _ := m.GetResetToken(ctx, "token")
+				return email != ""
+			},
+		},
+		{
+			name: "lock",
+			ttl:  time.Minute,
+			set: func(m *Memory, ttl time.Duration) {
+				_ = m.Lock("ip:198.51.100.1", ttl)
+			},
+			get: func(m *Memory) bool {
+				lockedFor, _ := m.LockedFor("ip:198.51.100.1")
+				return lockedFor > 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, clock := newTestMemory()
+			tt.set(m, tt.ttl)
+			clock.Advance(tt.ttl - time.Second)
+			if !tt.get(m) {
+				t.Fatalf("the value expired before its ttl")
+			}
+			clock.Advance(time.Second)
+			if tt.get(m) {
+				t.Errorf("the value is still there after its ttl")
+			}
+		})
+	}
+}
+
+func TestMemory_counterKeepsExpiry(t *testing.T) {
+	ctx := context.Background()
+	m, clock := newTestMemory()
+	_, _ = m.IncrOTPAttempts(ctx, "jane@example.com", time.Minute)
+	clock.Advance(40 * time.Second)
+	// a later increment does not push the window back
+	n, _ := m.IncrOTPAttempts(ctx, "jane@example.com", time.Minute)
+	if n != 2 {
+		t.Fatalf("IncrOTPAttempts() = %d, want 2", n)
+	}
+	clock.Advance(20 * time.Second)
+	if attempts, _ := m.GetOTPAttempts(ctx, "jane@example.com"); attempts != 0 {
+		t.Errorf("GetOTPAttempts() after the window = %d, want 0", attempts)
+	}
+}
+
+func TestMemory_slidingWindow(t *testing.T) {
+	m, clock := newTestMemory()
+	for i := 0; i < 3; i++ {
+		_, _, _ = m.SlidingWindowAdd("login", time.Minute)
+		clock.Advance(20 * time.Second)
+	}
+	// the first hit left the window
+	count, _, _ := m.SlidingWindowCount("login", time.Minute)
+	if count != 2 {
+		t.Fatalf("SlidingWindowCount() = %d, want 2", count)
+	}
+	clock.Advance(time.Minute)
+	count, _, _ = m.SlidingWindowCount("login", time.Minute)
+	if count != 0 {
+		t.Errorf("SlidingWindowCount() after the window = %d, want 0", count)
+	}
+}
+
+func TestMemory_sweep(t *testing.T) {
+	ctx := context.Background()
+	m, clock := newTestMemory()
+	_ = m.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: 1}}, time.Minute)
+	_ = m.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: 2}}, 0)
+	_, _, _ = m.SlidingWindowAdd("login", time.Minute)
+
+	// expired entries that are never read again are dropped on a later write
+	clock.Advance(sweepInterval + time.Second)
+	_ = m.SaveOTP(ctx, "jane@example.com", "hash", time.Minute)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[jobKey(1)]; ok {
+		t.Errorf("the expired job was not swept")
+	}
+	if _, ok := m.entries[jobKey(2)]; !ok {
+		t.Errorf("the job without a ttl was swept")
+	}
+	if len(m.windows) != 0 {
+		t.Errorf("the expired rate limiting windows were not swept: %v", m.windows)
+	}
+}
+
+func TestMemory_concurrency(t *testing.T) {
+	ctx := context.Background()
+	const workers, calls = 8, 100
+	tests := []struct {
+		name string
+		// call is made calls times by every worker and reports whether it succeeded
+		call func(m *Memory, worker, i int) bool
+		// count, when set, reads back what the calls left in the store
+		count func(m *Memory) int64
+		want  int64
+	}{
+		{
+			name: "counter",
+			call: func(m *Memory, worker, i int) bool {
+				_, err := m.IncrOTPAttempts(ctx, "jane@example.com", time.Minute)
+				return err == nil
+			},
+			count: func(m *Memory) int64 {
+				attempts, _ := m.GetOTPAttempts(ctx, "jane@example.com")
+				return attempts
+			},
+			want: workers * calls,
+		},
+		{
+			name: "sliding window",
+			call: func(m *Memory, worker, i int) bool {
+				_, _, err := m.SlidingWindowAdd("login", time.Minute)
+				return err == nil
+			},
+			count: func(m *Memory) int64 {
+				count, _, _ := m.SlidingWindowCount("login", time.Minute)
+				return count
+			},
+			want: workers * calls,
+		},
+		{
+			name: "cooldown starts once",
+			call: func(m *Memory, worker, i int) bool {
+				started, _ := m.StartOTPCooldown(ctx, "jane@example.com", time.Minute)
+				return started
+			},
+			want: 1,
+		},
+		{
+			name: "cache writes and reads",
+			call: func(m *Memory, worker, i int) bool {
+				jid := uint64(worker*calls + i + 1)
+				_ = m.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: uint(jid)}, Description: fmt.Sprint(jid)}, time.Minute)
+				job, ok, _ := m.GetJob(ctx, jid)
+				return ok && job.Description == fmt.Sprint(jid)
+			},
+			want: workers * calls,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemory()
+			var (
+				succeeded atomic.Int64
+				wg        sync.WaitGroup
+			)
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < calls; i++ {
+						if tt.call(m, w, i) {
+							succeeded.Add(1)
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+			if got := succeeded.Load(); got != tt.want {
+				t.Errorf("%d calls succeeded, want %d", got, tt.want)
+			}
+			if tt.count != nil {
+				if got := tt.count(m); got != tt.want {
+					t.Errorf("count = %d, want %d", got, tt.want)
+				}
+			}
+		})
+	}
+}
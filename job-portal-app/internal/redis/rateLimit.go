@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/google/uuid"
+)
+
+// Rate limiting state lives under the ratelimit: namespace. Windows are sorted sets of
+// request timestamps, so the count always covers exactly the last window (a sliding window).
+func rateLimitKey(key string) string { return "ratelimit:" + key }
+
+// SlidingWindowAdd records a hit and returns the number of hits in the window, including this
+// one, and the time of the oldest hit still in it.
+func (r *RedisClient) SlidingWindowAdd(key string, window time.Duration) (int64, time.Time, error) {
+	key = rateLimitKey(key)
+	now := time.Now()
+
+	var card *redis.IntCmd
+	var oldest *redis.ZSliceCmd
+	_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+		pipe.ZAdd(key, redis.Z{Score: float64(now.UnixNano()), Member: uuid.NewString()})
+		card = pipe.ZCard(key)
+		oldest = pipe.ZRangeWithScores(key, 0, 0)
+		pipe.Expire(key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return card.Val(), scoreTime(oldest.Val()), nil
+}
+
+// SlidingWindowCount returns the number of hits in the window and the time of the newest one.
+func (r *RedisClient) SlidingWindowCount(key string, window time.Duration) (int64, time.Time, error) {
+	key = rateLimitKey(key)
+	now := time.Now()
+
+	var card *redis.IntCmd
+	var newest *redis.ZSliceCmd
+	_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+		card = pipe.ZCard(key)
+		newest = pipe.ZRevRangeWithScores(key, 0, 0)
+		return nil
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return card.Val(), scoreTime(newest.Val()), nil
+}
+
+// ResetWindow forgets every hit in the window.
+func (r *RedisClient) ResetWindow(key string) error {
+	return r.client.Del(rateLimitKey(key)).Err()
+}
+
+// Lock locks key out for ttl.
+func (r *RedisClient) Lock(key string, ttl time.Duration) error {
+	return r.client.Set(rateLimitKey("lock:"+key), 1, ttl).Err()
+}
+
+// LockedFor returns how long key stays locked out, or 0 if it is not locked.
+func (r *RedisClient) LockedFor(key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(rateLimitKey("lock:" + key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// IncrStrikes counts the lockouts of key within ttl, so repeated lockouts can last longer.
+func (r *RedisClient) IncrStrikes(key string, ttl time.Duration) (int64, error) {
+	return incrWithExpiry.Run(r.client, []string{rateLimitKey("strikes:" + key)}, ttl.Milliseconds()).Int64()
+}
+
+func scoreTime(z []redis.Z) time.Time {
+	if len(z) == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(z[0].Score))
+}
@@ -2,14 +2,72 @@ package redis
 
 import (
 	"context"
+	"fmt"
+	"job-portal-api/internal/config"
 	"job-portal-api/internal/models"
+	"strings"
+	"time"
 )
 
-// Redis interface defines the common Redis operations.
+// Values of config.RedisConfig.Driver.
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+)
+
+// Redis is the cache the service layer keeps short lived state in: copies of jobs and
+// companies, and the password reset OTPs and tokens.
 type Redis interface {
-	SetData(ctx context.Context, jid uint64, jobData models.Jobs) error
-	GetData(ctx context.Context, jid uint64) (string, error)
-	SaveOTPInCache(email, otp string) error
-	GetOTP(email string) (string, error)
-	DeleteOTP(email string) error
+	SetJob(ctx context.Context, job models.Jobs, ttl time.Duration) error
+	// GetJob returns the cached job and whether there was one.
+	GetJob(ctx context.Context, jid uint64) (models.Jobs, bool, error)
+	DeleteJob(ctx context.Context, jid uint64) error
+	SetCompany(ctx context.Context, company models.Company, ttl time.Duration) error
+	// GetCompany returns the cached company and whether there was one.
+	GetCompany(ctx context.Context, cid uint64) (models.Company, bool, error)
+	DeleteCompany(ctx context.Context, cid uint64) error
+	SaveOTP(ctx context.Context, email, otpHash string, ttl time.Duration) error
+	GetOTP(ctx context.Context, email string) (string, error)
+	DeleteOTP(ctx context.Context, email string) error
+	StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error)
+	GetOTPAttempts(ctx context.Context, email string) (int64, error)
+	IncrOTPAttempts(ctx context.Context, email string, window time.Duration) (int64, error)
+	ResetOTPAttempts(ctx context.Context, email string) error
+	SaveResetToken(ctx context.Context, tokenHash, email string, ttl time.Duration) error
+	// GetResetToken returns the email of the reset token without using it up.
+	GetResetToken(ctx context.Context, tokenHash string) (string, error)
+	ConsumeResetToken(ctx context.Context, tokenHash string) (string, error)
+}
+
+// Store is the cache together with the rate limiting state, which is kept in the same place.
+type Store interface {
+	Redis
+	Ping(ctx context.Context) error
+	SlidingWindowAdd(key string, window time.Duration) (int64, time.Time, error)
+	SlidingWindowCount(key string, window time.Duration) (int64, time.Time, error)
+	ResetWindow(key string) error
+	Lock(key string, ttl time.Duration) error
+	LockedFor(key string) (time.Duration, error)
+	IncrStrikes(key string, ttl time.Duration) (int64, error)
+}
+
+var (
+	_ Store = (*RedisClient)(nil)
+	_ Store = (*Memory)(nil)
+	_ Store = (*Tiered)(nil)
+)
+
+// New returns the Store selected by cfg.RedisConfig.Driver, behind a circuit breaker and
+// a process memory tier.
+func New(cfg config.Config) (*Tiered, error) {
+	var store Store
+	switch strings.ToLower(cfg.RedisConfig.Driver) {
+	case DriverRedis, "":
+		store = NewRedisClient(cfg)
+	case DriverMemory:
+		store = NewMemory()
+	default:
+		return nil, fmt.Errorf("unknown CACHE_DRIVER %q", cfg.RedisConfig.Driver)
+	}
+	return NewTiered(NewBreaker(store, cfg.RedisConfig), cfg.RedisConfig.LocalTTL), nil
 }
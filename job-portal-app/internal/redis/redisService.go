@@ -14,7 +14,7 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// RedisClient implements the Redis interface.
+// RedisClient implements Store with a Redis server.
 type RedisClient struct {
 	client *redis.Client
 }
@@ -26,41 +26,89 @@ func NewRedisClient(cfg config.Config) *RedisClient {
 			Addr:     fmt.Sprintf("%s:%s", cfg.RedisConfig.Host, cfg.RedisConfig.Port), // Redis server address
 			Password: cfg.RedisConfig.Password,                                         // No password
 			DB:       cfg.RedisConfig.DB,                                               // Default DB
+			// fail fast, the circuit breaker takes over when the server is unreachable
+			DialTimeout:  cfg.RedisConfig.Timeout,
+			ReadTimeout:  cfg.RedisConfig.Timeout,
+			WriteTimeout: cfg.RedisConfig.Timeout,
+			MaxRetries:   0,
 		}),
 	}
 }
 
-// SetData sets a key-value pair in Redis.
-func (r *RedisClient) SetData(ctx context.Context, jid uint64, jobData models.Jobs) error {
-	jobID := fmt.Sprint(jid)
-	val, err := json.Marshal(jobData)
-	fmt.Println("cache data unmarshaled:::", string(val))
+// Cached records are stored as JSON under <kind>:<version>:<id>. cacheVersion is part of
+// every key, so changing the shape of a cached model only needs a new version: the old
+// entries are never read again and expire on their own.
+const cacheVersion = "v1"
+
+func jobKey(jid uint64) string     { return fmt.Sprintf("job:%s:%d", cacheVersion, jid) }
+func companyKey(cid uint64) string { return fmt.Sprintf("company:%s:%d", cacheVersion, cid) }
+
+// SetJob caches job for ttl.
+func (r *RedisClient) SetJob(ctx context.Context, job models.Jobs, ttl time.Duration) error {
+	return r.setJSON(ctx, jobKey(uint64(job.ID)), job, ttl)
+}
+
+// GetJob returns the cached job, and false when it is not cached.
+func (r *RedisClient) GetJob(ctx context.Context, jid uint64) (models.Jobs, bool, error) {
+	var job models.Jobs
+	ok, err := r.getJSON(ctx, jobKey(jid), &job)
+	return job, ok, err
+}
+
+// DeleteJob drops the cached job, if there is one.
+func (r *RedisClient) DeleteJob(ctx context.Context, jid uint64) error {
+	return r.client.WithContext(ctx).Del(jobKey(jid)).Err()
+}
+
+// SetCompany caches company for ttl.
+func (r *RedisClient) SetCompany(ctx context.Context, company models.Company, ttl time.Duration) error {
+	return r.setJSON(ctx, companyKey(uint64(company.ID)), company, ttl)
+}
+
+// GetCompany returns the cached company, and false when it is not cached.
+func (r *RedisClient) GetCompany(ctx context.Context, cid uint64) (models.Company, bool, error) {
+	var company models.Company
+	ok, err := r.getJSON(ctx, companyKey(cid), &company)
+	return company, ok, err
+}
+
+// DeleteCompany drops the cached company, if there is one.
+func (r *RedisClient) DeleteCompany(ctx context.Context, cid uint64) error {
+	return r.client.WithContext(ctx).Del(companyKey(cid)).Err()
+}
+
+func (r *RedisClient) setJSON(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	val, err := json.Marshal(v)
 	if err != nil {
-		fmt.Println("cache set data unmrashal error:========", err)
 		return err
 	}
-	err = r.client.Set(jobID, string(val), 10*time.Minute).Err()
+	err = r.client.WithContext(ctx).Set(key, val, ttl).Err()
 	if err != nil {
-		log.Error().Err(err).Msg("error while setting job data into cache")
+		log.Error().Err(err).Str("key", key).Msg("error while setting data into cache")
 	}
 	return err
 }
 
-// GetData retrieves the value associated with a key from Redis.
-func (r *RedisClient) GetData(ctx context.Context, jid uint64) (models.Jobs, error) {
-	jobID := fmt.Sprint(jid)
-	value, err := r.client.Get(jobID).Result()
+// getJSON decodes the value of key into v and reports whether the key was set.
+func (r *RedisClient) getJSON(ctx context.Context, key string, v interface{}) (bool, error) {
+	value, err := r.client.WithContext(ctx).Get(key).Bytes()
 	if err == redis.Nil {
-		return models.Jobs{}, fmt.Errorf("job '%s' not found", jobID)
+		return false, nil
 	}
-	var job models.Jobs
-	err = json.Unmarshal([]byte(value), &job)
 	if err != nil {
-		log.Error().Err(err).Msg("error while un-marshaling cache data")
-
-		return models.Jobs{}, err
+		return false, err
+	}
+	err = json.Unmarshal(value, v)
+	if err != nil {
+		log.Error().Err(err).Str("key", key).Msg("error while un-marshaling cache data")
+		return false, err
 	}
-	return job, nil
+	return true, nil
+}
+
+// Ping checks that the Redis server answers.
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.WithContext(ctx).Ping().Err()
 }
 
 // Close closes the Redis client.
@@ -68,36 +116,108 @@ func (r *RedisClient) Close() {
 	_ = r.client.Close()
 }
 
-func (r *RedisClient) SaveOTPInCache(email, otp string) error {
-	// Set the OTP in Redis with a specific expiration time (e.g., 5 minutes)
-	//code, _ := json.Marshal(otp)
+// Password reset state lives under the otp: namespace so it cannot collide with cached jobs.
+func otpKey(email string) string         { return "otp:code:" + email }
+func otpAttemptsKey(email string) string { return "otp:attempts:" + email }
+func otpCooldownKey(email string) string { return "otp:cooldown:" + email }
+func resetTokenKey(hash string) string   { return "otp:reset:" + hash }
+
+// SaveOTP stores the hash of the OTP sent to email until it expires.
+func (r *RedisClient) SaveOTP(ctx context.Context, email, otpHash string, ttl time.Duration) error {
+	return r.client.WithContext(ctx).Set(otpKey(email), otpHash, ttl).Err()
+}
 
-	err := r.client.Set(email, otp, 5*time.Minute).Err()
+// GetOTP returns the stored OTP hash for email, or an empty string if there is none.
+func (r *RedisClient) GetOTP(ctx context.Context, email string) (string, error) {
+	otpHash, err := r.client.WithContext(ctx).Get(otpKey(email)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
 	if err != nil {
-		return err
+		log.Error().Err(err).Msg("error getting OTP from redis")
+		return "", fmt.Errorf("failed to get OTP from Redis: %w", err)
 	}
+	return otpHash, nil
+}
 
+// DeleteOTP removes the OTP for email so it cannot be used again.
+func (r *RedisClient) DeleteOTP(ctx context.Context, email string) error {
+	err := r.client.WithContext(ctx).Del(otpKey(email)).Err()
+	if err != nil {
+		log.Error().Err(err).Msg("error deleting OTP from redis")
+		return fmt.Errorf("failed to delete OTP from Redis: %w", err)
+	}
 	return nil
 }
 
-func (r *RedisClient) GetOTP(email string) (string, error) {
-	otp, err := r.client.Get(email).Result()
-	if err != nil {
-		log.Printf("Error getting OTP from Redis for email %s: %v", email, err)
-		return "", fmt.Errorf("failed to get OTP from Redis: %w", err)
+// StartOTPCooldown reports whether a new OTP may be sent to email. It returns false while
+// the cooldown started by the previous OTP is still running.
+func (r *RedisClient) StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	return r.client.WithContext(ctx).SetNX(otpCooldownKey(email), 1, cooldown).Result()
+}
+
+// GetOTPAttempts returns the number of failed OTP attempts for email.
+func (r *RedisClient) GetOTPAttempts(ctx context.Context, email string) (int64, error) {
+	attempts, err := r.client.WithContext(ctx).Get(otpAttemptsKey(email)).Int64()
+	if err == redis.Nil {
+		return 0, nil
 	}
+	return attempts, err
+}
 
-	log.Printf("OTP retrieved successfully from Redis for email %s", email)
-	return otp, nil
+// IncrOTPAttempts records a failed OTP attempt. The counter expires window after the first
+// failure, which is also how long an email stays locked out.
+func (r *RedisClient) IncrOTPAttempts(ctx context.Context, email string, window time.Duration) (int64, error) {
+	return incrWithExpiry.Run(r.client.WithContext(ctx), []string{otpAttemptsKey(email)}, window.Milliseconds()).Int64()
 }
 
-func (r *RedisClient) DeleteOTP(email string) error {
-	result := r.client.Del(email)
-	if err := result.Err(); err != nil {
-		log.Printf("Error deleting OTP from Redis for email %s: %v", email, err)
-		return fmt.Errorf("failed to delete OTP from Redis: %w", err)
+// incrWithExpiry increments the counter in KEYS[1] and, on the first increment, makes it
+// expire after ARGV[1] milliseconds. Both run in one step, so a failure in between cannot
+// leave a counter that never expires.
+var incrWithExpiry = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// ResetOTPAttempts clears the failed attempts of email.
+func (r *RedisClient) ResetOTPAttempts(ctx context.Context, email string) error {
+	return r.client.WithContext(ctx).Del(otpAttemptsKey(email)).Err()
+}
+
+// SaveResetToken stores the email a password reset token was issued for, keyed by the
+// token's hash.
+func (r *RedisClient) SaveResetToken(ctx context.Context, tokenHash, email string, ttl time.Duration) error {
+	return r.client.WithContext(ctx).Set(resetTokenKey(tokenHash), email, ttl).Err()
+}
+
+// GetResetToken returns the email of the reset token, or an empty string for unknown or
+// expired tokens.
+func (r *RedisClient) GetResetToken(ctx context.Context, tokenHash string) (string, error) {
+	email, err := r.client.WithContext(ctx).Get(resetTokenKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return "", nil
 	}
+	return email, err
+}
 
-	log.Printf("OTP deleted successfully from Redis for email %s", email)
-	return nil
+// ConsumeResetToken returns the email of the reset token and deletes it in the same
+// transaction, so a token can only be used once. It returns an empty string for unknown or
+// expired tokens.
+func (r *RedisClient) ConsumeResetToken(ctx context.Context, tokenHash string) (string, error) {
+	var get *redis.StringCmd
+	_, err := r.client.WithContext(ctx).TxPipelined(func(pipe redis.Pipeliner) error {
+		get = pipe.Get(resetTokenKey(tokenHash))
+		pipe.Del(resetTokenKey(tokenHash))
+		return nil
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return get.Val(), nil
 }
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"job-portal-api/internal/models"
+)
+
+// Tiered keeps the jobs and companies read through it in process memory for a short while,
+// in front of the shared cache, so the hottest ones are served without a round trip. The
+// copies in memory are only dropped on the instance that deletes them, so every instance
+// has to delete a job or company when it changes anywhere; the service layer does that
+// when the change is announced by the database.
+type Tiered struct {
+	*Breaker
+	local *Memory
+	ttl   time.Duration
+}
+
+// NewTiered puts a process memory tier, holding entries for ttl, in front of shared. A ttl
+// of 0 turns the memory tier off.
+func NewTiered(shared *Breaker, ttl time.Duration) *Tiered {
+	return &Tiered{Breaker: shared, local: NewMemory(), ttl: ttl}
+}
+
+// localTTL is how long an entry the shared cache keeps for ttl is kept in memory.
+func (t *Tiered) localTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 && ttl < t.ttl {
+		return ttl
+	}
+	return t.ttl
+}
+
+func (t *Tiered) SetJob(ctx context.Context, job models.Jobs, ttl time.Duration) error {
+	if t.ttl > 0 {
+		_ = t.local.SetJob(ctx, job, t.localTTL(ttl))
+	}
+	return t.Breaker.SetJob(ctx, job, ttl)
+}
+
+func (t *Tiered) GetJob(ctx context.Context, jid uint64) (models.Jobs, bool, error) {
+	if t.ttl > 0 {
+		job, ok, _ := t.local.GetJob(ctx, jid)
+		if ok {
+			return job, true, nil
+		}
+	}
+	job, ok, err := t.Breaker.GetJob(ctx, jid)
+	if ok && t.ttl > 0 {
+		_ = t.local.SetJob(ctx, job, t.ttl)
+	}
+	return job, ok, err
+}
+
+func (t *Tiered) DeleteJob(ctx context.Context, jid uint64) error {
+	_ = t.local.DeleteJob(ctx, jid)
+	return t.Breaker.DeleteJob(ctx, jid)
+}
+
+func (t *Tiered) SetCompany(ctx context.Context, company models.Company, ttl time.Duration) error {
+	if t.ttl > 0 {
+		_ = t.local.SetCompany(ctx, company, t.localTTL(ttl))
+	}
+	return t.Breaker.SetCompany(ctx, company, ttl)
+}
+
+func (t *Tiered) GetCompany(ctx context.Context, cid uint64) (models.Company, bool, error) {
+	if t.ttl > 0 {
+		company, ok, _ := t.local.GetCompany(ctx, cid)
+		if ok {
+			return company, true, nil
+		}
+	}
+	company, ok, err := t.Breaker.GetCompany(ctx, cid)
+	if ok && t.ttl > 0 {
+		_ = t.local.SetCompany(ctx, company, t.ttl)
+	}
+	return company, ok, err
+}
+
+func (t *Tiered) DeleteCompany(ctx context.Context, cid uint64) error {
+	_ = t.local.DeleteCompany(ctx, cid)
+	return t.Breaker.DeleteCompany(ctx, cid)
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestTiered_localEviction(t *testing.T) {
+	ctx := context.Background()
+	job := models.Jobs{Model: gorm.Model{ID: 4}, Description: "Backend engineer"}
+	tests := []struct {
+		name     string
+		localTTL time.Duration
+		// act runs after the job was read once through the tiers, on the clock of the memory tier
+		act func(tiered *Tiered, shared *failingStore, clock *testClock)
+		// wantShared is whether reading the job again has to go to the shared cache
+		wantShared bool
+	}{
+		{
+			name:       "served from memory",
+			localTTL:   30 * time.Second,
+			act:        func(tiered *Tiered, shared *failingStore, clock *testClock) { clock.Advance(29 * time.Second) },
+			wantShared: false,
+		},
+		{
+			name:       "expires from memory",
+			localTTL:   30 * time.Second,
+			act:        func(tiered *Tiered, shared *failingStore, clock *testClock) { clock.Advance(30 * time.Second) },
+			wantShared: true,
+		},
+		{
+			name:     "deleted from memory",
+			localTTL: 30 * time.Second,
+			act: func(tiered *Tiered, shared *failingStore, clock *testClock) {
+				_ = tiered.DeleteJob(ctx, 4)
+				_ = shared.Memory.SetJob(ctx, job, time.Hour)
+			},
+			wantShared: true,
+		},
+		{
+			name:     "a shorter shared ttl caps the memory tier",
+			localTTL: 30 * time.Second,
+			act: func(tiered *Tiered, shared *failingStore, clock *testClock) {
+				_ = tiered.SetJob(ctx, job, 10*time.Second)
+				clock.Advance(10 * time.Second)
+			},
+			wantShared: true,
+		},
+		{
+			name:       "memory tier turned off",
+			localTTL:   0,
+			act:        func(tiered *Tiered, shared *failingStore, clock *testClock) {},
+			wantShared: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, store, _ := newTestBreaker(breakerConfig)
+			_ = store.Memory.SetJob(ctx, job, time.Hour)
+			tiered := NewTiered(b, tt.localTTL)
+			clock := newTestClock()
+			tiered.local.now = clock.Now
+
+			if got, ok, err := tiered.GetJob(ctx, 4); err != nil || !ok || got.Description != job.Description {
+				t.Fatalf("GetJob() = %+v, %v, %v", got, ok, err)
+			}
+			tt.act(tiered, store, clock)
+			calls := store.Calls()
+			got, ok, err := tiered.GetJob(ctx, 4)
+			if err != nil || !ok || got.Description != job.Description {
+				t.Fatalf("GetJob() again = %+v, %v, %v", got, ok, err)
+			}
+			if shared := store.Calls() > calls; shared != tt.wantShared {
+				t.Errorf("GetJob() went to the shared cache = %v, want %v", shared, tt.wantShared)
+			}
+		})
+	}
+}
+
+func TestTiered_deleteDuringOutage(t *testing.T) {
+	ctx := context.Background()
+	b, store, _ := newTestBreaker(breakerConfig)
+	tiered := NewTiered(b, time.Minute)
+	_ = tiered.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: 4}, Description: "old"}, time.Hour)
+
+	// the memory copy goes even when the shared one cannot be deleted
+	store.setErr(errConnRefused)
+	if err := tiered.DeleteJob(ctx, 4); err == nil {
+		t.Fatalf("DeleteJob() during the outage succeeded")
+	}
+	if _, ok, _ := tiered.local.GetJob(ctx, 4); ok {
+		t.Errorf("the job is still in the memory tier")
+	}
+	if _, ok, _ := tiered.GetJob(ctx, 4); ok {
+		t.Errorf("GetJob() returned the stale copy")
+	}
+}
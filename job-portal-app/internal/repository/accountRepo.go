@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+)
+
+// UpdateUsername changes the username. It reports false if another user already has it.
+func (r *Repo) UpdateUsername(ctx context.Context, uid uint, username string) (bool, error) {
+	var taken int64
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("username = ? AND id <> ?", username, uid).Count(&taken).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return false, errors.New("failed to update the username")
+	}
+	if taken > 0 {
+		return false, nil
+	}
+	err = r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", uid).Update("username", username).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return false, errors.New("failed to update the username")
+	}
+	return true, nil
+}
+
+// EmailInUse reports whether another user has the email, either as their address or as
+// one they are changing to.
+func (r *Repo) EmailInUse(ctx context.Context, uid uint, email string) (bool, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.User{}).
+		Where("(lower(email) = lower(?) OR lower(pending_email) = lower(?)) AND id <> ?", email, email, uid).
+		Count(&count).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return false, errors.New("failed to check the email")
+	}
+	return count > 0, nil
+}
+
+// SetPendingEmail stores the address the user is changing to until it is verified.
+func (r *Repo) SetPendingEmail(ctx context.Context, uid uint, email string) error {
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", uid).Update("pending_email", email).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to update the email")
+	}
+	return nil
+}
+
+// ConfirmEmailChange replaces the email with the verified pending one. It reports false if
+// the pending email is no longer email, e.g. because the user changed it again since.
+func (r *Repo) ConfirmEmailChange(ctx context.Context, uid uint, email string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND pending_email = ?", uid, email).
+		Updates(map[string]interface{}{
+			"email":             email,
+			"pending_email":     nil,
+			"email_verified_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return false, errors.New("failed to update the email")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateLocale saves the language the user gets emails and API messages in. An empty locale
+// follows the Accept-Language header again.
+func (r *Repo) UpdateLocale(ctx context.Context, uid uint, locale string) error {
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", uid).Update("locale", locale).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to update the locale")
+	}
+	return nil
+}
+
+func (r *Repo) FetchUserSessions(ctx context.Context, uid uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.DB.WithContext(ctx).Where("user_id = ?", uid).Order("created_at").Find(&sessions).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the sessions")
+	}
+	return sessions, nil
+}
+
+func (r *Repo) FetchMembershipsForUser(ctx context.Context, uid uint) ([]models.CompanyMember, error) {
+	var members []models.CompanyMember
+	err := r.DB.WithContext(ctx).Where("user_id = ?", uid).Order("company_id").Find(&members).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("failed to fetch company memberships")
+	}
+	return members, nil
+}
+
+func (r *Repo) FetchAPIKeysCreatedBy(ctx context.Context, uid uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.DB.WithContext(ctx).Where("created_by = ?", uid).Order("id").Find(&keys).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the api keys")
+	}
+	return keys, nil
+}
+
+func (r *Repo) FetchExternalIdentities(ctx context.Context, uid uint) ([]models.ExternalIdentity, error) {
+	var identities []models.ExternalIdentity
+	err := r.DB.WithContext(ctx).Where("user_id = ?", uid).Order("id").Find(&identities).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the external identities")
+	}
+	return identities, nil
+}
+
+// FetchLockoutEventsForKey returns the lockouts of one account or IP, most recent first.
+func (r *Repo) FetchLockoutEventsForKey(ctx context.Context, key string) ([]models.LockoutEvent, error) {
+	var events []models.LockoutEvent
+	err := r.DB.WithContext(ctx).Where("key = ?", key).Order("created_at desc").Find(&events).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the lockouts")
+	}
+	return events, nil
+}
+
+// DeleteUserAccount removes the user's memberships, recovery codes and linked identities,
+// revokes their sessions and the api keys they created, and anonymises the user row before
+// soft deleting it. The row is kept so that records pointing at the user stay valid.
+func (r *Repo) DeleteUserAccount(ctx context.Context, uid uint) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("user_id = ?", uid).Delete(&models.CompanyMember{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("user_id = ?", uid).Delete(&models.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("user_id = ?", uid).Delete(&models.ExternalIdentity{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("user_id = ?", uid).Delete(&models.Notification{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("user_id = ?", uid).Delete(&models.JobPreference{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", uid).Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&models.APIKey{}).Where("created_by = ? AND revoked_at IS NULL", uid).Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		anonymous := fmt.Sprintf("deleted-user-%d", uid)
+		err = tx.Model(&models.User{}).Where("id = ?", uid).Updates(map[string]interface{}{
+			"username":            anonymous,
+			"email":               anonymous + "@deleted.invalid",
+			"pending_email":       nil,
+			"password_hash":       "",
+			"email_verified_at":   nil,
+			"two_factor_enabled":  false,
+			"totp_secret":         "",
+			"totp_pending_secret": "",
+			"totp_last_step":      0,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, uid).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to delete the account")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) InsertAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	err := r.DB.WithContext(ctx).Create(&key).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.APIKey{}, errors.New("failed to create the api key")
+	}
+	return key, nil
+}
+
+// FetchAPIKeyByHash looks up a key by the hash of the key presented by the client.
+func (r *Repo) FetchAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	var key models.APIKey
+	err := r.DB.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.APIKey{}, errors.New("api key not found")
+	}
+	return key, nil
+}
+
+// FetchAPIKeys lists the keys of a company, or the service account keys when cid is nil.
+func (r *Repo) FetchAPIKeys(ctx context.Context, cid *uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := companyKeys(r.DB, cid).Order("id").Find(&keys).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the api keys")
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes the key if it belongs to the company, or is a service account key
+// when cid is nil.
+func (r *Repo) RevokeAPIKey(ctx context.Context, keyID uint, cid *uint) error {
+	result := companyKeys(r.DB.WithContext(ctx).Model(&models.APIKey{}), cid).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return errors.New("failed to revoke the api key")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("api key not found")
+	}
+	return nil
+}
+
+// TouchAPIKey records when the key was last used.
+func (r *Repo) TouchAPIKey(ctx context.Context, keyID uint, usedAt time.Time) error {
+	err := r.DB.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", keyID).Update("last_used_at", usedAt).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to update the api key")
+	}
+	return nil
+}
+
+func companyKeys(db *gorm.DB, cid *uint) *gorm.DB {
+	if cid == nil {
+		return db.Where("company_id IS NULL")
+	}
+	return db.Where("company_id = ?", *cid)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) InsertAuditEvent(ctx context.Context, event models.AuditEvent) error {
+	err := r.DB.WithContext(ctx).Create(&event).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to write the audit log")
+	}
+	return nil
+}
+
+// FetchAuditEvents returns the matching events, most recent first.
+func (r *Repo) FetchAuditEvents(ctx context.Context, query models.AuditQuery) ([]models.AuditEvent, error) {
+	db := r.DB.WithContext(ctx).Model(&models.AuditEvent{})
+	if query.UserID != 0 {
+		db = db.Where("actor_user_id = ? OR target_user_id = ?", query.UserID, query.UserID)
+	}
+	if query.EventType != "" {
+		db = db.Where("event_type = ?", query.EventType)
+	}
+	if !query.From.IsZero() {
+		db = db.Where("created_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		db = db.Where("created_at < ?", query.To)
+	}
+
+	var events []models.AuditEvent
+	err := db.Order("created_at desc, id desc").Limit(query.Limit).Find(&events).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the audit log")
+	}
+	return events, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+)
+
+// invalidateCache records that a cached job or company changed and announces it on
+// models.CacheInvalidationChannel. Run it in the transaction of the change: Postgres only
+// delivers the notification once that commits, and drops it on a rollback. New records are
+// not announced, since a lookup that finds nothing is never cached.
+func invalidateCache(tx *gorm.DB, kind string, id uint) error {
+	invalidation := models.CacheInvalidation{Kind: kind, EntityID: id}
+	err := tx.Create(&invalidation).Error
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(invalidation)
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT pg_notify(?, ?)", models.CacheInvalidationChannel, string(payload)).Error
+}
+
+// FetchCacheInvalidations returns the jobs and companies that changed since the given time,
+// each once.
+func (r *Repo) FetchCacheInvalidations(ctx context.Context, since time.Time) ([]models.CacheInvalidation, error) {
+	var invalidations []models.CacheInvalidation
+	err := r.DB.WithContext(ctx).Model(&models.CacheInvalidation{}).
+		Select("kind, entity_id, MAX(created_at) AS created_at").
+		Where("created_at >= ?", since).Group("kind, entity_id").
+		Find(&invalidations).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("failed to fetch cache invalidations")
+	}
+	return invalidations, nil
+}
+
+// DeleteCacheInvalidations drops the invalidations older than before.
+func (r *Repo) DeleteCacheInvalidations(ctx context.Context, before time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Where("created_at < ?", before).Delete(&models.CacheInvalidation{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return 0, errors.New("failed to delete cache invalidations")
+	}
+	return result.RowsAffected, nil
+}
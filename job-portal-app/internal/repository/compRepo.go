@@ -5,13 +5,25 @@ import (
 	"errors"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 	"job-portal-api/internal/models"
 )
 
-func (r *Repo) InsertCompany(ctx context.Context, companyData models.Company) (models.Company, error) {
-	result := r.DB.Create(&companyData)
-	if result.Error != nil {
-		log.Info().Err(result.Error).Send()
+// InsertCompany creates the company and makes ownerID its owner in the same transaction.
+func (r *Repo) InsertCompany(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&companyData).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&models.CompanyMember{
+			CompanyID: companyData.ID,
+			UserID:    ownerID,
+			Role:      models.MemberRoleOwner,
+		}).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
 		return models.Company{}, errors.New("failed to create company")
 	}
 	return companyData, nil
@@ -19,7 +31,7 @@ func (r *Repo) InsertCompany(ctx context.Context, companyData models.Company) (m
 
 func (r *Repo) FetchAllCompanies(ctx context.Context) ([]models.Company, error) {
 	var userDetails []models.Company
-	result := r.DB.Find(&userDetails)
+	result := r.DB.WithContext(ctx).Find(&userDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return nil, errors.New("failed to fetch the companies")
@@ -29,7 +41,7 @@ func (r *Repo) FetchAllCompanies(ctx context.Context) ([]models.Company, error)
 
 func (r *Repo) FetchCompanyByID(ctx context.Context, cid uint64) (models.Company, error) {
 	var companyData models.Company
-	result := r.DB.Where("id = ?", cid).First(&companyData)
+	result := r.DB.WithContext(ctx).Where("id = ?", cid).First(&companyData)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return models.Company{}, errors.New("failed to fetch the company")
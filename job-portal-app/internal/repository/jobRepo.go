@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 	"job-portal-api/internal/models"
@@ -11,7 +13,7 @@ import (
 
 func (r *Repo) FetchJobPostingByID(ctx context.Context, jid uint64) (models.Jobs, error) {
 	var job models.Jobs
-	result := r.DB.Where("id = ?", jid).Preload("Locations").Preload("TechnologyStacks").Preload("WorkModes").Preload("Qualifications").Preload("Shifts").Preload("JobTypes").Find(&job)
+	result := r.DB.WithContext(ctx).Where("id = ?", jid).Preload("Locations").Preload("TechnologyStacks").Preload("WorkModes").Preload("Qualifications").Preload("Shifts").Preload("JobTypes").Find(&job)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return models.Jobs{}, errors.New("failed to create job postings")
@@ -35,7 +37,7 @@ func (r *Repo) InsertJobPosting(ctx context.Context, jobData models.NewJobReques
 		Shifts:           getShifts(jobData.Shifts),
 		JobTypes:         getJobTypes(jobData.JobTypes),
 	}
-	result := r.DB.Create(&job)
+	result := r.DB.WithContext(ctx).Create(&job)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return models.NewJobResponse{}, errors.New("failed to insert job posting")
@@ -46,6 +48,71 @@ func (r *Repo) InsertJobPosting(ctx context.Context, jobData models.NewJobReques
 	return res, nil
 }
 
+// UpdateJobPosting replaces the details and criteria of an existing job posting. The
+// owning company cannot be changed.
+func (r *Repo) UpdateJobPosting(ctx context.Context, jid uint64, jobData models.NewJobRequest) (models.Jobs, error) {
+	var job models.Jobs
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", jid).First(&job).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&job).Updates(map[string]interface{}{
+			"min_np":      jobData.MinNP,
+			"max_np":      jobData.MaxNP,
+			"budget":      jobData.Budget,
+			"description": jobData.Description,
+			"min_exp":     jobData.MinExp,
+			"max_exp":     jobData.MaxExp,
+		}).Error
+		if err != nil {
+			return err
+		}
+		associations := map[string]interface{}{
+			"Locations":        getLocations(jobData.Locations),
+			"TechnologyStacks": getTechnologyStacks(jobData.TechnologyStacks),
+			"WorkModes":        getWorkModes(jobData.WorkModes),
+			"Qualifications":   getQualifications(jobData.Qualifications),
+			"Shifts":           getShifts(jobData.Shifts),
+			"JobTypes":         getJobTypes(jobData.JobTypes),
+		}
+		for name, values := range associations {
+			err = tx.Model(&job).Association(name).Replace(values)
+			if err != nil {
+				return err
+			}
+		}
+		return invalidateCache(tx, models.CacheKindJob, job.ID)
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Jobs{}, errors.New("failed to update job posting")
+	}
+	return job, nil
+}
+
+// CloseJobPosting stops a job from taking applications. It reports false when the job
+// does not exist or is already closed.
+func (r *Repo) CloseJobPosting(ctx context.Context, jid uint64, closedAt time.Time) (bool, error) {
+	var closed bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Jobs{}).Where("id = ? AND closed_at IS NULL", jid).Update("closed_at", closedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		closed = result.RowsAffected == 1
+		if !closed {
+			return nil
+		}
+		return invalidateCache(tx, models.CacheKindJob, uint(jid))
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return false, errors.New("failed to close job posting")
+	}
+	return closed, nil
+}
+
 func getLocations(locationsIds []uint) (locations []models.Locations) {
 	for _, id := range locationsIds {
 		locations = append(locations, models.Locations{Model: gorm.Model{ID: id}})
@@ -90,7 +157,7 @@ func getJobTypes(jobTypesIds []uint) (jobTypes []models.JobTypes) {
 
 func (r *Repo) FetchAllJobPostings(ctx context.Context) ([]models.Jobs, error) {
 	var jobDatas []models.Jobs
-	result := r.DB.Preload("Locations").Find(&jobDatas)
+	result := r.DB.WithContext(ctx).Preload("Locations").Find(&jobDatas)
 	fmt.Println("DB::", jobDatas)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
@@ -102,7 +169,7 @@ func (r *Repo) FetchAllJobPostings(ctx context.Context) ([]models.Jobs, error) {
 
 func (r *Repo) FetchJobsForCompany(ctx context.Context, cid uint64) ([]models.Jobs, error) {
 	var jobData []models.Jobs
-	result := r.DB.Where("cid = ?", cid).Preload("Locations").Preload("Qualification").Preload("TechnologyStack").Preload("WorkMode").Preload("Shift").Preload("JobType").Find(&jobData)
+	result := r.DB.WithContext(ctx).Where("cid = ?", cid).Preload("Locations").Preload("Qualification").Preload("TechnologyStack").Preload("WorkMode").Preload("Shift").Preload("JobType").Find(&jobData)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return nil, errors.New("failed to fetch jobs for company")
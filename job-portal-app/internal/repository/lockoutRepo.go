@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) InsertLockoutEvent(ctx context.Context, event models.LockoutEvent) error {
+	err := r.DB.WithContext(ctx).Create(&event).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to record the lockout")
+	}
+	return nil
+}
+
+// FetchLockoutEvents returns the most recent lockouts first.
+func (r *Repo) FetchLockoutEvents(ctx context.Context, limit int) ([]models.LockoutEvent, error) {
+	var events []models.LockoutEvent
+	err := r.DB.WithContext(ctx).Order("created_at desc").Limit(limit).Find(&events).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the lockouts")
+	}
+	return events, nil
+}
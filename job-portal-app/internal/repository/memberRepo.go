@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm/clause"
+	"job-portal-api/internal/models"
+)
+
+// FetchCompanyMember returns the membership of a user in a company. A zero-value member
+// and no error means the user is not a member.
+func (r *Repo) FetchCompanyMember(ctx context.Context, cid uint64, uid uint) (models.CompanyMember, error) {
+	var member models.CompanyMember
+	result := r.DB.WithContext(ctx).Where("company_id = ? AND user_id = ?", cid, uid).Limit(1).Find(&member)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.CompanyMember{}, errors.New("failed to fetch company membership")
+	}
+	return member, nil
+}
+
+func (r *Repo) FetchCompanyMembers(ctx context.Context, cid uint64) ([]models.CompanyMember, error) {
+	var members []models.CompanyMember
+	result := r.DB.WithContext(ctx).Where("company_id = ?", cid).Order("id").Find(&members)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, errors.New("failed to fetch company members")
+	}
+	return members, nil
+}
+
+// FetchCompanyIDsForUser returns the IDs of every company the user is a member of.
+func (r *Repo) FetchCompanyIDsForUser(ctx context.Context, uid uint) ([]uint, error) {
+	var ids []uint
+	result := r.DB.WithContext(ctx).Model(&models.CompanyMember{}).Where("user_id = ?", uid).Pluck("company_id", &ids)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, errors.New("failed to fetch company memberships")
+	}
+	return ids, nil
+}
+
+// SaveCompanyMember adds the member, or updates the role if the user already belongs to the company.
+func (r *Repo) SaveCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "company_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(&member)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.CompanyMember{}, errors.New("failed to save company member")
+	}
+	return member, nil
+}
+
+func (r *Repo) DeleteCompanyMember(ctx context.Context, cid uint64, uid uint) error {
+	result := r.DB.WithContext(ctx).Unscoped().Where("company_id = ? AND user_id = ?", cid, uid).Delete(&models.CompanyMember{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return errors.New("failed to remove company member")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user is not a member of this company")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) InsertNotifications(ctx context.Context, notifications ...models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	err := r.DB.WithContext(ctx).Create(&notifications).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to save the notification")
+	}
+	return nil
+}
+
+// FetchNotifications returns the notifications selected by the query, newest first.
+func (r *Repo) FetchNotifications(ctx context.Context, query models.NotificationQuery) ([]models.Notification, error) {
+	var notifications []models.Notification
+	db := r.DB.WithContext(ctx).Where("user_id = ?", query.UserID)
+	if query.Before != 0 {
+		db = db.Where("id < ?", query.Before)
+	}
+	if query.UnreadOnly {
+		db = db.Where("read_at IS NULL")
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	err := db.Order("id desc").Find(&notifications).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the notifications")
+	}
+	return notifications, nil
+}
+
+func (r *Repo) CountUnreadNotifications(ctx context.Context, uid uint) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", uid).Count(&count).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return 0, errors.New("could not count the notifications")
+	}
+	return count, nil
+}
+
+// MarkNotificationRead marks a notification of the user read. Marking it twice keeps the
+// first time. It reports false when the user has no such notification.
+func (r *Repo) MarkNotificationRead(ctx context.Context, uid uint, id uint, readAt time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, uid).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", readAt))
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return false, errors.New("failed to update the notification")
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user read and returns how
+// many there were.
+func (r *Repo) MarkAllNotificationsRead(ctx context.Context, uid uint, readAt time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", uid).
+		Update("read_at", readAt)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return 0, errors.New("failed to update the notifications")
+	}
+	return result.RowsAffected, nil
+}
+
+// SaveJobPreference creates or replaces the job preference of a user.
+func (r *Repo) SaveJobPreference(ctx context.Context, preference models.JobPreference) (models.JobPreference, error) {
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&preference).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.JobPreference{}, errors.New("failed to save the job preference")
+	}
+	return preference, nil
+}
+
+// FetchJobPreference returns the job preference of the user, one with UserID 0 when they
+// have none.
+func (r *Repo) FetchJobPreference(ctx context.Context, uid uint) (models.JobPreference, error) {
+	var preference models.JobPreference
+	err := r.DB.WithContext(ctx).Where("user_id = ?", uid).Limit(1).Find(&preference).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.JobPreference{}, errors.New("could not fetch the job preference")
+	}
+	return preference, nil
+}
+
+// FetchJobPreferences returns a page of the job preferences, ordered by user. The next page
+// starts after the last user of this one.
+func (r *Repo) FetchJobPreferences(ctx context.Context, afterUserID uint, limit int) ([]models.JobPreference, error) {
+	var preferences []models.JobPreference
+	err := r.DB.WithContext(ctx).Where("user_id > ?", afterUserID).Order("user_id").Limit(limit).Find(&preferences).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the job preferences")
+	}
+	return preferences, nil
+}
+
+// FetchUsersByIDs returns the id, role and locale of the users with the given ids. Unknown
+// ids are left out.
+func (r *Repo) FetchUsersByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	var users []models.User
+	err := r.DB.WithContext(ctx).Select("id", "role", "locale").Where("id IN ?", ids).Find(&users).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the users")
+	}
+	return users, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) InsertOIDCLoginState(ctx context.Context, state models.OIDCLoginState) error {
+	err := r.DB.WithContext(ctx).Create(&state).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to store the login state")
+	}
+	return nil
+}
+
+// ConsumeOIDCLoginState deletes the login state and returns it, so each state can only be
+// used once even when the callback is replayed concurrently.
+func (r *Repo) ConsumeOIDCLoginState(ctx context.Context, stateHash string) (models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	err := r.DB.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.OIDCLoginState{}, errors.New("failed to fetch the login state")
+	}
+	if len(states) == 0 {
+		return models.OIDCLoginState{}, errors.New("login state not found")
+	}
+	return states[0], nil
+}
+
+// FetchExternalIdentity reports whether the provider's subject is linked to a user yet.
+func (r *Repo) FetchExternalIdentity(ctx context.Context, provider, subject string) (models.ExternalIdentity, bool, error) {
+	var identity models.ExternalIdentity
+	err := r.DB.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ExternalIdentity{}, false, nil
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.ExternalIdentity{}, false, errors.New("failed to fetch the external identity")
+	}
+	return identity, true, nil
+}
+
+func (r *Repo) InsertExternalIdentity(ctx context.Context, identity models.ExternalIdentity) error {
+	err := r.DB.WithContext(ctx).Create(&identity).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to link the external identity")
+	}
+	return nil
+}
+
+// InsertUserWithIdentity creates a user that signed in through a provider for the first time.
+func (r *Repo) InsertUserWithIdentity(ctx context.Context, user models.User, identity models.ExternalIdentity) (models.User, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&user).Error
+		if err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(&identity).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.User{}, errors.New("failed to create the user")
+	}
+	return user, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"job-portal-api/internal/models"
+)
+
+// Transaction runs fn with a repository bound to one database transaction. It is committed
+// when fn returns nil and rolled back otherwise.
+func (r *Repo) Transaction(ctx context.Context, fn func(tx UserRepo) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&Repo{DB: tx})
+	})
+}
+
+// EnqueueOutbox queues messages for the dispatcher. Call it on the repository passed to
+// Transaction to queue them together with the change they belong to.
+func (r *Repo) EnqueueOutbox(ctx context.Context, messages ...models.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range messages {
+		messages[i].Status = models.OutboxStatusPending
+		if messages[i].NextAttemptAt.IsZero() {
+			messages[i].NextAttemptAt = now
+		}
+	}
+	err := r.DB.WithContext(ctx).Create(&messages).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to queue the message")
+	}
+	return nil
+}
+
+// ClaimOutboxMessages returns up to limit pending messages that are due and pushes their next
+// attempt lease into the future, so that other dispatchers skip them while they are delivered.
+func (r *Repo) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, now).
+			Order("next_attempt_at, id").Limit(limit).Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(messages))
+		for _, m := range messages {
+			ids = append(ids, m.ID)
+		}
+		return tx.Model(&models.OutboxMessage{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the outbox")
+	}
+	return messages, nil
+}
+
+// MarkOutboxDelivered records a delivery and drops the payload.
+func (r *Repo) MarkOutboxDelivered(ctx context.Context, id uint, deliveredAt time.Time) error {
+	err := r.DB.WithContext(ctx).Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       models.OutboxStatusDelivered,
+		"delivered_at": deliveredAt,
+		"payload":      nil,
+		"last_error":   "",
+	}).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("could not update the outbox")
+	}
+	return nil
+}
+
+// RecordOutboxFailure stores a failed attempt. status is pending for a retry at
+// nextAttemptAt, or dead once the dispatcher gives up.
+func (r *Repo) RecordOutboxFailure(ctx context.Context, id uint, attempts int, lastError string, status string, nextAttemptAt time.Time) error {
+	err := r.DB.WithContext(ctx).Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"attempts":        attempts,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("could not update the outbox")
+	}
+	return nil
+}
+
+// FetchOutboxMessages returns the messages in the given status, most recently changed first.
+func (r *Repo) FetchOutboxMessages(ctx context.Context, status string, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := r.DB.WithContext(ctx).Where("status = ?", status).Order("updated_at desc, id desc").Limit(limit).Find(&messages).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the outbox")
+	}
+	return messages, nil
+}
+
+// ReplayOutboxMessage queues a dead message again with a fresh set of attempts. It reports
+// false when there is no dead message with that id.
+func (r *Repo) ReplayOutboxMessage(ctx context.Context, id uint) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.OutboxMessage{}).
+		Where("id = ? AND status = ?", id, models.OutboxStatusDead).
+		Updates(map[string]interface{}{
+			"status":          models.OutboxStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return false, errors.New("could not update the outbox")
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteDeliveredOutboxMessages drops the messages delivered before the given time. Pending
+// and dead messages are kept.
+func (r *Repo) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).
+		Where("status = ? AND delivered_at < ?", models.OutboxStatusDelivered, before).
+		Delete(&models.OutboxMessage{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return 0, errors.New("failed to delete delivered outbox messages")
+	}
+	return result.RowsAffected, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"job-portal-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 	//"job-portal/internal/models"
@@ -19,16 +20,91 @@ type UserRepo interface {
 	InsertUser(ctx context.Context, userData models.User) (models.User, error)
 	VerifyUserCredentials(ctx context.Context, email string) (models.User, error)
 
-	InsertCompany(ctx context.Context, companyData models.Company) (models.Company, error)
+	InsertCompany(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error)
 	FetchAllCompanies(ctx context.Context) ([]models.Company, error)
 	FetchCompanyByID(ctx context.Context, cid uint64) (models.Company, error)
 
+	FetchCompanyMember(ctx context.Context, cid uint64, uid uint) (models.CompanyMember, error)
+	FetchCompanyMembers(ctx context.Context, cid uint64) ([]models.CompanyMember, error)
+	FetchCompanyIDsForUser(ctx context.Context, uid uint) ([]uint, error)
+	SaveCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error)
+	DeleteCompanyMember(ctx context.Context, cid uint64, uid uint) error
+
 	InsertJobPosting(ctx context.Context, jobData models.NewJobRequest) (models.NewJobResponse, error)
+	UpdateJobPosting(ctx context.Context, jid uint64, jobData models.NewJobRequest) (models.Jobs, error)
 	FetchJobsForCompany(ctx context.Context, cid uint64) ([]models.Jobs, error)
 	FetchAllJobPostings(ctx context.Context) ([]models.Jobs, error)
 	FetchJobPostingByID(ctx context.Context, jid uint64) (models.Jobs, error)
+	CloseJobPosting(ctx context.Context, jid uint64, closedAt time.Time) (bool, error)
+	FetchCacheInvalidations(ctx context.Context, since time.Time) ([]models.CacheInvalidation, error)
+	DeleteCacheInvalidations(ctx context.Context, before time.Time) (int64, error)
 	GetUserByEmail(ctx context.Context, email string) (models.User, error)
 	UpdatePassword(ctx context.Context, email, hashedPassword string) error
+	UpdateUserRole(ctx context.Context, uid uint64, role string) (models.User, error)
+	GetUserByID(ctx context.Context, uid uint64) (models.User, error)
+	MarkEmailVerified(ctx context.Context, uid uint) error
+	SaveTOTPPendingSecret(ctx context.Context, uid uint, secret string) error
+	EnableTOTP(ctx context.Context, uid uint, step int64, codes []models.RecoveryCode) error
+	DisableTOTP(ctx context.Context, uid uint) error
+	AdvanceTOTPStep(ctx context.Context, uid uint, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, uid uint, codeHash string) (bool, error)
+	InsertAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, error)
+	FetchAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error)
+	FetchAPIKeys(ctx context.Context, cid *uint) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uint, cid *uint) error
+	TouchAPIKey(ctx context.Context, keyID uint, usedAt time.Time) error
+	InsertLockoutEvent(ctx context.Context, event models.LockoutEvent) error
+	FetchLockoutEvents(ctx context.Context, limit int) ([]models.LockoutEvent, error)
+	UpdateUsername(ctx context.Context, uid uint, username string) (bool, error)
+	EmailInUse(ctx context.Context, uid uint, email string) (bool, error)
+	SetPendingEmail(ctx context.Context, uid uint, email string) error
+	ConfirmEmailChange(ctx context.Context, uid uint, email string) (bool, error)
+	UpdateLocale(ctx context.Context, uid uint, locale string) error
+	FetchUserSessions(ctx context.Context, uid uint) ([]models.Session, error)
+	FetchMembershipsForUser(ctx context.Context, uid uint) ([]models.CompanyMember, error)
+	FetchAPIKeysCreatedBy(ctx context.Context, uid uint) ([]models.APIKey, error)
+	FetchExternalIdentities(ctx context.Context, uid uint) ([]models.ExternalIdentity, error)
+	FetchLockoutEventsForKey(ctx context.Context, key string) ([]models.LockoutEvent, error)
+	DeleteUserAccount(ctx context.Context, uid uint) error
+	InsertAuditEvent(ctx context.Context, event models.AuditEvent) error
+	FetchAuditEvents(ctx context.Context, query models.AuditQuery) ([]models.AuditEvent, error)
+	Transaction(ctx context.Context, fn func(tx UserRepo) error) error
+	EnqueueOutbox(ctx context.Context, messages ...models.OutboxMessage) error
+	ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error)
+	MarkOutboxDelivered(ctx context.Context, id uint, deliveredAt time.Time) error
+	RecordOutboxFailure(ctx context.Context, id uint, attempts int, lastError string, status string, nextAttemptAt time.Time) error
+	FetchOutboxMessages(ctx context.Context, status string, limit int) ([]models.OutboxMessage, error)
+	ReplayOutboxMessage(ctx context.Context, id uint) (bool, error)
+	DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int64, error)
+	InsertWebhookEndpoint(ctx context.Context, endpoint models.WebhookEndpoint) (models.WebhookEndpoint, error)
+	FetchWebhookEndpoints(ctx context.Context, cid uint) ([]models.WebhookEndpoint, error)
+	FetchWebhookEndpoint(ctx context.Context, id uint) (models.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, cid uint, id uint) (bool, error)
+	InsertWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	FetchWebhookDeliveries(ctx context.Context, endpointID uint, limit int) ([]models.WebhookDelivery, error)
+	InsertNotifications(ctx context.Context, notifications ...models.Notification) error
+	FetchNotifications(ctx context.Context, query models.NotificationQuery) ([]models.Notification, error)
+	CountUnreadNotifications(ctx context.Context, uid uint) (int64, error)
+	MarkNotificationRead(ctx context.Context, uid uint, id uint, readAt time.Time) (bool, error)
+	MarkAllNotificationsRead(ctx context.Context, uid uint, readAt time.Time) (int64, error)
+	SaveJobPreference(ctx context.Context, preference models.JobPreference) (models.JobPreference, error)
+	FetchJobPreference(ctx context.Context, uid uint) (models.JobPreference, error)
+	FetchJobPreferences(ctx context.Context, afterUserID uint, limit int) ([]models.JobPreference, error)
+	FetchUsersByIDs(ctx context.Context, ids []uint) ([]models.User, error)
+	InsertOIDCLoginState(ctx context.Context, state models.OIDCLoginState) error
+	ConsumeOIDCLoginState(ctx context.Context, stateHash string) (models.OIDCLoginState, error)
+	FetchExternalIdentity(ctx context.Context, provider, subject string) (models.ExternalIdentity, bool, error)
+	InsertExternalIdentity(ctx context.Context, identity models.ExternalIdentity) error
+	InsertUserWithIdentity(ctx context.Context, user models.User, identity models.ExternalIdentity) (models.User, error)
+
+	InsertSession(ctx context.Context, session models.Session, refreshToken models.RefreshToken) error
+	FetchRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, models.Session, error)
+	RotateRefreshToken(ctx context.Context, usedID uint, next models.RefreshToken) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeUserSessions(ctx context.Context, uid uint, exceptSessionID string) error
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	DeleteExpiredRevokedTokens(ctx context.Context, before time.Time) (int64, error)
+	IsTokenRevoked(ctx context.Context, jti string, sessionID string) (bool, error)
 }
 
 func NewRepository(db *gorm.DB) (UserRepo, error) {
@@ -12,6 +12,7 @@ import (
 	context "context"
 	models "job-portal-api/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -39,6 +40,291 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 	return m.recorder
 }
 
+// AdvanceTOTPStep mocks base method.
+func (m *MockUserRepo) AdvanceTOTPStep(ctx context.Context, uid uint, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceTOTPStep", ctx, uid, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceTOTPStep indicates an expected call of AdvanceTOTPStep.
+func (mr *MockUserRepoMockRecorder) AdvanceTOTPStep(ctx, uid, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceTOTPStep", reflect.TypeOf((*MockUserRepo)(nil).AdvanceTOTPStep), ctx, uid, step)
+}
+
+// ClaimOutboxMessages mocks base method.
+func (m *MockUserRepo) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxMessages", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]models.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxMessages indicates an expected call of ClaimOutboxMessages.
+func (mr *MockUserRepoMockRecorder) ClaimOutboxMessages(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxMessages", reflect.TypeOf((*MockUserRepo)(nil).ClaimOutboxMessages), ctx, now, lease, limit)
+}
+
+// CloseJobPosting mocks base method.
+func (m *MockUserRepo) CloseJobPosting(ctx context.Context, jid uint64, closedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseJobPosting", ctx, jid, closedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseJobPosting indicates an expected call of CloseJobPosting.
+func (mr *MockUserRepoMockRecorder) CloseJobPosting(ctx, jid, closedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseJobPosting", reflect.TypeOf((*MockUserRepo)(nil).CloseJobPosting), ctx, jid, closedAt)
+}
+
+// ConfirmEmailChange mocks base method.
+func (m *MockUserRepo) ConfirmEmailChange(ctx context.Context, uid uint, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", ctx, uid, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockUserRepoMockRecorder) ConfirmEmailChange(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockUserRepo)(nil).ConfirmEmailChange), ctx, uid, email)
+}
+
+// ConsumeOIDCLoginState mocks base method.
+func (m *MockUserRepo) ConsumeOIDCLoginState(ctx context.Context, stateHash string) (models.OIDCLoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOIDCLoginState", ctx, stateHash)
+	ret0, _ := ret[0].(models.OIDCLoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOIDCLoginState indicates an expected call of ConsumeOIDCLoginState.
+func (mr *MockUserRepoMockRecorder) ConsumeOIDCLoginState(ctx, stateHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOIDCLoginState", reflect.TypeOf((*MockUserRepo)(nil).ConsumeOIDCLoginState), ctx, stateHash)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockUserRepo) CountUnreadNotifications(ctx context.Context, uid uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockUserRepoMockRecorder) CountUnreadNotifications(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockUserRepo)(nil).CountUnreadNotifications), ctx, uid)
+}
+
+// DeleteCacheInvalidations mocks base method.
+func (m *MockUserRepo) DeleteCacheInvalidations(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCacheInvalidations", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCacheInvalidations indicates an expected call of DeleteCacheInvalidations.
+func (mr *MockUserRepoMockRecorder) DeleteCacheInvalidations(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCacheInvalidations", reflect.TypeOf((*MockUserRepo)(nil).DeleteCacheInvalidations), ctx, before)
+}
+
+// DeleteCompanyMember mocks base method.
+func (m *MockUserRepo) DeleteCompanyMember(ctx context.Context, cid uint64, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompanyMember", ctx, cid, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompanyMember indicates an expected call of DeleteCompanyMember.
+func (mr *MockUserRepoMockRecorder) DeleteCompanyMember(ctx, cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompanyMember", reflect.TypeOf((*MockUserRepo)(nil).DeleteCompanyMember), ctx, cid, uid)
+}
+
+// DeleteDeliveredOutboxMessages mocks base method.
+func (m *MockUserRepo) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeliveredOutboxMessages", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliveredOutboxMessages indicates an expected call of DeleteDeliveredOutboxMessages.
+func (mr *MockUserRepoMockRecorder) DeleteDeliveredOutboxMessages(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveredOutboxMessages", reflect.TypeOf((*MockUserRepo)(nil).DeleteDeliveredOutboxMessages), ctx, before)
+}
+
+// DeleteExpiredRevokedTokens mocks base method.
+func (m *MockUserRepo) DeleteExpiredRevokedTokens(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRevokedTokens", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredRevokedTokens indicates an expected call of DeleteExpiredRevokedTokens.
+func (mr *MockUserRepoMockRecorder) DeleteExpiredRevokedTokens(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRevokedTokens", reflect.TypeOf((*MockUserRepo)(nil).DeleteExpiredRevokedTokens), ctx, before)
+}
+
+// DeleteUserAccount mocks base method.
+func (m *MockUserRepo) DeleteUserAccount(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserAccount", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserAccount indicates an expected call of DeleteUserAccount.
+func (mr *MockUserRepoMockRecorder) DeleteUserAccount(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserAccount", reflect.TypeOf((*MockUserRepo)(nil).DeleteUserAccount), ctx, uid)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockUserRepo) DeleteWebhookEndpoint(ctx context.Context, cid, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", ctx, cid, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockUserRepoMockRecorder) DeleteWebhookEndpoint(ctx, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockUserRepo)(nil).DeleteWebhookEndpoint), ctx, cid, id)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockUserRepo) DisableTOTP(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUserRepoMockRecorder) DisableTOTP(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUserRepo)(nil).DisableTOTP), ctx, uid)
+}
+
+// EmailInUse mocks base method.
+func (m *MockUserRepo) EmailInUse(ctx context.Context, uid uint, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailInUse", ctx, uid, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailInUse indicates an expected call of EmailInUse.
+func (mr *MockUserRepoMockRecorder) EmailInUse(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailInUse", reflect.TypeOf((*MockUserRepo)(nil).EmailInUse), ctx, uid, email)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockUserRepo) EnableTOTP(ctx context.Context, uid uint, step int64, codes []models.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", ctx, uid, step, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockUserRepoMockRecorder) EnableTOTP(ctx, uid, step, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockUserRepo)(nil).EnableTOTP), ctx, uid, step, codes)
+}
+
+// EnqueueOutbox mocks base method.
+func (m *MockUserRepo) EnqueueOutbox(ctx context.Context, messages ...models.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range messages {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnqueueOutbox", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueOutbox indicates an expected call of EnqueueOutbox.
+func (mr *MockUserRepoMockRecorder) EnqueueOutbox(ctx any, messages ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, messages...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueOutbox", reflect.TypeOf((*MockUserRepo)(nil).EnqueueOutbox), varargs...)
+}
+
+// FetchAPIKeyByHash mocks base method.
+func (m *MockUserRepo) FetchAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAPIKeyByHash indicates an expected call of FetchAPIKeyByHash.
+func (mr *MockUserRepoMockRecorder) FetchAPIKeyByHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAPIKeyByHash", reflect.TypeOf((*MockUserRepo)(nil).FetchAPIKeyByHash), ctx, keyHash)
+}
+
+// FetchAPIKeys mocks base method.
+func (m *MockUserRepo) FetchAPIKeys(ctx context.Context, cid *uint) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAPIKeys", ctx, cid)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAPIKeys indicates an expected call of FetchAPIKeys.
+func (mr *MockUserRepoMockRecorder) FetchAPIKeys(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAPIKeys", reflect.TypeOf((*MockUserRepo)(nil).FetchAPIKeys), ctx, cid)
+}
+
+// FetchAPIKeysCreatedBy mocks base method.
+func (m *MockUserRepo) FetchAPIKeysCreatedBy(ctx context.Context, uid uint) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAPIKeysCreatedBy", ctx, uid)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAPIKeysCreatedBy indicates an expected call of FetchAPIKeysCreatedBy.
+func (mr *MockUserRepoMockRecorder) FetchAPIKeysCreatedBy(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAPIKeysCreatedBy", reflect.TypeOf((*MockUserRepo)(nil).FetchAPIKeysCreatedBy), ctx, uid)
+}
+
 // FetchAllCompanies mocks base method.
 func (m *MockUserRepo) FetchAllCompanies(ctx context.Context) ([]models.Company, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +355,36 @@ func (mr *MockUserRepoMockRecorder) FetchAllJobPostings(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAllJobPostings", reflect.TypeOf((*MockUserRepo)(nil).FetchAllJobPostings), ctx)
 }
 
+// FetchAuditEvents mocks base method.
+func (m *MockUserRepo) FetchAuditEvents(ctx context.Context, query models.AuditQuery) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAuditEvents", ctx, query)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAuditEvents indicates an expected call of FetchAuditEvents.
+func (mr *MockUserRepoMockRecorder) FetchAuditEvents(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAuditEvents", reflect.TypeOf((*MockUserRepo)(nil).FetchAuditEvents), ctx, query)
+}
+
+// FetchCacheInvalidations mocks base method.
+func (m *MockUserRepo) FetchCacheInvalidations(ctx context.Context, since time.Time) ([]models.CacheInvalidation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchCacheInvalidations", ctx, since)
+	ret0, _ := ret[0].([]models.CacheInvalidation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchCacheInvalidations indicates an expected call of FetchCacheInvalidations.
+func (mr *MockUserRepoMockRecorder) FetchCacheInvalidations(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCacheInvalidations", reflect.TypeOf((*MockUserRepo)(nil).FetchCacheInvalidations), ctx, since)
+}
+
 // FetchCompanyByID mocks base method.
 func (m *MockUserRepo) FetchCompanyByID(ctx context.Context, cid uint64) (models.Company, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +400,82 @@ func (mr *MockUserRepoMockRecorder) FetchCompanyByID(ctx, cid any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCompanyByID", reflect.TypeOf((*MockUserRepo)(nil).FetchCompanyByID), ctx, cid)
 }
 
+// FetchCompanyIDsForUser mocks base method.
+func (m *MockUserRepo) FetchCompanyIDsForUser(ctx context.Context, uid uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchCompanyIDsForUser", ctx, uid)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchCompanyIDsForUser indicates an expected call of FetchCompanyIDsForUser.
+func (mr *MockUserRepoMockRecorder) FetchCompanyIDsForUser(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCompanyIDsForUser", reflect.TypeOf((*MockUserRepo)(nil).FetchCompanyIDsForUser), ctx, uid)
+}
+
+// FetchCompanyMember mocks base method.
+func (m *MockUserRepo) FetchCompanyMember(ctx context.Context, cid uint64, uid uint) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchCompanyMember", ctx, cid, uid)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchCompanyMember indicates an expected call of FetchCompanyMember.
+func (mr *MockUserRepoMockRecorder) FetchCompanyMember(ctx, cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCompanyMember", reflect.TypeOf((*MockUserRepo)(nil).FetchCompanyMember), ctx, cid, uid)
+}
+
+// FetchCompanyMembers mocks base method.
+func (m *MockUserRepo) FetchCompanyMembers(ctx context.Context, cid uint64) ([]models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchCompanyMembers", ctx, cid)
+	ret0, _ := ret[0].([]models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchCompanyMembers indicates an expected call of FetchCompanyMembers.
+func (mr *MockUserRepoMockRecorder) FetchCompanyMembers(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCompanyMembers", reflect.TypeOf((*MockUserRepo)(nil).FetchCompanyMembers), ctx, cid)
+}
+
+// FetchExternalIdentities mocks base method.
+func (m *MockUserRepo) FetchExternalIdentities(ctx context.Context, uid uint) ([]models.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExternalIdentities", ctx, uid)
+	ret0, _ := ret[0].([]models.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExternalIdentities indicates an expected call of FetchExternalIdentities.
+func (mr *MockUserRepoMockRecorder) FetchExternalIdentities(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExternalIdentities", reflect.TypeOf((*MockUserRepo)(nil).FetchExternalIdentities), ctx, uid)
+}
+
+// FetchExternalIdentity mocks base method.
+func (m *MockUserRepo) FetchExternalIdentity(ctx context.Context, provider, subject string) (models.ExternalIdentity, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExternalIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(models.ExternalIdentity)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchExternalIdentity indicates an expected call of FetchExternalIdentity.
+func (mr *MockUserRepoMockRecorder) FetchExternalIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExternalIdentity", reflect.TypeOf((*MockUserRepo)(nil).FetchExternalIdentity), ctx, provider, subject)
+}
+
 // FetchJobPostingByID mocks base method.
 func (m *MockUserRepo) FetchJobPostingByID(ctx context.Context, jid uint64) (models.Jobs, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +491,36 @@ func (mr *MockUserRepoMockRecorder) FetchJobPostingByID(ctx, jid any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchJobPostingByID", reflect.TypeOf((*MockUserRepo)(nil).FetchJobPostingByID), ctx, jid)
 }
 
+// FetchJobPreference mocks base method.
+func (m *MockUserRepo) FetchJobPreference(ctx context.Context, uid uint) (models.JobPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchJobPreference", ctx, uid)
+	ret0, _ := ret[0].(models.JobPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchJobPreference indicates an expected call of FetchJobPreference.
+func (mr *MockUserRepoMockRecorder) FetchJobPreference(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchJobPreference", reflect.TypeOf((*MockUserRepo)(nil).FetchJobPreference), ctx, uid)
+}
+
+// FetchJobPreferences mocks base method.
+func (m *MockUserRepo) FetchJobPreferences(ctx context.Context, afterUserID uint, limit int) ([]models.JobPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchJobPreferences", ctx, afterUserID, limit)
+	ret0, _ := ret[0].([]models.JobPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchJobPreferences indicates an expected call of FetchJobPreferences.
+func (mr *MockUserRepoMockRecorder) FetchJobPreferences(ctx, afterUserID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchJobPreferences", reflect.TypeOf((*MockUserRepo)(nil).FetchJobPreferences), ctx, afterUserID, limit)
+}
+
 // FetchJobsForCompany mocks base method.
 func (m *MockUserRepo) FetchJobsForCompany(ctx context.Context, cid uint64) ([]models.Jobs, error) {
 	m.ctrl.T.Helper()
@@ -114,49 +536,739 @@ func (mr *MockUserRepoMockRecorder) FetchJobsForCompany(ctx, cid any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchJobsForCompany", reflect.TypeOf((*MockUserRepo)(nil).FetchJobsForCompany), ctx, cid)
 }
 
-// InsertCompany mocks base method.
-func (m *MockUserRepo) InsertCompany(ctx context.Context, companyData models.Company) (models.Company, error) {
+// FetchLockoutEvents mocks base method.
+func (m *MockUserRepo) FetchLockoutEvents(ctx context.Context, limit int) ([]models.LockoutEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertCompany", ctx, companyData)
-	ret0, _ := ret[0].(models.Company)
+	ret := m.ctrl.Call(m, "FetchLockoutEvents", ctx, limit)
+	ret0, _ := ret[0].([]models.LockoutEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertCompany indicates an expected call of InsertCompany.
-func (mr *MockUserRepoMockRecorder) InsertCompany(ctx, companyData any) *gomock.Call {
+// FetchLockoutEvents indicates an expected call of FetchLockoutEvents.
+func (mr *MockUserRepoMockRecorder) FetchLockoutEvents(ctx, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCompany", reflect.TypeOf((*MockUserRepo)(nil).InsertCompany), ctx, companyData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchLockoutEvents", reflect.TypeOf((*MockUserRepo)(nil).FetchLockoutEvents), ctx, limit)
 }
 
-// InsertJobPosting mocks base method.
-func (m *MockUserRepo) InsertJobPosting(ctx context.Context, jobData models.NewJobRequest) (models.NewJobResponse, error) {
+// FetchLockoutEventsForKey mocks base method.
+func (m *MockUserRepo) FetchLockoutEventsForKey(ctx context.Context, key string) ([]models.LockoutEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertJobPosting", ctx, jobData)
-	ret0, _ := ret[0].(models.NewJobResponse)
+	ret := m.ctrl.Call(m, "FetchLockoutEventsForKey", ctx, key)
+	ret0, _ := ret[0].([]models.LockoutEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertJobPosting indicates an expected call of InsertJobPosting.
-func (mr *MockUserRepoMockRecorder) InsertJobPosting(ctx, jobData any) *gomock.Call {
+// FetchLockoutEventsForKey indicates an expected call of FetchLockoutEventsForKey.
+func (mr *MockUserRepoMockRecorder) FetchLockoutEventsForKey(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertJobPosting", reflect.TypeOf((*MockUserRepo)(nil).InsertJobPosting), ctx, jobData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchLockoutEventsForKey", reflect.TypeOf((*MockUserRepo)(nil).FetchLockoutEventsForKey), ctx, key)
 }
 
-// InsertUser mocks base method.
-func (m *MockUserRepo) InsertUser(ctx context.Context, userData models.User) (models.User, error) {
+// FetchMembershipsForUser mocks base method.
+func (m *MockUserRepo) FetchMembershipsForUser(ctx context.Context, uid uint) ([]models.CompanyMember, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUser", ctx, userData)
-	ret0, _ := ret[0].(models.User)
+	ret := m.ctrl.Call(m, "FetchMembershipsForUser", ctx, uid)
+	ret0, _ := ret[0].([]models.CompanyMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertUser indicates an expected call of InsertUser.
-func (mr *MockUserRepoMockRecorder) InsertUser(ctx, userData any) *gomock.Call {
+// FetchMembershipsForUser indicates an expected call of FetchMembershipsForUser.
+func (mr *MockUserRepoMockRecorder) FetchMembershipsForUser(ctx, uid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockUserRepo)(nil).InsertUser), ctx, userData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMembershipsForUser", reflect.TypeOf((*MockUserRepo)(nil).FetchMembershipsForUser), ctx, uid)
+}
+
+// FetchNotifications mocks base method.
+func (m *MockUserRepo) FetchNotifications(ctx context.Context, query models.NotificationQuery) ([]models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchNotifications", ctx, query)
+	ret0, _ := ret[0].([]models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchNotifications indicates an expected call of FetchNotifications.
+func (mr *MockUserRepoMockRecorder) FetchNotifications(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNotifications", reflect.TypeOf((*MockUserRepo)(nil).FetchNotifications), ctx, query)
+}
+
+// FetchOutboxMessages mocks base method.
+func (m *MockUserRepo) FetchOutboxMessages(ctx context.Context, status string, limit int) ([]models.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchOutboxMessages", ctx, status, limit)
+	ret0, _ := ret[0].([]models.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchOutboxMessages indicates an expected call of FetchOutboxMessages.
+func (mr *MockUserRepoMockRecorder) FetchOutboxMessages(ctx, status, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchOutboxMessages", reflect.TypeOf((*MockUserRepo)(nil).FetchOutboxMessages), ctx, status, limit)
+}
+
+// FetchRefreshToken mocks base method.
+func (m *MockUserRepo) FetchRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(models.Session)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchRefreshToken indicates an expected call of FetchRefreshToken.
+func (mr *MockUserRepoMockRecorder) FetchRefreshToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRefreshToken", reflect.TypeOf((*MockUserRepo)(nil).FetchRefreshToken), ctx, tokenHash)
+}
+
+// FetchUserSessions mocks base method.
+func (m *MockUserRepo) FetchUserSessions(ctx context.Context, uid uint) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchUserSessions", ctx, uid)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchUserSessions indicates an expected call of FetchUserSessions.
+func (mr *MockUserRepoMockRecorder) FetchUserSessions(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserSessions", reflect.TypeOf((*MockUserRepo)(nil).FetchUserSessions), ctx, uid)
+}
+
+// FetchUsersByIDs mocks base method.
+func (m *MockUserRepo) FetchUsersByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchUsersByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchUsersByIDs indicates an expected call of FetchUsersByIDs.
+func (mr *MockUserRepoMockRecorder) FetchUsersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUsersByIDs", reflect.TypeOf((*MockUserRepo)(nil).FetchUsersByIDs), ctx, ids)
+}
+
+// FetchWebhookDeliveries mocks base method.
+func (m *MockUserRepo) FetchWebhookDeliveries(ctx context.Context, endpointID uint, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchWebhookDeliveries", ctx, endpointID, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchWebhookDeliveries indicates an expected call of FetchWebhookDeliveries.
+func (mr *MockUserRepoMockRecorder) FetchWebhookDeliveries(ctx, endpointID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchWebhookDeliveries", reflect.TypeOf((*MockUserRepo)(nil).FetchWebhookDeliveries), ctx, endpointID, limit)
+}
+
+// FetchWebhookEndpoint mocks base method.
+func (m *MockUserRepo) FetchWebhookEndpoint(ctx context.Context, id uint) (models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchWebhookEndpoint", ctx, id)
+	ret0, _ := ret[0].(models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchWebhookEndpoint indicates an expected call of FetchWebhookEndpoint.
+func (mr *MockUserRepoMockRecorder) FetchWebhookEndpoint(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchWebhookEndpoint", reflect.TypeOf((*MockUserRepo)(nil).FetchWebhookEndpoint), ctx, id)
+}
+
+// FetchWebhookEndpoints mocks base method.
+func (m *MockUserRepo) FetchWebhookEndpoints(ctx context.Context, cid uint) ([]models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchWebhookEndpoints", ctx, cid)
+	ret0, _ := ret[0].([]models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchWebhookEndpoints indicates an expected call of FetchWebhookEndpoints.
+func (mr *MockUserRepoMockRecorder) FetchWebhookEndpoints(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchWebhookEndpoints", reflect.TypeOf((*MockUserRepo)(nil).FetchWebhookEndpoints), ctx, cid)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockUserRepo) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUserRepoMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepo)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(ctx context.Context, uid uint64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, uid)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), ctx, uid)
+}
+
+// InsertAPIKey mocks base method.
+func (m *MockUserRepo) InsertAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAPIKey", ctx, key)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAPIKey indicates an expected call of InsertAPIKey.
+func (mr *MockUserRepoMockRecorder) InsertAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAPIKey", reflect.TypeOf((*MockUserRepo)(nil).InsertAPIKey), ctx, key)
+}
+
+// InsertAuditEvent mocks base method.
+func (m *MockUserRepo) InsertAuditEvent(ctx context.Context, event models.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditEvent indicates an expected call of InsertAuditEvent.
+func (mr *MockUserRepoMockRecorder) InsertAuditEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditEvent", reflect.TypeOf((*MockUserRepo)(nil).InsertAuditEvent), ctx, event)
+}
+
+// InsertCompany mocks base method.
+func (m *MockUserRepo) InsertCompany(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCompany", ctx, companyData, ownerID)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCompany indicates an expected call of InsertCompany.
+func (mr *MockUserRepoMockRecorder) InsertCompany(ctx, companyData, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCompany", reflect.TypeOf((*MockUserRepo)(nil).InsertCompany), ctx, companyData, ownerID)
+}
+
+// InsertExternalIdentity mocks base method.
+func (m *MockUserRepo) InsertExternalIdentity(ctx context.Context, identity models.ExternalIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertExternalIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertExternalIdentity indicates an expected call of InsertExternalIdentity.
+func (mr *MockUserRepoMockRecorder) InsertExternalIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertExternalIdentity", reflect.TypeOf((*MockUserRepo)(nil).InsertExternalIdentity), ctx, identity)
+}
+
+// InsertJobPosting mocks base method.
+func (m *MockUserRepo) InsertJobPosting(ctx context.Context, jobData models.NewJobRequest) (models.NewJobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertJobPosting", ctx, jobData)
+	ret0, _ := ret[0].(models.NewJobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertJobPosting indicates an expected call of InsertJobPosting.
+func (mr *MockUserRepoMockRecorder) InsertJobPosting(ctx, jobData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertJobPosting", reflect.TypeOf((*MockUserRepo)(nil).InsertJobPosting), ctx, jobData)
+}
+
+// InsertLockoutEvent mocks base method.
+func (m *MockUserRepo) InsertLockoutEvent(ctx context.Context, event models.LockoutEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLockoutEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertLockoutEvent indicates an expected call of InsertLockoutEvent.
+func (mr *MockUserRepoMockRecorder) InsertLockoutEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLockoutEvent", reflect.TypeOf((*MockUserRepo)(nil).InsertLockoutEvent), ctx, event)
+}
+
+// InsertNotifications mocks base method.
+func (m *MockUserRepo) InsertNotifications(ctx context.Context, notifications ...models.Notification) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range notifications {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InsertNotifications", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertNotifications indicates an expected call of InsertNotifications.
+func (mr *MockUserRepoMockRecorder) InsertNotifications(ctx any, notifications ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, notifications...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotifications", reflect.TypeOf((*MockUserRepo)(nil).InsertNotifications), varargs...)
+}
+
+// InsertOIDCLoginState mocks base method.
+func (m *MockUserRepo) InsertOIDCLoginState(ctx context.Context, state models.OIDCLoginState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOIDCLoginState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOIDCLoginState indicates an expected call of InsertOIDCLoginState.
+func (mr *MockUserRepoMockRecorder) InsertOIDCLoginState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOIDCLoginState", reflect.TypeOf((*MockUserRepo)(nil).InsertOIDCLoginState), ctx, state)
+}
+
+// InsertSession mocks base method.
+func (m *MockUserRepo) InsertSession(ctx context.Context, session models.Session, refreshToken models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSession", ctx, session, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSession indicates an expected call of InsertSession.
+func (mr *MockUserRepoMockRecorder) InsertSession(ctx, session, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSession", reflect.TypeOf((*MockUserRepo)(nil).InsertSession), ctx, session, refreshToken)
+}
+
+// InsertUser mocks base method.
+func (m *MockUserRepo) InsertUser(ctx context.Context, userData models.User) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUser", ctx, userData)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUser indicates an expected call of InsertUser.
+func (mr *MockUserRepoMockRecorder) InsertUser(ctx, userData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockUserRepo)(nil).InsertUser), ctx, userData)
+}
+
+// InsertUserWithIdentity mocks base method.
+func (m *MockUserRepo) InsertUserWithIdentity(ctx context.Context, user models.User, identity models.ExternalIdentity) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserWithIdentity", ctx, user, identity)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserWithIdentity indicates an expected call of InsertUserWithIdentity.
+func (mr *MockUserRepoMockRecorder) InsertUserWithIdentity(ctx, user, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserWithIdentity", reflect.TypeOf((*MockUserRepo)(nil).InsertUserWithIdentity), ctx, user, identity)
+}
+
+// InsertWebhookDelivery mocks base method.
+func (m *MockUserRepo) InsertWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockUserRepoMockRecorder) InsertWebhookDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockUserRepo)(nil).InsertWebhookDelivery), ctx, delivery)
+}
+
+// InsertWebhookEndpoint mocks base method.
+func (m *MockUserRepo) InsertWebhookEndpoint(ctx context.Context, endpoint models.WebhookEndpoint) (models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookEndpoint", ctx, endpoint)
+	ret0, _ := ret[0].(models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookEndpoint indicates an expected call of InsertWebhookEndpoint.
+func (mr *MockUserRepoMockRecorder) InsertWebhookEndpoint(ctx, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookEndpoint", reflect.TypeOf((*MockUserRepo)(nil).InsertWebhookEndpoint), ctx, endpoint)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockUserRepo) IsTokenRevoked(ctx context.Context, jti, sessionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockUserRepoMockRecorder) IsTokenRevoked(ctx, jti, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockUserRepo)(nil).IsTokenRevoked), ctx, jti, sessionID)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockUserRepo) MarkAllNotificationsRead(ctx context.Context, uid uint, readAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, uid, readAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockUserRepoMockRecorder) MarkAllNotificationsRead(ctx, uid, readAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockUserRepo)(nil).MarkAllNotificationsRead), ctx, uid, readAt)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepo) MarkEmailVerified(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepoMockRecorder) MarkEmailVerified(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepo)(nil).MarkEmailVerified), ctx, uid)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockUserRepo) MarkNotificationRead(ctx context.Context, uid, id uint, readAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, uid, id, readAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockUserRepoMockRecorder) MarkNotificationRead(ctx, uid, id, readAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockUserRepo)(nil).MarkNotificationRead), ctx, uid, id, readAt)
+}
+
+// MarkOutboxDelivered mocks base method.
+func (m *MockUserRepo) MarkOutboxDelivered(ctx context.Context, id uint, deliveredAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxDelivered", ctx, id, deliveredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxDelivered indicates an expected call of MarkOutboxDelivered.
+func (mr *MockUserRepoMockRecorder) MarkOutboxDelivered(ctx, id, deliveredAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxDelivered", reflect.TypeOf((*MockUserRepo)(nil).MarkOutboxDelivered), ctx, id, deliveredAt)
+}
+
+// RecordOutboxFailure mocks base method.
+func (m *MockUserRepo) RecordOutboxFailure(ctx context.Context, id uint, attempts int, lastError, status string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOutboxFailure", ctx, id, attempts, lastError, status, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOutboxFailure indicates an expected call of RecordOutboxFailure.
+func (mr *MockUserRepoMockRecorder) RecordOutboxFailure(ctx, id, attempts, lastError, status, nextAttemptAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxFailure", reflect.TypeOf((*MockUserRepo)(nil).RecordOutboxFailure), ctx, id, attempts, lastError, status, nextAttemptAt)
+}
+
+// ReplayOutboxMessage mocks base method.
+func (m *MockUserRepo) ReplayOutboxMessage(ctx context.Context, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayOutboxMessage", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayOutboxMessage indicates an expected call of ReplayOutboxMessage.
+func (mr *MockUserRepoMockRecorder) ReplayOutboxMessage(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayOutboxMessage", reflect.TypeOf((*MockUserRepo)(nil).ReplayOutboxMessage), ctx, id)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockUserRepo) RevokeAPIKey(ctx context.Context, keyID uint, cid *uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, keyID, cid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockUserRepoMockRecorder) RevokeAPIKey(ctx, keyID, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockUserRepo)(nil).RevokeAPIKey), ctx, keyID, cid)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUserRepo) RevokeSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUserRepoMockRecorder) RevokeSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUserRepo)(nil).RevokeSession), ctx, sessionID)
+}
+
+// RevokeToken mocks base method.
+func (m *MockUserRepo) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockUserRepoMockRecorder) RevokeToken(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockUserRepo)(nil).RevokeToken), ctx, jti, expiresAt)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockUserRepo) RevokeUserSessions(ctx context.Context, uid uint, exceptSessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", ctx, uid, exceptSessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockUserRepoMockRecorder) RevokeUserSessions(ctx, uid, exceptSessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockUserRepo)(nil).RevokeUserSessions), ctx, uid, exceptSessionID)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockUserRepo) RotateRefreshToken(ctx context.Context, usedID uint, next models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, usedID, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockUserRepoMockRecorder) RotateRefreshToken(ctx, usedID, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockUserRepo)(nil).RotateRefreshToken), ctx, usedID, next)
+}
+
+// SaveCompanyMember mocks base method.
+func (m *MockUserRepo) SaveCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCompanyMember", ctx, member)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCompanyMember indicates an expected call of SaveCompanyMember.
+func (mr *MockUserRepoMockRecorder) SaveCompanyMember(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCompanyMember", reflect.TypeOf((*MockUserRepo)(nil).SaveCompanyMember), ctx, member)
+}
+
+// SaveJobPreference mocks base method.
+func (m *MockUserRepo) SaveJobPreference(ctx context.Context, preference models.JobPreference) (models.JobPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveJobPreference", ctx, preference)
+	ret0, _ := ret[0].(models.JobPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveJobPreference indicates an expected call of SaveJobPreference.
+func (mr *MockUserRepoMockRecorder) SaveJobPreference(ctx, preference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveJobPreference", reflect.TypeOf((*MockUserRepo)(nil).SaveJobPreference), ctx, preference)
+}
+
+// SaveTOTPPendingSecret mocks base method.
+func (m *MockUserRepo) SaveTOTPPendingSecret(ctx context.Context, uid uint, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTOTPPendingSecret", ctx, uid, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTOTPPendingSecret indicates an expected call of SaveTOTPPendingSecret.
+func (mr *MockUserRepoMockRecorder) SaveTOTPPendingSecret(ctx, uid, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTPPendingSecret", reflect.TypeOf((*MockUserRepo)(nil).SaveTOTPPendingSecret), ctx, uid, secret)
+}
+
+// SetPendingEmail mocks base method.
+func (m *MockUserRepo) SetPendingEmail(ctx context.Context, uid uint, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingEmail", ctx, uid, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingEmail indicates an expected call of SetPendingEmail.
+func (mr *MockUserRepoMockRecorder) SetPendingEmail(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingEmail", reflect.TypeOf((*MockUserRepo)(nil).SetPendingEmail), ctx, uid, email)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockUserRepo) TouchAPIKey(ctx context.Context, keyID uint, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, keyID, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockUserRepoMockRecorder) TouchAPIKey(ctx, keyID, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockUserRepo)(nil).TouchAPIKey), ctx, keyID, usedAt)
+}
+
+// Transaction mocks base method.
+func (m *MockUserRepo) Transaction(ctx context.Context, fn func(UserRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockUserRepoMockRecorder) Transaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockUserRepo)(nil).Transaction), ctx, fn)
+}
+
+// UpdateJobPosting mocks base method.
+func (m *MockUserRepo) UpdateJobPosting(ctx context.Context, jid uint64, jobData models.NewJobRequest) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobPosting", ctx, jid, jobData)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJobPosting indicates an expected call of UpdateJobPosting.
+func (mr *MockUserRepoMockRecorder) UpdateJobPosting(ctx, jid, jobData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobPosting", reflect.TypeOf((*MockUserRepo)(nil).UpdateJobPosting), ctx, jid, jobData)
+}
+
+// UpdateLocale mocks base method.
+func (m *MockUserRepo) UpdateLocale(ctx context.Context, uid uint, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocale", ctx, uid, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocale indicates an expected call of UpdateLocale.
+func (mr *MockUserRepoMockRecorder) UpdateLocale(ctx, uid, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocale", reflect.TypeOf((*MockUserRepo)(nil).UpdateLocale), ctx, uid, locale)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepo) UpdatePassword(ctx context.Context, email, hashedPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, email, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepoMockRecorder) UpdatePassword(ctx, email, hashedPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepo)(nil).UpdatePassword), ctx, email, hashedPassword)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockUserRepo) UpdateUserRole(ctx context.Context, uid uint64, role string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, uid, role)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockUserRepoMockRecorder) UpdateUserRole(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockUserRepo)(nil).UpdateUserRole), ctx, uid, role)
+}
+
+// UpdateUsername mocks base method.
+func (m *MockUserRepo) UpdateUsername(ctx context.Context, uid uint, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUsername", ctx, uid, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUsername indicates an expected call of UpdateUsername.
+func (mr *MockUserRepoMockRecorder) UpdateUsername(ctx, uid, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUsername", reflect.TypeOf((*MockUserRepo)(nil).UpdateUsername), ctx, uid, username)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockUserRepo) UseRecoveryCode(ctx context.Context, uid uint, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, uid, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockUserRepoMockRecorder) UseRecoveryCode(ctx, uid, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockUserRepo)(nil).UseRecoveryCode), ctx, uid, codeHash)
 }
 
 // VerifyUserCredentials mocks base method.
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"job-portal-api/internal/models"
+)
+
+// InsertSession stores a new login session together with its first refresh token.
+func (r *Repo) InsertSession(ctx context.Context, session models.Session, refreshToken models.RefreshToken) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&session).Error
+		if err != nil {
+			return err
+		}
+		refreshToken.SessionID = session.ID
+		return tx.Create(&refreshToken).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to create session")
+	}
+	return nil
+}
+
+// FetchRefreshToken looks up a refresh token by its hash along with the session it belongs to.
+func (r *Repo) FetchRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, models.Session, error) {
+	var token models.RefreshToken
+	err := r.DB.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.RefreshToken{}, models.Session{}, errors.New("invalid refresh token")
+	}
+	var session models.Session
+	err = r.DB.WithContext(ctx).Where("id = ?", token.SessionID).First(&session).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.RefreshToken{}, models.Session{}, errors.New("invalid refresh token")
+	}
+	return token, session, nil
+}
+
+// RotateRefreshToken marks the used token and stores its successor. It fails if the token
+// was already used, so two concurrent refreshes with the same token cannot both succeed.
+func (r *Repo) RotateRefreshToken(ctx context.Context, usedID uint, next models.RefreshToken) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).Where("id = ? AND used_at IS NULL", usedID).Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("refresh token already used")
+		}
+		return tx.Create(&next).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to rotate refresh token")
+	}
+	return nil
+}
+
+func (r *Repo) RevokeSession(ctx context.Context, sessionID string) error {
+	err := r.DB.WithContext(ctx).Model(&models.Session{}).Where("id = ? AND revoked_at IS NULL", sessionID).Update("revoked_at", time.Now()).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to revoke session")
+	}
+	return nil
+}
+
+// RevokeUserSessions revokes every open session of the user except exceptSessionID, which
+// may be empty to revoke them all.
+func (r *Repo) RevokeUserSessions(ctx context.Context, uid uint, exceptSessionID string) error {
+	query := r.DB.WithContext(ctx).Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", uid)
+	if exceptSessionID != "" {
+		query = query.Where("id <> ?", exceptSessionID)
+	}
+	err := query.Update("revoked_at", time.Now()).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
+
+// RevokeToken adds an access token to the revocation list.
+func (r *Repo) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+	}).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to revoke token")
+	}
+	return nil
+}
+
+// DeleteExpiredRevokedTokens drops the revocation list entries of tokens that expired
+// before the given time, which would be rejected anyway.
+func (r *Repo) DeleteExpiredRevokedTokens(ctx context.Context, before time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.RevokedToken{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return 0, errors.New("failed to delete expired revoked tokens")
+	}
+	return result.RowsAffected, nil
+}
+
+// IsTokenRevoked implements auth.RevocationList.
+func (r *Repo) IsTokenRevoked(ctx context.Context, jti string, sessionID string) (bool, error) {
+	var count int64
+	if jti != "" {
+		err := r.DB.WithContext(ctx).Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	if sessionID != "" {
+		err := r.DB.WithContext(ctx).Model(&models.Session{}).Where("id = ? AND revoked_at IS NOT NULL", sessionID).Count(&count).Error
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
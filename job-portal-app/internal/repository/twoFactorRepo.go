@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+)
+
+// SaveTOTPPendingSecret stores a secret that is being enrolled. The active secret, if any,
+// keeps working until the new one is confirmed.
+func (r *Repo) SaveTOTPPendingSecret(ctx context.Context, uid uint, secret string) error {
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", uid).Update("totp_pending_secret", secret).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to start two-factor enrolment")
+	}
+	return nil
+}
+
+// EnableTOTP makes the pending secret the active one, records the step of the code that
+// confirmed it and replaces the user's recovery codes.
+func (r *Repo) EnableTOTP(ctx context.Context, uid uint, step int64, codes []models.RecoveryCode) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ? AND totp_pending_secret <> ''", uid).Updates(map[string]interface{}{
+			"two_factor_enabled":  true,
+			"totp_secret":         gorm.Expr("totp_pending_secret"),
+			"totp_pending_secret": "",
+			"totp_last_step":      step,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no pending enrolment")
+		}
+		err := tx.Where("user_id = ?", uid).Delete(&models.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to enable two-factor authentication")
+	}
+	return nil
+}
+
+// DisableTOTP removes the user's secrets and recovery codes.
+func (r *Repo) DisableTOTP(ctx context.Context, uid uint) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", uid).Updates(map[string]interface{}{
+			"two_factor_enabled":  false,
+			"totp_secret":         "",
+			"totp_pending_secret": "",
+			"totp_last_step":      0,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", uid).Delete(&models.RecoveryCode{}).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to disable two-factor authentication")
+	}
+	return nil
+}
+
+// AdvanceTOTPStep records step as the last accepted TOTP step. It reports false if an
+// equal or later step was already accepted, which means the code was replayed.
+func (r *Repo) AdvanceTOTPStep(ctx context.Context, uid uint, step int64) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ? AND totp_last_step < ?", uid, step).Update("totp_last_step", step)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return false, errors.New("failed to verify the code")
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UseRecoveryCode marks the recovery code as used and reports whether it was valid and unused.
+func (r *Repo) UseRecoveryCode(ctx context.Context, uid uint, codeHash string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", uid, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return false, errors.New("failed to verify the code")
+	}
+	return result.RowsAffected == 1, nil
+}
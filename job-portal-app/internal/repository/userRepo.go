@@ -3,13 +3,14 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"job-portal-api/internal/models"
 )
 
 func (r *Repo) InsertUser(ctx context.Context, UserDetails models.User) (models.User, error) {
-	result := r.DB.Create(&UserDetails)
+	result := r.DB.WithContext(ctx).Create(&UserDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return models.User{}, errors.New("failed to create the user")
@@ -19,7 +20,7 @@ func (r *Repo) InsertUser(ctx context.Context, UserDetails models.User) (models.
 
 func (r *Repo) VerifyUserCredentials(ctx context.Context, email string) (models.User, error) {
 	var userDetails models.User
-	result := r.DB.Where("email = ?", email).First(&userDetails)
+	result := r.DB.WithContext(ctx).Where("lower(email) = lower(?)", email).First(&userDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return models.User{}, errors.New("authentication failed")
@@ -27,9 +28,10 @@ func (r *Repo) VerifyUserCredentials(ctx context.Context, email string) (models.
 	return userDetails, nil
 }
 
+// GetUserByEmail finds the user by email, ignoring case.
 func (r *Repo) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
 	var userDetails models.User
-	result := r.DB.Where("email = ?", email).First(&userDetails)
+	result := r.DB.WithContext(ctx).Where("lower(email) = lower(?)", email).First(&userDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
 		return models.User{}, errors.New("user not found")
@@ -37,18 +39,40 @@ func (r *Repo) GetUserByEmail(ctx context.Context, email string) (models.User, e
 	return userDetails, nil
 }
 
+func (r *Repo) GetUserByID(ctx context.Context, uid uint64) (models.User, error) {
+	var userDetails models.User
+	result := r.DB.WithContext(ctx).Where("id = ?", uid).First(&userDetails)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.User{}, errors.New("user not found")
+	}
+	return userDetails, nil
+}
+
+// MarkEmailVerified records that the user has verified their email address.
+func (r *Repo) MarkEmailVerified(ctx context.Context, uid uint) error {
+	result := r.DB.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", uid).
+		Update("email_verified_at", time.Now())
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return errors.New("could not verify the email")
+	}
+	return nil
+}
+
 // UpdatePassword updates the user's password in the database.
 func (r *Repo) UpdatePassword(ctx context.Context, email, hashedPassword string) error {
 	// Assuming you have a User model with an Email field
 	user := &models.User{}
-	err := r.DB.Where("email = ?", email).First(user).Error
+	err := r.DB.WithContext(ctx).Where("lower(email) = lower(?)", email).First(user).Error
 	if err != nil {
 		log.Printf("Error finding user with email %s: %v", email, err)
 		return errors.New("user not found")
 	}
 	// Update the user's password
 	user.PasswordHash = hashedPassword
-	err = r.DB.Save(user).Error
+	err = r.DB.WithContext(ctx).Save(user).Error
 	if err != nil {
 		log.Printf("Error updating password for user with email %s: %v", email, err)
 		return errors.New("failed to update password")
@@ -56,3 +80,20 @@ func (r *Repo) UpdatePassword(ctx context.Context, email, hashedPassword string)
 
 	return nil
 }
+
+// UpdateUserRole sets the role of the user with the given ID and returns the updated user.
+func (r *Repo) UpdateUserRole(ctx context.Context, uid uint64, role string) (models.User, error) {
+	var user models.User
+	err := r.DB.WithContext(ctx).Where("id = ?", uid).First(&user).Error
+	if err != nil {
+		log.Info().Err(err).Uint64("user id", uid).Send()
+		return models.User{}, errors.New("user not found")
+	}
+	err = r.DB.WithContext(ctx).Model(&user).Update("role", role).Error
+	if err != nil {
+		log.Info().Err(err).Uint64("user id", uid).Send()
+		return models.User{}, errors.New("failed to update user role")
+	}
+	user.Role = role
+	return user, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) InsertWebhookEndpoint(ctx context.Context, endpoint models.WebhookEndpoint) (models.WebhookEndpoint, error) {
+	err := r.DB.WithContext(ctx).Create(&endpoint).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.WebhookEndpoint{}, errors.New("failed to create the webhook endpoint")
+	}
+	return endpoint, nil
+}
+
+// FetchWebhookEndpoints lists the endpoints of a company.
+func (r *Repo) FetchWebhookEndpoints(ctx context.Context, cid uint) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.DB.WithContext(ctx).Where("company_id = ?", cid).Order("id").Find(&endpoints).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the webhook endpoints")
+	}
+	return endpoints, nil
+}
+
+// FetchWebhookEndpoint returns the endpoint with the id, or one with ID 0 when there is none.
+func (r *Repo) FetchWebhookEndpoint(ctx context.Context, id uint) (models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := r.DB.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&endpoint).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.WebhookEndpoint{}, errors.New("could not fetch the webhook endpoint")
+	}
+	return endpoint, nil
+}
+
+// DeleteWebhookEndpoint removes the endpoint if it belongs to the company. Its delivery log
+// goes with it. It reports false when there is no such endpoint.
+func (r *Repo) DeleteWebhookEndpoint(ctx context.Context, cid uint, id uint) (bool, error) {
+	var deleted bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND company_id = ?", id, cid).Delete(&models.WebhookEndpoint{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return false, errors.New("failed to delete the webhook endpoint")
+	}
+	return deleted, nil
+}
+
+func (r *Repo) InsertWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	err := r.DB.WithContext(ctx).Create(&delivery).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("failed to record the webhook delivery")
+	}
+	return nil
+}
+
+// FetchWebhookDeliveries returns the latest delivery attempts to an endpoint, newest first.
+func (r *Repo) FetchWebhookDeliveries(ctx context.Context, endpointID uint, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.DB.WithContext(ctx).Where("endpoint_id = ?", endpointID).Order("id desc").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("could not fetch the webhook deliveries")
+	}
+	return deliveries, nil
+}
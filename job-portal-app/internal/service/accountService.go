@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+)
+
+var (
+	ErrUsernameTaken    = errors.New("username is already taken")
+	ErrEmailTaken       = errors.New("email is already in use")
+	ErrLastCompanyOwner = errors.New("you are the last owner of a company, hand it over to another member first")
+	// ErrEmailChangeUnavailable is returned when no secret is configured to sign the link
+	// that confirms a new email.
+	ErrEmailChangeUnavailable = errors.New("the email address cannot be changed")
+)
+
+// GetProfileService returns the logged in user.
+func (s *Service) GetProfileService(ctx context.Context, uid uint) (models.User, error) {
+	return s.UserRepo.GetUserByID(ctx, uint64(uid))
+}
+
+// UpdateProfileService changes the username right away. A new email only replaces the
+// current one once the link sent to it is opened; until then it is kept as the pending email.
+func (s *Service) UpdateProfileService(ctx context.Context, uid uint, data models.UpdateProfileRequest) (models.User, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(uid))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if data.Username != nil && *data.Username != user.Username {
+		ok, err := s.UserRepo.UpdateUsername(ctx, uid, strings.TrimSpace(*data.Username))
+		if err != nil {
+			return models.User{}, err
+		}
+		if !ok {
+			return models.User{}, ErrUsernameTaken
+		}
+	}
+
+	if data.Locale != nil && *data.Locale != user.Locale {
+		err = s.UserRepo.UpdateLocale(ctx, uid, *data.Locale)
+		if err != nil {
+			return models.User{}, err
+		}
+		// the email change below is confirmed in the new language
+		user.Locale = *data.Locale
+	}
+
+	if data.Email != nil && normalizeEmail(*data.Email) != normalizeEmail(user.Email) {
+		err = s.changeEmail(ctx, user, normalizeEmail(*data.Email), data.CurrentPassword)
+		if err != nil {
+			return models.User{}, err
+		}
+	}
+
+	return s.UserRepo.GetUserByID(ctx, uint64(uid))
+}
+
+func (s *Service) changeEmail(ctx context.Context, user models.User, email, password string) error {
+	// without a secret there is no way to sign a link, and the new email is never taken unconfirmed
+	if s.cfg.AuthConfig.EmailVerificationSecret == "" {
+		return ErrEmailChangeUnavailable
+	}
+	err := confirmPassword(user, password)
+	if err != nil {
+		return err
+	}
+	taken, err := s.UserRepo.EmailInUse(ctx, user.ID, email)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+
+	token := s.signVerificationToken(user.ID, email, time.Now().Add(emailVerificationTTL))
+	link := fmt.Sprintf("%s/api/verify-email?token=%s", strings.TrimSuffix(s.cfg.AppConfig.BaseURL, "/"), url.QueryEscape(token))
+	confirm, err := newEmail(emailLocale(ctx, user), email, mail.TemplateConfirmEmailChange, mail.LinkData{Link: link, Hours: int(emailVerificationTTL.Hours())})
+	if err != nil {
+		return err
+	}
+	// tell the current address too, in case someone else is changing it
+	notice, err := newEmail(emailLocale(ctx, user), user.Email, mail.TemplateEmailChangeNotice, nil)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.Transaction(ctx, func(tx repository.UserRepo) error {
+		err := tx.SetPendingEmail(ctx, user.ID, email)
+		if err != nil {
+			return err
+		}
+		return tx.EnqueueOutbox(ctx, confirm, notice)
+	})
+}
+
+// ExportAccountService collects everything held about the user.
+func (s *Service) ExportAccountService(ctx context.Context, uid uint) (models.AccountExport, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(uid))
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	sessions, err := s.UserRepo.FetchUserSessions(ctx, uid)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	memberships, err := s.UserRepo.FetchMembershipsForUser(ctx, uid)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	keys, err := s.UserRepo.FetchAPIKeysCreatedBy(ctx, uid)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	identities, err := s.UserRepo.FetchExternalIdentities(ctx, uid)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	lockouts, err := s.UserRepo.FetchLockoutEventsForKey(ctx, normalizeEmail(user.Email))
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	notifications, err := s.UserRepo.FetchNotifications(ctx, models.NotificationQuery{UserID: uid})
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	preference, err := s.UserRepo.FetchJobPreference(ctx, uid)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+	// a negative limit fetches every entry
+	auditEvents, err := s.UserRepo.FetchAuditEvents(ctx, models.AuditQuery{UserID: uid, Limit: -1})
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+
+	export := models.AccountExport{
+		ExportedAt:         time.Now().UTC(),
+		User:               user,
+		Sessions:           make([]models.ExportedSession, 0, len(sessions)),
+		CompanyMemberships: memberships,
+		APIKeysCreated:     keys,
+		ExternalIdentities: identities,
+		LockoutEvents:      lockouts,
+		Notifications:      notifications,
+		AuditEvents:        auditEvents,
+	}
+	if preference.UserID != 0 {
+		export.JobPreference = &preference
+	}
+	for _, session := range sessions {
+		export.Sessions = append(export.Sessions, models.ExportedSession{
+			ID:        session.ID,
+			CreatedAt: session.CreatedAt,
+			RevokedAt: session.RevokedAt,
+		})
+	}
+	return export, nil
+}
+
+// DeleteAccountService deletes the account of the logged in user, revokes the api keys they
+// created and logs out every session. The user row is anonymised by the repository.
+func (s *Service) DeleteAccountService(ctx context.Context, uid uint, password string) error {
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(uid))
+	if err != nil {
+		return err
+	}
+	err = confirmPassword(user, password)
+	if err != nil {
+		return err
+	}
+
+	memberships, err := s.UserRepo.FetchMembershipsForUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, membership := range memberships {
+		if membership.Role != models.MemberRoleOwner {
+			continue
+		}
+		members, err := s.UserRepo.FetchCompanyMembers(ctx, uint64(membership.CompanyID))
+		if err != nil {
+			return err
+		}
+		if countOwners(members) <= 1 {
+			return ErrLastCompanyOwner
+		}
+	}
+
+	err = s.UserRepo.DeleteUserAccount(ctx, uid)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint("user id", uid).Msg("account deleted")
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditAccountDeleted, TargetUserID: uintRef(uid)})
+	return nil
+}
+
+// confirmPassword checks the password of a sensitive change. Accounts created through an
+// identity provider have no password and skip the check.
+func confirmPassword(user models.User, password string) error {
+	if user.PasswordHash == "" {
+		return nil
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		log.Warn().Uint("user id", user.ID).Msg("invalid password")
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+func countOwners(members []models.CompanyMember) int {
+	owners := 0
+	for _, member := range members {
+		if member.Role == models.MemberRoleOwner {
+			owners++
+		}
+	}
+	return owners
+}
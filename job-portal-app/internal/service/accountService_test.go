@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/i18n"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+)
+
+func TestService_UpdateProfileService(t *testing.T) {
+	const validHash = "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y" // Valid hash for "validpassword"
+	user := models.User{Username: "jane", Email: "jane@example.com", PasswordHash: validHash}
+	user.ID = 1
+	username := func(v string) *string { return &v }
+	email := username
+
+	tests := []struct {
+		name string
+		data models.UpdateProfileRequest
+		// secret signs the links that confirm a new email
+		secret  string
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name: "username taken",
+			data: models.UpdateProfileRequest{Username: username("john")},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+				mockRepo.EXPECT().UpdateUsername(gomock.Any(), uint(1), "john").Return(false, nil).Times(1)
+			},
+			wantErr: ErrUsernameTaken,
+		},
+		{
+			name:   "email change with the wrong password",
+			data:   models.UpdateProfileRequest{Email: email("new@example.com"), CurrentPassword: "wrongpassword"},
+			secret: "secret",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+			},
+			wantErr: ErrInvalidPassword,
+		},
+		{
+			name:   "email in use",
+			data:   models.UpdateProfileRequest{Email: email("New@Example.com"), CurrentPassword: "validpassword"},
+			secret: "secret",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+				mockRepo.EXPECT().EmailInUse(gomock.Any(), uint(1), "new@example.com").Return(true, nil).Times(1)
+			},
+			wantErr: ErrEmailTaken,
+		},
+		{
+			name: "same email is not a change",
+			data: models.UpdateProfileRequest{Email: email("JANE@example.com")},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(2)
+			},
+		},
+		{
+			name: "email change without verification configured",
+			data: models.UpdateProfileRequest{Username: username("jane"), Email: email("new@example.com"), CurrentPassword: "validpassword"},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+			},
+			wantErr: ErrEmailChangeUnavailable,
+		},
+		{
+			name: "locale changed",
+			data: models.UpdateProfileRequest{Locale: username("hi")},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(2)
+				mockRepo.EXPECT().UpdateLocale(gomock.Any(), uint(1), "hi").Return(nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setup(mockRepo)
+			cfg := config.Config{}
+			cfg.AuthConfig.EmailVerificationSecret = tt.secret
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, cfg)
+			_, err := s.UpdateProfileService(context.Background(), 1, tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateProfileService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_DeleteAccountService(t *testing.T) {
+	const validHash = "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y" // Valid hash for "validpassword"
+	user := models.User{Email: "jane@example.com", PasswordHash: validHash}
+	user.ID = 1
+	owner := func(uid uint) models.CompanyMember {
+		return models.CompanyMember{CompanyID: 5, UserID: uid, Role: models.MemberRoleOwner}
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		setup    func(mockRepo *repository.MockUserRepo)
+		wantErr  error
+	}{
+		{
+			name:     "wrong password",
+			password: "wrongpassword",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+			},
+			wantErr: ErrInvalidPassword,
+		},
+		{
+			name:     "last owner of a company",
+			password: "validpassword",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+				mockRepo.EXPECT().FetchMembershipsForUser(gomock.Any(), uint(1)).Return([]models.CompanyMember{owner(1)}, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMembers(gomock.Any(), uint64(5)).Return([]models.CompanyMember{owner(1), {CompanyID: 5, UserID: 2, Role: models.MemberRoleRecruiter}}, nil).Times(1)
+			},
+			wantErr: ErrLastCompanyOwner,
+		},
+		{
+			name:     "success",
+			password: "validpassword",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+				mockRepo.EXPECT().FetchMembershipsForUser(gomock.Any(), uint(1)).Return([]models.CompanyMember{owner(1)}, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMembers(gomock.Any(), uint64(5)).Return([]models.CompanyMember{owner(1), owner(2)}, nil).Times(1)
+				mockRepo.EXPECT().DeleteUserAccount(gomock.Any(), uint(1)).Return(nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			err := s.DeleteAccountService(context.Background(), 1, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteAccountService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_UpdateProfileService_emailChangeLocale(t *testing.T) {
+	const validHash = "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y" // Valid hash for "validpassword"
+	user := models.User{Username: "jane", Email: "jane@example.com", PasswordHash: validHash}
+	user.ID = 1
+	locale, email := "hi", "new@example.com"
+
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(2)
+	mockRepo.EXPECT().UpdateLocale(gomock.Any(), uint(1), "hi").Return(nil).Times(1)
+	mockRepo.EXPECT().EmailInUse(gomock.Any(), uint(1), "new@example.com").Return(false, nil).Times(1)
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+		return fn(mockRepo)
+	}).Times(1)
+	mockRepo.EXPECT().SetPendingEmail(gomock.Any(), uint(1), "new@example.com").Return(nil).Times(1)
+	mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+		// both emails go out in the language chosen in the same request
+		want := []string{"अपने नए ईमेल पते की पुष्टि करें", "आपका ईमेल पता बदला जा रहा है"}
+		if len(messages) != len(want) {
+			t.Fatalf("UpdateProfileService() queued %d emails", len(messages))
+		}
+		for i, m := range messages {
+			if m.Summary != want[i] {
+				t.Errorf("UpdateProfileService() queued %q, want %q", m.Summary, want[i])
+			}
+		}
+		return nil
+	}).Times(1)
+
+	cfg := config.Config{}
+	cfg.AuthConfig.EmailVerificationSecret = "secret"
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, cfg)
+	_, err := s.UpdateProfileService(context.Background(), 1, models.UpdateProfileRequest{Locale: &locale, Email: &email, CurrentPassword: "validpassword"})
+	if err != nil {
+		t.Fatalf("UpdateProfileService() error = %v", err)
+	}
+}
+
+func TestService_ExportAccountService(t *testing.T) {
+	user := models.User{Username: "jane", Email: "Jane@Example.com"}
+	user.ID = 1
+	actor, target := uint(1), uint(1)
+	events := []models.AuditEvent{
+		{ID: 1, EventType: models.AuditLoginSucceeded, ActorUserID: &actor, IP: "198.51.100.1"},
+		{ID: 2, EventType: models.AuditRoleGranted, TargetUserID: &target},
+	}
+
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+	mockRepo.EXPECT().FetchUserSessions(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().FetchMembershipsForUser(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().FetchAPIKeysCreatedBy(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().FetchExternalIdentities(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().FetchLockoutEventsForKey(gomock.Any(), "jane@example.com").Return(nil, nil).Times(1)
+	mockRepo.EXPECT().FetchNotifications(gomock.Any(), models.NotificationQuery{UserID: 1}).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().FetchJobPreference(gomock.Any(), uint(1)).Return(models.JobPreference{}, nil).Times(1)
+	// every entry is exported, not just the latest page
+	mockRepo.EXPECT().FetchAuditEvents(gomock.Any(), models.AuditQuery{UserID: 1, Limit: -1}).Return(events, nil).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	got, err := s.ExportAccountService(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExportAccountService() error = %v", err)
+	}
+	if len(got.AuditEvents) != len(events) || got.AuditEvents[0].IP != "198.51.100.1" {
+		t.Errorf("ExportAccountService() audit events = %+v, want %+v", got.AuditEvents, events)
+	}
+	if got.JobPreference != nil {
+		t.Errorf("ExportAccountService() job preference = %+v, want nil", got.JobPreference)
+	}
+}
+
+func Test_emailLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    models.User
+		request string
+		want    string
+	}{
+		{name: "saved locale", user: models.User{Locale: i18n.Hindi}, request: i18n.English, want: i18n.Hindi},
+		{name: "locale of the request", request: i18n.Hindi, want: i18n.Hindi},
+		{name: "unsupported saved locale", user: models.User{Locale: "fr"}, request: i18n.Hindi, want: i18n.Hindi},
+		{name: "default", want: i18n.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.request != "" {
+				ctx = i18n.WithLocale(ctx, tt.request)
+			}
+			if got := emailLocale(ctx, tt.user); got != tt.want {
+				t.Errorf("emailLocale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+)
+
+// GrantRoleService assigns the given role to a user. The user's sessions are revoked with
+// it, so the new role applies from their next login instead of when their access token
+// expires.
+func (s *Service) GrantRoleService(ctx context.Context, uid uint64, role string) (models.User, error) {
+	if !models.ValidRole(role) {
+		return models.User{}, fmt.Errorf("unknown role %q", role)
+	}
+	user, err := s.setRole(ctx, uid, role)
+	if err != nil {
+		return models.User{}, err
+	}
+	log.Info().Uint64("user id", uid).Str("role", role).Msg("role granted")
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditRoleGranted, TargetUserID: uintRef(uint(uid)), Details: map[string]string{"role": role}})
+	return user, nil
+}
+
+// RevokeRoleService drops a user back to the candidate role and revokes their sessions.
+func (s *Service) RevokeRoleService(ctx context.Context, uid uint64) (models.User, error) {
+	user, err := s.setRole(ctx, uid, models.RoleCandidate)
+	if err != nil {
+		return models.User{}, err
+	}
+	log.Info().Uint64("user id", uid).Msg("role revoked")
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditRoleRevoked, TargetUserID: uintRef(uint(uid))})
+	return user, nil
+}
+
+// BootstrapAdminsService makes the registered users with the given emails admins. It is run
+// at startup with ADMIN_EMAILS, since the admin routes cannot be used to create the first
+// admin. Emails nobody registered yet are skipped, and so are unverified ones when email
+// verification is on, so registering a listed email is not enough to become an admin.
+func (s *Service) BootstrapAdminsService(ctx context.Context, emails []string) error {
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		user, err := s.UserRepo.GetUserByEmail(ctx, email)
+		if err != nil {
+			log.Warn().Str("email", email).Msg("admin email is not registered yet")
+			continue
+		}
+		if user.Role == models.RoleAdmin {
+			continue
+		}
+		if s.cfg.AuthConfig.EmailVerification != config.EmailVerificationOff && user.EmailVerifiedAt == nil {
+			log.Warn().Str("email", email).Msg("admin email is not verified yet")
+			continue
+		}
+		_, err = s.setRole(ctx, uint64(user.ID), models.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		log.Info().Uint("user id", user.ID).Msg("admin role granted from ADMIN_EMAILS")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditRoleGranted, TargetUserID: uintRef(user.ID), Details: map[string]string{"role": models.RoleAdmin, "source": "ADMIN_EMAILS"}})
+	}
+	return nil
+}
+
+// setRole changes the role of a user and revokes their sessions, whose tokens carry the old role.
+func (s *Service) setRole(ctx context.Context, uid uint64, role string) (models.User, error) {
+	var user models.User
+	err := s.UserRepo.Transaction(ctx, func(tx repository.UserRepo) error {
+		var err error
+		user, err = tx.UpdateUserRole(ctx, uid, role)
+		if err != nil {
+			return err
+		}
+		return tx.RevokeUserSessions(ctx, uint(uid), "")
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
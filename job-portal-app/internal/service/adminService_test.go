@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestService_GrantRoleService(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		setup   func(mockRepo *repository.MockUserRepo)
+		want    models.User
+		wantErr bool
+	}{
+		{
+			name:    "unknown role",
+			role:    "superuser",
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			want:    models.User{},
+			wantErr: true,
+		},
+		{
+			name: "error from db",
+			role: models.RoleRecruiter,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().UpdateUserRole(gomock.Any(), uint64(2), models.RoleRecruiter).Return(models.User{}, errors.New("user not found")).Times(1)
+			},
+			want:    models.User{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			role: models.RoleRecruiter,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().UpdateUserRole(gomock.Any(), uint64(2), models.RoleRecruiter).Return(models.User{Email: "test@example.com", Role: models.RoleRecruiter}, nil).Times(1)
+				mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(2), "").Return(nil).Times(1)
+			},
+			want: models.User{Email: "test@example.com", Role: models.RoleRecruiter},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+				return fn(mockRepo)
+			}).AnyTimes()
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.GrantRoleService(context.Background(), 2, tt.role)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrantRoleService() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GrantRoleService() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_RevokeRoleService(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+		return fn(mockRepo)
+	}).Times(1)
+	mockRepo.EXPECT().UpdateUserRole(gomock.Any(), uint64(2), models.RoleCandidate).Return(models.User{Role: models.RoleCandidate}, nil).Times(1)
+	mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(2), "").Return(nil).Times(1)
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	got, err := s.RevokeRoleService(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("RevokeRoleService() error = %v", err)
+	}
+	if got.Role != models.RoleCandidate {
+		t.Errorf("RevokeRoleService() role = %v, want %v", got.Role, models.RoleCandidate)
+	}
+}
+
+func TestService_BootstrapAdminsService(t *testing.T) {
+	verified := time.Now()
+	tests := []struct {
+		name    string
+		cfg     config.AuthConfig
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr bool
+	}{
+		{
+			name: "not registered yet",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "root@example.com").Return(models.User{}, errors.New("user not found")).Times(1)
+			},
+		},
+		{
+			name: "already an admin",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "root@example.com").Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleAdmin}, nil).Times(1)
+			},
+		},
+		{
+			name: "unverified while verification is on",
+			cfg:  config.AuthConfig{EmailVerification: config.EmailVerificationLogin, EmailVerificationSecret: "secret"},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "root@example.com").Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleCandidate}, nil).Times(1)
+			},
+		},
+		{
+			name: "verified user is promoted",
+			cfg:  config.AuthConfig{EmailVerification: config.EmailVerificationLogin, EmailVerificationSecret: "secret"},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "root@example.com").Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleCandidate, EmailVerifiedAt: &verified}, nil).Times(1)
+				mockRepo.EXPECT().UpdateUserRole(gomock.Any(), uint64(2), models.RoleAdmin).Return(models.User{Role: models.RoleAdmin}, nil).Times(1)
+				mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(2), "").Return(nil).Times(1)
+			},
+		},
+		{
+			name: "error from db",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "root@example.com").Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleCandidate}, nil).Times(1)
+				mockRepo.EXPECT().UpdateUserRole(gomock.Any(), uint64(2), models.RoleAdmin).Return(models.User{}, errors.New("failed to update user role")).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+				return fn(mockRepo)
+			}).AnyTimes()
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{AuthConfig: tt.cfg})
+			err := s.BootstrapAdminsService(context.Background(), []string{" root@example.com", ""})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BootstrapAdminsService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+)
+
+// apiKeyTouchInterval limits how often last-used tracking writes to the database.
+const apiKeyTouchInterval = time.Minute
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAdminOnly is returned for operations reserved to platform admins.
+	ErrAdminOnly = errors.New("only admins can do this")
+	// ErrServiceKeyScope is returned for service account keys with a scope other than
+	// jobs:read. They reach no company, so reading jobs is all they can do.
+	ErrServiceKeyScope = errors.New("service account keys can only read jobs")
+)
+
+// authorizeAPIKeys checks that the actor may manage the keys of the company. Company keys are
+// managed by the company's owners, service account keys (cid 0) by admins.
+func (s *Service) authorizeAPIKeys(ctx context.Context, actor models.Actor, cid uint64) error {
+	if cid == 0 {
+		if actor.Role != models.RoleAdmin {
+			return ErrAdminOnly
+		}
+		return nil
+	}
+	return s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+}
+
+// CreateAPIKeyService creates a key for the company, or a service account key when cid is 0.
+// The response is the only place the key is ever shown.
+func (s *Service) CreateAPIKeyService(ctx context.Context, actor models.Actor, cid uint64, keyData models.NewAPIKeyRequest) (models.NewAPIKeyResponse, error) {
+	err := s.authorizeAPIKeys(ctx, actor, cid)
+	if err != nil {
+		return models.NewAPIKeyResponse{}, err
+	}
+	for _, scope := range keyData.Scopes {
+		if !models.ValidScope(scope) {
+			return models.NewAPIKeyResponse{}, fmt.Errorf("unknown scope %q", scope)
+		}
+		if cid == 0 && scope != models.ScopeJobsRead {
+			return models.NewAPIKeyResponse{}, ErrServiceKeyScope
+		}
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return models.NewAPIKeyResponse{}, err
+	}
+	key := models.APIKeyPrefix + secret
+
+	record := models.APIKey{
+		Name:      keyData.Name,
+		Prefix:    apiKeyDisplayPrefix(key),
+		KeyHash:   hashToken(key),
+		Scopes:    keyData.Scopes,
+		CreatedBy: actor.UserID,
+	}
+	if cid != 0 {
+		companyID := uint(cid)
+		record.CompanyID = &companyID
+	}
+	if keyData.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, keyData.ExpiresInDays)
+		record.ExpiresAt = &expiresAt
+	}
+
+	record, err = s.UserRepo.InsertAPIKey(ctx, record)
+	if err != nil {
+		return models.NewAPIKeyResponse{}, err
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditAPIKeyCreated, Details: map[string]string{
+		"api_key_id": fmt.Sprint(record.ID),
+		"prefix":     record.Prefix,
+		"scopes":     strings.Join(record.Scopes, " "),
+	}})
+	return models.NewAPIKeyResponse{APIKey: record, Key: key}, nil
+}
+
+// ListAPIKeysService lists the keys of the company, or the service account keys when cid is 0.
+func (s *Service) ListAPIKeysService(ctx context.Context, actor models.Actor, cid uint64) ([]models.APIKey, error) {
+	err := s.authorizeAPIKeys(ctx, actor, cid)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.FetchAPIKeys(ctx, companyIDPtr(cid))
+}
+
+// RevokeAPIKeyService revokes a key. Requests made with it fail from then on.
+func (s *Service) RevokeAPIKeyService(ctx context.Context, actor models.Actor, cid uint64, keyID uint) error {
+	err := s.authorizeAPIKeys(ctx, actor, cid)
+	if err != nil {
+		return err
+	}
+	err = s.UserRepo.RevokeAPIKey(ctx, keyID, companyIDPtr(cid))
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditAPIKeyRevoked, Details: map[string]string{"api_key_id": fmt.Sprint(keyID)}})
+	return nil
+}
+
+// ValidateAPIKey implements auth.APIKeyValidator.
+func (s *Service) ValidateAPIKey(ctx context.Context, key string) (auth.Claims, error) {
+	record, err := s.UserRepo.FetchAPIKeyByHash(ctx, hashToken(key))
+	if err != nil {
+		log.Warn().Str("prefix", apiKeyDisplayPrefix(key)).Msg("unknown api key")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditAPIKeyRejected, Details: map[string]string{"prefix": apiKeyDisplayPrefix(key), "reason": "unknown"}})
+		return auth.Claims{}, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if record.RevokedAt != nil || (record.ExpiresAt != nil && now.After(*record.ExpiresAt)) {
+		reason := "revoked"
+		if record.RevokedAt == nil {
+			reason = "expired"
+		}
+		log.Warn().Uint("api key", record.ID).Msg("revoked or expired api key")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditAPIKeyRejected, ActorAPIKeyID: uintRef(record.ID), Details: map[string]string{"reason": reason}})
+		return auth.Claims{}, ErrInvalidAPIKey
+	}
+
+	// the use of a key is recorded at most once per interval, not on every request
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) > apiKeyTouchInterval {
+		err = s.UserRepo.TouchAPIKey(ctx, record.ID, now)
+		if err != nil {
+			log.Error().Err(err).Uint("api key", record.ID).Msg("failed to record api key use")
+		}
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditAPIKeyUsed, ActorAPIKeyID: uintRef(record.ID)})
+	}
+
+	claims := auth.Claims{
+		Role:     models.RoleService,
+		APIKeyID: record.ID,
+		Scopes:   record.Scopes,
+	}
+	claims.Subject = fmt.Sprintf("apikey:%d", record.ID)
+	if record.CompanyID != nil {
+		claims.CompanyID = *record.CompanyID
+	}
+	return claims, nil
+}
+
+// apiKeyDisplayPrefix is the start of the key that is stored and shown to identify it.
+func apiKeyDisplayPrefix(key string) string {
+	if len(key) > len(models.APIKeyPrefix)+6 {
+		return key[:len(models.APIKeyPrefix)+6]
+	}
+	return key
+}
+
+func companyIDPtr(cid uint64) *uint {
+	if cid == 0 {
+		return nil
+	}
+	companyID := uint(cid)
+	return &companyID
+}
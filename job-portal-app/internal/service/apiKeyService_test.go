@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_CreateAPIKeyService(t *testing.T) {
+	keyData := models.NewAPIKeyRequest{Name: "ats", Scopes: []string{models.ScopeApplicationsProcess}}
+	tests := []struct {
+		name    string
+		actor   models.Actor
+		cid     uint64
+		scopes  []string
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name:    "service account key by a recruiter",
+			actor:   models.Actor{UserID: 2, Role: models.RoleRecruiter},
+			cid:     0,
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrAdminOnly,
+		},
+		{
+			name:    "service account key that processes applications",
+			actor:   models.Actor{UserID: 1, Role: models.RoleAdmin},
+			cid:     0,
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrServiceKeyScope,
+		},
+		{
+			name:   "service account key that reads jobs",
+			actor:  models.Actor{UserID: 1, Role: models.RoleAdmin},
+			cid:    0,
+			scopes: []string{models.ScopeJobsRead},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().InsertAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+					if key.CompanyID != nil {
+						t.Errorf("InsertAPIKey() got key %+v", key)
+					}
+					return key, nil
+				}).Times(1)
+			},
+		},
+		{
+			name:  "company key by a recruiter who is not an owner",
+			actor: models.Actor{UserID: 2, Role: models.RoleRecruiter},
+			cid:   1,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(models.CompanyMember{Model: gorm.Model{ID: 5}, Role: models.MemberRoleRecruiter}, nil).Times(1)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{
+			name:  "company key by an owner",
+			actor: models.Actor{UserID: 2, Role: models.RoleRecruiter},
+			cid:   1,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(models.CompanyMember{Model: gorm.Model{ID: 5}, Role: models.MemberRoleOwner}, nil).Times(1)
+				mockRepo.EXPECT().InsertAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+					if key.CompanyID == nil || *key.CompanyID != 1 || key.CreatedBy != 2 {
+						t.Errorf("InsertAPIKey() got key %+v", key)
+					}
+					return key, nil
+				}).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			keyData := keyData
+			if tt.scopes != nil {
+				keyData.Scopes = tt.scopes
+			}
+			got, err := s.CreateAPIKeyService(context.Background(), tt.actor, tt.cid, keyData)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateAPIKeyService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !strings.HasPrefix(got.Key, models.APIKeyPrefix) || !strings.HasPrefix(got.Key, got.Prefix) {
+				t.Errorf("CreateAPIKeyService() key = %q, prefix = %q", got.Key, got.Prefix)
+			}
+			if got.KeyHash != hashToken(got.Key) {
+				t.Errorf("CreateAPIKeyService() stored hash does not match the key")
+			}
+		})
+	}
+}
+
+func TestService_ValidateAPIKey(t *testing.T) {
+	companyID := uint(1)
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now()
+	tests := []struct {
+		name     string
+		key      models.APIKey
+		fetchErr error
+		touch    bool
+		// wantEvent is the audited event type, if any
+		wantEvent  string
+		wantReason string
+		wantErr    error
+	}{
+		{
+			name:       "unknown key",
+			fetchErr:   errors.New("record not found"),
+			wantEvent:  models.AuditAPIKeyRejected,
+			wantReason: "unknown",
+			wantErr:    ErrInvalidAPIKey,
+		},
+		{
+			name:       "revoked key",
+			key:        models.APIKey{ID: 1, RevokedAt: &past},
+			wantEvent:  models.AuditAPIKeyRejected,
+			wantReason: "revoked",
+			wantErr:    ErrInvalidAPIKey,
+		},
+		{
+			name:       "expired key",
+			key:        models.APIKey{ID: 1, ExpiresAt: &past},
+			wantEvent:  models.AuditAPIKeyRejected,
+			wantReason: "expired",
+			wantErr:    ErrInvalidAPIKey,
+		},
+		{
+			name:  "recently used key is not touched or audited again",
+			key:   models.APIKey{ID: 1, CompanyID: &companyID, Scopes: []string{models.ScopeJobsRead}, LastUsedAt: &recent},
+			touch: false,
+		},
+		{
+			name:      "success",
+			key:       models.APIKey{ID: 1, CompanyID: &companyID, Scopes: []string{models.ScopeJobsRead}, LastUsedAt: &past},
+			touch:     true,
+			wantEvent: models.AuditAPIKeyUsed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			var events []models.AuditEvent
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, event models.AuditEvent) error {
+				events = append(events, event)
+				return nil
+			}).AnyTimes()
+			mockRepo.EXPECT().FetchAPIKeyByHash(gomock.Any(), hashToken("jpk_key")).Return(tt.key, tt.fetchErr).Times(1)
+			if tt.touch {
+				mockRepo.EXPECT().TouchAPIKey(gomock.Any(), uint(1), gomock.Any()).Return(nil).Times(1)
+			}
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.ValidateAPIKey(context.Background(), "jpk_key")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantEvent == "" {
+				if len(events) != 0 {
+					t.Errorf("audited %+v, want nothing", events)
+				}
+			} else {
+				if len(events) != 1 || events[0].EventType != tt.wantEvent || events[0].Details["reason"] != tt.wantReason {
+					t.Fatalf("audited %+v, want one %s event", events, tt.wantEvent)
+				}
+				if tt.fetchErr == nil && (events[0].ActorAPIKeyID == nil || *events[0].ActorAPIKeyID != 1) {
+					t.Errorf("audited actor api key = %v, want 1", events[0].ActorAPIKeyID)
+				}
+			}
+			if err != nil {
+				return
+			}
+			if got.APIKeyID != 1 || got.CompanyID != 1 || !got.HasScope(models.ScopeJobsRead) || got.Role != models.RoleService {
+				t.Errorf("ValidateAPIKey() got = %+v", got)
+			}
+		})
+	}
+}
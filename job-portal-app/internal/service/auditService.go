@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+)
+
+const maxAuditEvents = 1000
+
+// audit appends an event to the audit log. The actor is taken from the request's token
+// unless the event names one, and the client and trace id from the request. A failure to
+// write is logged but does not fail the operation being audited.
+func (s *Service) audit(ctx context.Context, event models.AuditEvent) {
+	if event.ActorUserID == nil && event.ActorAPIKeyID == nil {
+		if claims, ok := ctx.Value(auth.Key).(auth.Claims); ok {
+			if claims.APIKeyID != 0 {
+				event.ActorAPIKeyID = uintRef(claims.APIKeyID)
+			} else if uid, err := strconv.ParseUint(claims.Subject, 10, 64); err == nil {
+				event.ActorUserID = uintRef(uint(uid))
+			}
+		}
+	}
+	if client, ok := models.ClientFromContext(ctx); ok {
+		event.IP = client.IP
+		event.UserAgent = client.UserAgent
+		event.TraceID = client.TraceID
+	}
+
+	err := s.UserRepo.InsertAuditEvent(ctx, event)
+	if err != nil {
+		log.Error().Err(err).Str("event", event.EventType).Str("Trace Id", event.TraceID).Msg("failed to write the audit log")
+	}
+}
+
+// actorEvent sets the actor of event to the caller of a service method, the key when one
+// was used.
+func actorEvent(actor models.Actor, event models.AuditEvent) models.AuditEvent {
+	if actor.APIKeyID != 0 {
+		event.ActorAPIKeyID = uintRef(actor.APIKeyID)
+	} else if actor.UserID != 0 {
+		event.ActorUserID = uintRef(actor.UserID)
+	}
+	return event
+}
+
+// ListAuditEventsService returns the matching audit events, most recent first.
+func (s *Service) ListAuditEventsService(ctx context.Context, query models.AuditQuery) ([]models.AuditEvent, error) {
+	if query.Limit <= 0 || query.Limit > maxAuditEvents {
+		query.Limit = maxAuditEvents
+	}
+	return s.UserRepo.FetchAuditEvents(ctx, query)
+}
+
+func uintRef(v uint) *uint {
+	return &v
+}
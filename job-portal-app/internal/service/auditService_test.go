@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+)
+
+func TestService_audit(t *testing.T) {
+	client := models.Client{IP: "10.0.0.1", UserAgent: "curl/8.0", TraceID: "trace-1"}
+	tests := []struct {
+		name  string
+		ctx   func() context.Context
+		event models.AuditEvent
+		want  models.AuditEvent
+	}{
+		{
+			name: "actor, client and trace id from the request",
+			ctx: func() context.Context {
+				ctx := models.WithClient(context.Background(), client)
+				return context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+			},
+			event: models.AuditEvent{EventType: models.AuditRoleGranted, TargetUserID: uintRef(2)},
+			want: models.AuditEvent{
+				EventType:    models.AuditRoleGranted,
+				ActorUserID:  uintRef(1),
+				TargetUserID: uintRef(2),
+				IP:           "10.0.0.1",
+				UserAgent:    "curl/8.0",
+				TraceID:      "trace-1",
+			},
+		},
+		{
+			name: "api key as actor",
+			ctx: func() context.Context {
+				return context.WithValue(context.Background(), auth.Key, auth.Claims{APIKeyID: 9})
+			},
+			event: models.AuditEvent{EventType: models.AuditAPIKeyRevoked},
+			want:  models.AuditEvent{EventType: models.AuditAPIKeyRevoked, ActorAPIKeyID: uintRef(9)},
+		},
+		{
+			name: "actor set by the caller is kept",
+			ctx: func() context.Context {
+				return context.WithValue(context.Background(), auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+			},
+			event: models.AuditEvent{EventType: models.AuditAPIKeyCreated, ActorAPIKeyID: uintRef(3)},
+			want:  models.AuditEvent{EventType: models.AuditAPIKeyCreated, ActorAPIKeyID: uintRef(3)},
+		},
+		{
+			name:  "anonymous request",
+			ctx:   context.Background,
+			event: models.AuditEvent{EventType: models.AuditLoginFailed, Details: map[string]string{"email": "test@example.com"}},
+			want:  models.AuditEvent{EventType: models.AuditLoginFailed, Details: map[string]string{"email": "test@example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			var got models.AuditEvent
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, event models.AuditEvent) error {
+				got = event
+				return nil
+			}).Times(1)
+			s := &Service{UserRepo: mockRepo}
+			s.audit(tt.ctx(), tt.event)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("audit() wrote %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_audit_writeFailure(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(errors.New("connection refused")).Times(1)
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+		return fn(mockRepo)
+	}).Times(1)
+	mockRepo.EXPECT().UpdateUserRole(gomock.Any(), uint64(2), models.RoleRecruiter).Return(models.User{Role: models.RoleRecruiter}, nil).Times(1)
+	mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(2), "").Return(nil).Times(1)
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	_, err := s.GrantRoleService(context.Background(), 2, models.RoleRecruiter)
+	if err != nil {
+		t.Errorf("GrantRoleService() error = %v, a failed audit write must not fail the change", err)
+	}
+}
+
+func TestService_ListAuditEventsService(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "default limit", limit: 0, wantLimit: maxAuditEvents},
+		{name: "limit kept", limit: 50, wantLimit: 50},
+		{name: "limit capped", limit: 5000, wantLimit: maxAuditEvents},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			query := models.AuditQuery{UserID: 2, EventType: models.AuditLoginFailed, Limit: tt.limit}
+			want := query
+			want.Limit = tt.wantLimit
+			mockRepo.EXPECT().FetchAuditEvents(gomock.Any(), want).Return([]models.AuditEvent{{ID: 1}}, nil).Times(1)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.ListAuditEventsService(context.Background(), query)
+			if err != nil || len(got) != 1 {
+				t.Errorf("ListAuditEventsService() = %v, %v", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+	"job-portal-api/internal/repository"
+)
+
+const (
+	// invalidationSlack widens the catch up after a reconnect, to cover transactions that
+	// committed a little after they recorded their change, and clocks that differ between
+	// instances. Evicting a few entries twice costs nothing.
+	invalidationSlack = time.Minute
+	// invalidationRetention is how long invalidations are kept for instances to catch up.
+	// It only has to outlast the cache TTLs: an entry cached before an older change has
+	// expired by the time that change is pruned.
+	invalidationRetention = 24 * time.Hour
+	invalidationPrune     = time.Hour
+)
+
+// NotificationListener delivers the notifications sent on a Postgres channel. It is
+// implemented by database.Listener.
+type NotificationListener interface {
+	// Listen blocks until ctx is done. connected is called every time the connection is
+	// up, with the time from which notifications may have been missed, zero the first time.
+	Listen(ctx context.Context, channel string, connected func(ctx context.Context, missedSince time.Time), notify func(ctx context.Context, payload string)) error
+}
+
+// CacheInvalidator evicts the cached copies of the jobs and companies changed on any
+// instance, from this instance's memory tier and from the shared cache.
+type CacheInvalidator struct {
+	repo     repository.UserRepo
+	cache    redis.Redis
+	listener NotificationListener
+}
+
+func NewCacheInvalidator(repo repository.UserRepo, cache redis.Redis, listener NotificationListener) (*CacheInvalidator, error) {
+	if repo == nil || cache == nil || listener == nil {
+		return nil, errors.New("the cache invalidator needs a repository, a cache and a listener")
+	}
+	return &CacheInvalidator{repo: repo, cache: cache, listener: listener}, nil
+}
+
+// Run listens for changes and prunes old invalidations until ctx is cancelled.
+func (i *CacheInvalidator) Run(ctx context.Context) {
+	go i.prune(ctx)
+	err := i.listener.Listen(ctx, models.CacheInvalidationChannel, i.catchUp, i.handle)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Error().Err(err).Msg("cache invalidation listener stopped")
+	}
+}
+
+// handle evicts the job or company of one notification.
+func (i *CacheInvalidator) handle(ctx context.Context, payload string) {
+	var invalidation models.CacheInvalidation
+	err := json.Unmarshal([]byte(payload), &invalidation)
+	if err != nil {
+		log.Error().Err(err).Str("payload", payload).Msg("invalid cache invalidation")
+		return
+	}
+	i.evict(ctx, invalidation)
+}
+
+// catchUp evicts what changed while the listener was disconnected.
+func (i *CacheInvalidator) catchUp(ctx context.Context, missedSince time.Time) {
+	if missedSince.IsZero() {
+		return
+	}
+	invalidations, err := i.repo.FetchCacheInvalidations(ctx, missedSince.Add(-invalidationSlack))
+	if err != nil {
+		// the entries expire on their own, it is not worth failing the listener for
+		log.Error().Err(err).Msg("failed to catch up on cache invalidations")
+		return
+	}
+	for _, invalidation := range invalidations {
+		i.evict(ctx, invalidation)
+	}
+	log.Info().Int("evicted", len(invalidations)).Time("missed since", missedSince).Msg("caught up on cache invalidations")
+}
+
+func (i *CacheInvalidator) evict(ctx context.Context, invalidation models.CacheInvalidation) {
+	var err error
+	switch invalidation.Kind {
+	case models.CacheKindJob:
+		err = i.cache.DeleteJob(ctx, uint64(invalidation.EntityID))
+	case models.CacheKindCompany:
+		err = i.cache.DeleteCompany(ctx, uint64(invalidation.EntityID))
+	default:
+		log.Error().Str("kind", invalidation.Kind).Msg("unknown kind of cache invalidation")
+		return
+	}
+	// the breaker deletes it once the cache is back
+	if err != nil && !errors.Is(err, redis.ErrUnavailable) {
+		log.Error().Err(err).Str("kind", invalidation.Kind).Uint("id", invalidation.EntityID).Msg("failed to evict cache entry")
+	}
+}
+
+// prune drops the invalidations no instance needs any more.
+func (i *CacheInvalidator) prune(ctx context.Context) {
+	ticker := time.NewTicker(invalidationPrune)
+	defer ticker.Stop()
+	for {
+		n, err := i.repo.DeleteCacheInvalidations(ctx, time.Now().Add(-invalidationRetention))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to prune cache invalidations")
+		} else if n > 0 {
+			log.Info().Int64("deleted", n).Msg("pruned cache invalidations")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
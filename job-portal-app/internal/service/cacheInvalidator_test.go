@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+)
+
+// scriptedListener replays connections and notifications as a database.Listener would.
+type scriptedListener struct {
+	// events are run in order: a connection when connectedAfter is set, a notification otherwise
+	events []listenerEvent
+}
+
+type listenerEvent struct {
+	connectedAfter *time.Time
+	payload        string
+}
+
+func (l scriptedListener) Listen(ctx context.Context, channel string, connected func(ctx context.Context, missedSince time.Time), notify func(ctx context.Context, payload string)) error {
+	if channel != models.CacheInvalidationChannel {
+		return errors.New("unexpected channel " + channel)
+	}
+	for _, e := range l.events {
+		if e.connectedAfter != nil {
+			connected(ctx, *e.connectedAfter)
+			continue
+		}
+		notify(ctx, e.payload)
+	}
+	return context.Canceled
+}
+
+func TestCacheInvalidator_Run(t *testing.T) {
+	missedSince := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	firstConnect := time.Time{}
+	tests := []struct {
+		name        string
+		events      []listenerEvent
+		setup       func(mockRepo *repository.MockUserRepo)
+		wantEvicted []uint64
+	}{
+		{
+			name: "job changed on another instance",
+			events: []listenerEvent{
+				{connectedAfter: &firstConnect},
+				{payload: `{"id":10,"kind":"job","entityId":1}`},
+			},
+			wantEvicted: []uint64{1},
+		},
+		{
+			name: "invalid notifications are skipped",
+			events: []listenerEvent{
+				{connectedAfter: &firstConnect},
+				{payload: `not json`},
+				{payload: `{"id":11,"kind":"user","entityId":1}`},
+				{payload: `{"id":12,"kind":"job","entityId":2}`},
+			},
+			wantEvicted: []uint64{2},
+		},
+		{
+			name: "catch up after a reconnect",
+			events: []listenerEvent{
+				{connectedAfter: &firstConnect},
+				{connectedAfter: &missedSince},
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCacheInvalidations(gomock.Any(), missedSince.Add(-invalidationSlack)).Return([]models.CacheInvalidation{
+					{Kind: models.CacheKindJob, EntityID: 1},
+					{Kind: models.CacheKindCompany, EntityID: 3},
+				}, nil).Times(1)
+			},
+			wantEvicted: []uint64{1},
+		},
+		{
+			name: "catch up fails",
+			events: []listenerEvent{
+				{connectedAfter: &missedSince},
+				{payload: `{"id":13,"kind":"job","entityId":2}`},
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCacheInvalidations(gomock.Any(), gomock.Any()).Return(nil, errors.New("test error")).Times(1)
+			},
+			wantEvicted: []uint64{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().DeleteCacheInvalidations(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(mockRepo)
+			}
+			shared := redis.NewMemory()
+			cache := redis.NewTiered(redis.NewBreaker(shared, config.RedisConfig{BreakerThreshold: 1}), time.Minute)
+			for _, jid := range []uint{1, 2} {
+				_ = cache.SetJob(ctx, models.Jobs{Model: gorm.Model{ID: jid}}, time.Minute)
+			}
+			_ = cache.SetCompany(ctx, models.Company{Model: gorm.Model{ID: 3}}, time.Minute)
+
+			invalidator, err := NewCacheInvalidator(mockRepo, cache, scriptedListener{events: tt.events})
+			if err != nil {
+				t.Fatalf("NewCacheInvalidator() error = %v", err)
+			}
+			runCtx, cancel := context.WithCancel(ctx)
+			invalidator.Run(runCtx)
+			cancel()
+
+			evicted := map[uint64]bool{}
+			for _, jid := range tt.wantEvicted {
+				evicted[jid] = true
+			}
+			for _, jid := range []uint64{1, 2} {
+				// both the memory tier and the shared cache are evicted
+				_, local, _ := cache.GetJob(ctx, jid)
+				_, remote, _ := shared.GetJob(ctx, jid)
+				if local == evicted[jid] || remote == evicted[jid] {
+					t.Errorf("job %d cached = %v in memory and %v in the shared cache, want evicted %v", jid, local, remote, evicted[jid])
+				}
+			}
+		})
+	}
+}
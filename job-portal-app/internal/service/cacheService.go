@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+)
+
+// fetchJob returns the job from the cache, reading it from the database and caching it on
+// a miss. The cache is only an optimisation: when it fails the database is used instead,
+// and while its circuit breaker is open it is not even tried. A job that does not exist is
+// returned with ID 0 and is not cached.
+func (s *Service) fetchJob(ctx context.Context, jid uint64) (models.Jobs, error) {
+	ttl := s.cfg.RedisConfig.JobTTL
+	if s.rdb == nil || ttl <= 0 {
+		return s.UserRepo.FetchJobPostingByID(ctx, jid)
+	}
+	job, cached, err := s.rdb.GetJob(ctx, jid)
+	if err != nil {
+		cacheWarning(err).Uint64("job id", jid).Msg("failed to read the job cache")
+	}
+	if cached {
+		return job, nil
+	}
+	job, err = s.UserRepo.FetchJobPostingByID(ctx, jid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	if job.ID != 0 {
+		err = s.rdb.SetJob(ctx, job, ttl)
+		if err != nil {
+			cacheWarning(err).Uint64("job id", jid).Msg("failed to cache the job")
+		}
+	}
+	return job, nil
+}
+
+// invalidateJob drops the cached copy of a job after it changed, so the next read gets it
+// from the database. It must be called once the change is committed, otherwise a read in
+// between could cache the old job again.
+func (s *Service) invalidateJob(ctx context.Context, jid uint64) {
+	if s.rdb == nil {
+		return
+	}
+	err := s.rdb.DeleteJob(ctx, jid)
+	// the breaker deletes it once the cache is back
+	if err != nil && !errors.Is(err, redis.ErrUnavailable) {
+		log.Error().Err(err).Uint64("job id", jid).Msg("failed to invalidate the cached job")
+	}
+}
+
+// fetchCompany is fetchJob for companies.
+func (s *Service) fetchCompany(ctx context.Context, cid uint64) (models.Company, error) {
+	ttl := s.cfg.RedisConfig.CompanyTTL
+	if s.rdb == nil || ttl <= 0 {
+		return s.UserRepo.FetchCompanyByID(ctx, cid)
+	}
+	company, cached, err := s.rdb.GetCompany(ctx, cid)
+	if err != nil {
+		cacheWarning(err).Uint64("company id", cid).Msg("failed to read the company cache")
+	}
+	if cached {
+		return company, nil
+	}
+	company, err = s.UserRepo.FetchCompanyByID(ctx, cid)
+	if err != nil {
+		return models.Company{}, err
+	}
+	if company.ID != 0 {
+		err = s.rdb.SetCompany(ctx, company, ttl)
+		if err != nil {
+			cacheWarning(err).Uint64("company id", cid).Msg("failed to cache the company")
+		}
+	}
+	return company, nil
+}
+
+// cacheWarning logs a failed cache call. Calls skipped by the open circuit breaker are
+// expected during an outage, and only logged at debug level.
+func cacheWarning(err error) *zerolog.Event {
+	if errors.Is(err, redis.ErrUnavailable) {
+		return log.Debug().Err(err)
+	}
+	return log.Warn().Err(err)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+	"job-portal-api/internal/repository"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// downStore is a cache that cannot be reached. It counts the calls that got to it.
+type downStore struct {
+	redis.Store
+	calls atomic.Int64
+}
+
+var errConnRefused = errors.New("dial tcp: connection refused")
+
+func (d *downStore) fail() error {
+	d.calls.Add(1)
+	return errConnRefused
+}
+
+func (d *downStore) Ping(ctx context.Context) error { return d.fail() }
+func (d *downStore) SetJob(ctx context.Context, job models.Jobs, ttl time.Duration) error {
+	return d.fail()
+}
+func (d *downStore) GetJob(ctx context.Context, jid uint64) (models.Jobs, bool, error) {
+	return models.Jobs{}, false, d.fail()
+}
+func (d *downStore) SaveOTP(ctx context.Context, email, otpHash string, ttl time.Duration) error {
+	return d.fail()
+}
+func (d *downStore) GetOTP(ctx context.Context, email string) (string, error) { return "", d.fail() }
+func (d *downStore) DeleteOTP(ctx context.Context, email string) error        { return d.fail() }
+func (d *downStore) StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	return false, d.fail()
+}
+func (d *downStore) GetOTPAttempts(ctx context.Context, email string) (int64, error) {
+	return 0, d.fail()
+}
+func (d *downStore) IncrOTPAttempts(ctx context.Context, email string, window time.Duration) (int64, error) {
+	return 0, d.fail()
+}
+func (d *downStore) ResetOTPAttempts(ctx context.Context, email string) error { return d.fail() }
+func (d *downStore) SaveResetToken(ctx context.Context, tokenHash, email string, ttl time.Duration) error {
+	return d.fail()
+}
+func (d *downStore) GetResetToken(ctx context.Context, tokenHash string) (string, error) {
+	return "", d.fail()
+}
+func (d *downStore) ConsumeResetToken(ctx context.Context, tokenHash string) (string, error) {
+	return "", d.fail()
+}
+
+var outageConfig = config.RedisConfig{JobTTL: time.Minute, BreakerThreshold: 2, BreakerCooldown: time.Hour}
+
+func TestService_ApplicationProcessor_cacheDown(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	var applications []models.RequestJob
+	for jid := uint64(1); jid <= 10; jid++ {
+		applications = append(applications, models.RequestJob{Name: "A", Jid: jid, Budget: 100})
+		mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), jid).Return(models.Jobs{Model: gorm.Model{ID: uint(jid)}, Cid: 1, Budget: 100}, nil).Times(1)
+	}
+	store := &downStore{}
+	breaker := redis.NewBreaker(store, outageConfig)
+	s := &Service{
+		UserRepo: mockRepo,
+		rdb:      breaker,
+		cfg:      config.Config{RedisConfig: outageConfig},
+	}
+
+	// every job is read from the database instead
+	_, err := s.ApplicationProcessor(context.Background(), models.Actor{UserID: 1, Role: models.RoleAdmin}, applications)
+	if err != nil {
+		t.Fatalf("ApplicationProcessor() error = %v", err)
+	}
+	// the applications are screened concurrently, so a few calls can be in flight when the
+	// breaker opens, but not one per application
+	if calls := store.calls.Load(); calls >= int64(2*len(applications)) {
+		t.Errorf("the cache was called %d times, want the breaker to stop calling it", calls)
+	}
+	if state := breaker.Stats().State; state != redis.BreakerOpen {
+		t.Errorf("breaker state = %q, want %q", state, redis.BreakerOpen)
+	}
+}
+
+func TestService_passwordResetFlow_cacheDown(t *testing.T) {
+	ctx := context.Background()
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(models.User{Model: gorm.Model{ID: 1}, Email: "jane@example.com"}, nil).Times(1)
+	var otp string
+	mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+		var msg mail.Message
+		_ = json.Unmarshal(messages[0].Payload, &msg)
+		otp = regexp.MustCompile(`[0-9]{6}`).FindString(msg.Text)
+		return nil
+	}).Times(1)
+	store := &downStore{}
+	s, _ := NewService(mockRepo, &auth.Auth{}, redis.NewBreaker(store, outageConfig), config.Config{RedisConfig: outageConfig})
+
+	_, err := s.ForgetPasswordService(ctx, models.ForgetPasswordRequest{Email: "jane@example.com"})
+	if err != nil || otp == "" {
+		t.Fatalf("ForgetPasswordService() error = %v, otp %q", err, otp)
+	}
+	// the cooldown is kept in memory as well
+	_, err = s.ForgetPasswordService(ctx, models.ForgetPasswordRequest{Email: "jane@example.com"})
+	if !errors.Is(err, ErrOTPCooldown) {
+		t.Fatalf("ForgetPasswordService() during the cooldown error = %v, want %v", err, ErrOTPCooldown)
+	}
+	verified, err := s.VerifyOTPService(ctx, models.VerifyOTPRequest{Email: "jane@example.com", OTP: otp})
+	if err != nil || verified.ResetToken == "" {
+		t.Fatalf("VerifyOTPService() error = %v", err)
+	}
+	_, err = s.VerifyOTPService(ctx, models.VerifyOTPRequest{Email: "jane@example.com", OTP: otp})
+	if !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("VerifyOTPService() with a used OTP error = %v, want %v", err, ErrInvalidOTP)
+	}
+	err = s.ResetPasswordService(ctx, models.ResetPasswordRequest{ResetToken: verified.ResetToken, NewPassword: "a", ConfirmPassword: "b"})
+	if !errors.Is(err, ErrPasswordMismatch) {
+		t.Fatalf("ResetPasswordService() error = %v, want %v", err, ErrPasswordMismatch)
+	}
+	if calls := store.calls.Load(); calls != int64(outageConfig.BreakerThreshold) {
+		t.Errorf("the cache was called %d times, want %d", calls, outageConfig.BreakerThreshold)
+	}
+}
+
+func TestService_invalidateJob_cacheDown(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	memory := redis.NewMemory()
+	flaky := &flakyStore{Store: memory}
+	breaker := redis.NewBreaker(flaky, config.RedisConfig{BreakerThreshold: 1})
+	s := &Service{
+		UserRepo: mockRepo,
+		rdb:      breaker,
+		cfg:      config.Config{RedisConfig: config.RedisConfig{JobTTL: time.Minute}},
+	}
+	_ = memory.SetJob(context.Background(), models.Jobs{Model: gorm.Model{ID: 4}, Description: "old"}, time.Minute)
+
+	// the job changes while the cache is down, so its cached copy cannot be deleted
+	flaky.down.Store(true)
+	s.invalidateJob(context.Background(), 4)
+	flaky.down.Store(false)
+
+	// the cache is back, the stale copy must not be served
+	mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(4)).Return(models.Jobs{Model: gorm.Model{ID: 4}, Description: "new"}, nil).Times(1)
+	got, err := s.GetJobPostingByIDService(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("GetJobPostingByIDService() error = %v", err)
+	}
+	if got.Description != "new" {
+		t.Errorf("GetJobPostingByIDService() description = %q, want %q", got.Description, "new")
+	}
+}
+
+// flakyStore is a cache whose job deletes fail while down is set.
+type flakyStore struct {
+	redis.Store
+	down atomic.Bool
+}
+
+func (f *flakyStore) DeleteJob(ctx context.Context, jid uint64) error {
+	if f.down.Load() {
+		return errConnRefused
+	}
+	return f.Store.DeleteJob(ctx, jid)
+}
@@ -6,9 +6,9 @@ import (
 	"job-portal-api/internal/models"
 )
 
-func (s *Service) CreateCompanyService(ctx context.Context, companyData models.Company) (models.Company, error) {
-
-	companyData, err := s.UserRepo.InsertCompany(ctx, companyData)
+// CreateCompanyService creates the company with the actor as its first owner.
+func (s *Service) CreateCompanyService(ctx context.Context, actor models.Actor, companyData models.Company) (models.Company, error) {
+	companyData, err := s.UserRepo.InsertCompany(ctx, companyData, actor.UserID)
 	if err != nil {
 		return models.Company{}, err
 	}
@@ -24,7 +24,7 @@ func (s *Service) ListCompaniesService(ctx context.Context) ([]models.Company, e
 }
 
 func (s *Service) GetCompanyService(ctx context.Context, cid uint64) (models.Company, error) {
-	companyData, err := s.UserRepo.FetchCompanyByID(ctx, cid)
+	companyData, err := s.fetchCompany(ctx, cid)
 	if err != nil {
 		return models.Company{}, err
 	}
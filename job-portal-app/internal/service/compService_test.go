@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
 	"job-portal-api/internal/repository"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestService_ListCompaniesService(t *testing.T) {
@@ -74,7 +78,7 @@ func TestService_ListCompaniesService(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().FetchAllCompanies(tt.args.ctx).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
 			got, err := s.ListCompaniesService(tt.args.ctx)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListCompaniesService() error = %v, wantErr %v", err, tt.wantErr)
@@ -135,7 +139,7 @@ func TestService_GetCompanyService(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().FetchCompanyByID(tt.args.ctx, tt.args.cid).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
 			got, err := s.GetCompanyService(tt.args.ctx, tt.args.cid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetCompanyService() error = %v, wantErr %v", err, tt.wantErr)
@@ -194,10 +198,10 @@ func TestService_CreateCompanyService(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
 			if tt.mockRepoResponse != nil {
-				mockRepo.EXPECT().InsertCompany(tt.args.ctx, tt.args.companyData).Return(tt.mockRepoResponse()).AnyTimes()
+				mockRepo.EXPECT().InsertCompany(tt.args.ctx, tt.args.companyData, uint(7)).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
-			got, err := s.CreateCompanyService(tt.args.ctx, tt.args.companyData)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.CreateCompanyService(tt.args.ctx, models.Actor{UserID: 7, Role: models.RoleRecruiter}, tt.args.companyData)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateCompanyService() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -208,3 +212,50 @@ func TestService_CreateCompanyService(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetCompanyService_cache(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		company   models.Company
+		wantReads int
+	}{
+		{
+			name:      "cached after the first read",
+			ttl:       time.Minute,
+			company:   models.Company{Model: gorm.Model{ID: 3}, Name: "Acme"},
+			wantReads: 1,
+		},
+		{
+			name:      "caching turned off",
+			company:   models.Company{Model: gorm.Model{ID: 3}, Name: "Acme"},
+			wantReads: 3,
+		},
+		{
+			name:      "unknown companies are not cached",
+			ttl:       time.Minute,
+			wantReads: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().FetchCompanyByID(gomock.Any(), uint64(3)).Return(tt.company, nil).Times(tt.wantReads)
+			s := &Service{
+				UserRepo: mockRepo,
+				rdb:      redis.NewMemory(),
+				cfg:      config.Config{RedisConfig: config.RedisConfig{CompanyTTL: tt.ttl}},
+			}
+			for i := 0; i < 3; i++ {
+				got, err := s.GetCompanyService(context.Background(), 3)
+				if err != nil {
+					t.Fatalf("GetCompanyService() error = %v", err)
+				}
+				if got.Name != tt.company.Name {
+					t.Errorf("GetCompanyService() got = %v, want %v", got, tt.company)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+)
+
+const emailVerificationTTL = 24 * time.Hour
+
+var (
+	ErrEmailNotVerified         = errors.New("please verify your email address before logging in")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+)
+
+// queueVerificationEmail queues an email with a link that verifies the user's address.
+// Nothing is sent when no signing secret is configured.
+func (s *Service) queueVerificationEmail(ctx context.Context, repo repository.UserRepo, user models.User) error {
+	if s.cfg.AuthConfig.EmailVerificationSecret == "" {
+		return nil
+	}
+	token := s.signVerificationToken(user.ID, user.Email, time.Now().Add(emailVerificationTTL))
+	link := fmt.Sprintf("%s/api/verify-email?token=%s", strings.TrimSuffix(s.cfg.AppConfig.BaseURL, "/"), url.QueryEscape(token))
+	return queueEmail(ctx, repo, emailLocale(ctx, user), user.Email, mail.TemplateVerifyEmail, mail.LinkData{Link: link, Hours: int(emailVerificationTTL.Hours())})
+}
+
+// VerifyEmailService marks the email in the token as verified. Verifying twice is not an error.
+func (s *Service) VerifyEmailService(ctx context.Context, token string) error {
+	uid, email, err := s.parseVerificationToken(token)
+	if err != nil {
+		log.Info().Err(err).Msg("invalid verification token")
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := s.UserRepo.GetUserByID(ctx, uid)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	// a link sent to the new address of an email change swaps it in
+	if user.PendingEmail != nil && strings.EqualFold(*user.PendingEmail, email) {
+		ok, err := s.UserRepo.ConfirmEmailChange(ctx, user.ID, *user.PendingEmail)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidVerificationToken
+		}
+		return nil
+	}
+	// a link sent to an older address must not verify the current one
+	if !strings.EqualFold(user.Email, email) {
+		return ErrInvalidVerificationToken
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return s.UserRepo.MarkEmailVerified(ctx, user.ID)
+}
+
+// ResendVerificationService sends a new verification link. Like ForgetPasswordService it
+// does not tell the caller whether the account exists.
+func (s *Service) ResendVerificationService(ctx context.Context, email string) error {
+	user, err := s.UserRepo.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		log.Info().Err(err).Msg("verification requested for an unknown email")
+		return nil
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	err = s.queueVerificationEmail(ctx, s.UserRepo, user)
+	if err != nil {
+		log.Error().Err(err).Uint("user id", user.ID).Msg("failed to send verification email")
+		return errors.New("failed to send verification email")
+	}
+	return nil
+}
+
+// signVerificationToken returns "<payload>.<signature>", both base64url encoded. The payload
+// is "<user id>|<expiry unix time>|<email>" and the signature its HMAC-SHA256.
+func (s *Service) signVerificationToken(uid uint, email string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d|%d|%s", uid, expiresAt.Unix(), email)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.verificationMAC(encoded))
+}
+
+// parseVerificationToken returns the user id and email of a valid token. Without a secret
+// no link was ever sent, and anyone could sign one with the empty key, so every token is
+// rejected.
+func (s *Service) parseVerificationToken(token string) (uint64, string, error) {
+	if s.cfg.AuthConfig.EmailVerificationSecret == "" {
+		return 0, "", errors.New("email verification is not configured")
+	}
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, "", errors.New("malformed token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.verificationMAC(encoded)) {
+		return 0, "", errors.New("bad signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, "", err
+	}
+
+	// the email goes last, so a "|" in it does not break the split
+	parts := strings.SplitN(string(payload), "|", 3)
+	if len(parts) != 3 {
+		return 0, "", errors.New("malformed payload")
+	}
+	uid, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", err
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, "", err
+	}
+	if time.Now().Unix() > expiresAt {
+		return 0, "", errors.New("token expired")
+	}
+	return uid, parts[2], nil
+}
+
+func (s *Service) verificationMAC(payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.cfg.AuthConfig.EmailVerificationSecret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_VerifyEmailService(t *testing.T) {
+	cfg := config.Config{AuthConfig: config.AuthConfig{
+		EmailVerification:       config.EmailVerificationLogin,
+		EmailVerificationSecret: "secret",
+	}}
+	verifiedAt := time.Now()
+	pending := "new@example.com"
+	tests := []struct {
+		name    string
+		token   func(s *Service) string
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name: "tampered token",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "test@example.com", time.Now().Add(time.Hour)) + "x"
+			},
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrInvalidVerificationToken,
+		},
+		{
+			name: "signed with an empty key",
+			token: func(s *Service) string {
+				unsigned := *s
+				unsigned.cfg.AuthConfig.EmailVerificationSecret = ""
+				return unsigned.signVerificationToken(1, "test@example.com", time.Now().Add(time.Hour))
+			},
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrInvalidVerificationToken,
+		},
+		{
+			name: "expired token",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "test@example.com", time.Now().Add(-time.Minute))
+			},
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrInvalidVerificationToken,
+		},
+		{
+			name: "email changed since the link was sent",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "old@example.com", time.Now().Add(time.Hour))
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com"}, nil).Times(1)
+			},
+			wantErr: ErrInvalidVerificationToken,
+		},
+		{
+			name: "already verified",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "test@example.com", time.Now().Add(time.Hour))
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com", EmailVerifiedAt: &verifiedAt}, nil).Times(1)
+			},
+		},
+		{
+			name: "link sent to the new address of an email change",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "new@example.com", time.Now().Add(time.Hour))
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com", EmailVerifiedAt: &verifiedAt, PendingEmail: &pending}, nil).Times(1)
+				mockRepo.EXPECT().ConfirmEmailChange(gomock.Any(), uint(0), "new@example.com").Return(true, nil).Times(1)
+			},
+		},
+		{
+			name: "email change superseded by another one",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "new@example.com", time.Now().Add(time.Hour))
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com", PendingEmail: &pending}, nil).Times(1)
+				mockRepo.EXPECT().ConfirmEmailChange(gomock.Any(), uint(0), "new@example.com").Return(false, nil).Times(1)
+			},
+			wantErr: ErrInvalidVerificationToken,
+		},
+		{
+			name: "success",
+			token: func(s *Service) string {
+				return s.signVerificationToken(1, "test@example.com", time.Now().Add(time.Hour))
+			},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com"}, nil).Times(1)
+				mockRepo.EXPECT().MarkEmailVerified(gomock.Any(), uint(0)).Return(nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setup(mockRepo)
+			svc, _ := NewService(mockRepo, &auth.Auth{}, nil, cfg)
+			s := svc.(*Service)
+			err := s.VerifyEmailService(context.Background(), tt.token(s))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyEmailService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_UserLoginService_unverifiedEmail(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().VerifyUserCredentials(gomock.Any(), "test@example.com").Return(models.User{
+		PasswordHash: "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y", // Valid hash for "validpassword"
+	}, nil).Times(1)
+
+	cfg := config.Config{AuthConfig: config.AuthConfig{
+		EmailVerification:       config.EmailVerificationLogin,
+		EmailVerificationSecret: "secret",
+	}}
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, cfg)
+	_, err := s.UserLoginService(context.Background(), models.NewUser{Email: "test@example.com", Password: "validpassword"})
+	if !errors.Is(err, ErrEmailNotVerified) {
+		t.Errorf("UserLoginService() error = %v, want %v", err, ErrEmailNotVerified)
+	}
+}
+
+func TestService_ResendVerificationService(t *testing.T) {
+	cfg := config.Config{
+		AppConfig: config.AppConfig{BaseURL: "https://jobs.example.com/"},
+		AuthConfig: config.AuthConfig{
+			EmailVerification:       config.EmailVerificationLogin,
+			EmailVerificationSecret: "secret",
+		},
+	}
+	verifiedAt := time.Now()
+	tests := []struct {
+		name       string
+		setup      func(mockRepo *repository.MockUserRepo, queued *[]models.OutboxMessage)
+		wantQueued int
+		wantErr    bool
+	}{
+		{
+			name: "unknown email",
+			setup: func(mockRepo *repository.MockUserRepo, queued *[]models.OutboxMessage) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(models.User{}, errors.New("user not found")).Times(1)
+			},
+		},
+		{
+			name: "already verified",
+			setup: func(mockRepo *repository.MockUserRepo, queued *[]models.OutboxMessage) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(models.User{Email: "test@example.com", EmailVerifiedAt: &verifiedAt}, nil).Times(1)
+			},
+		},
+		{
+			name: "error queueing the email",
+			setup: func(mockRepo *repository.MockUserRepo, queued *[]models.OutboxMessage) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(models.User{Email: "test@example.com"}, nil).Times(1)
+				mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).Return(errors.New("failed to queue the message")).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			setup: func(mockRepo *repository.MockUserRepo, queued *[]models.OutboxMessage) {
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(models.User{Email: "test@example.com"}, nil).Times(1)
+				mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+					*queued = append(*queued, messages...)
+					return nil
+				}).Times(1)
+			},
+			wantQueued: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			var queued []models.OutboxMessage
+			tt.setup(mockRepo, &queued)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, cfg)
+			err := s.ResendVerificationService(context.Background(), "Test@Example.com")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResendVerificationService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(queued) != tt.wantQueued {
+				t.Fatalf("ResendVerificationService() queued %d emails, want %d", len(queued), tt.wantQueued)
+			}
+			if tt.wantQueued == 0 {
+				return
+			}
+			if queued[0].Kind != models.OutboxKindEmail || queued[0].Destination != "test@example.com" || queued[0].Summary != "Verify your email address" {
+				t.Errorf("ResendVerificationService() queued %+v", queued[0])
+			}
+			var msg mail.Message
+			err = json.Unmarshal(queued[0].Payload, &msg)
+			if err != nil {
+				t.Fatalf("invalid payload: %v", err)
+			}
+			if !strings.Contains(msg.Text, "https://jobs.example.com/api/verify-email?token=") || !strings.Contains(msg.HTML, `href="https://jobs.example.com/api/verify-email?token=`) {
+				t.Errorf("ResendVerificationService() email is missing the link:\n%s\n%s", msg.Text, msg.HTML)
+			}
+		})
+	}
+}
+
+func TestService_VerifyEmailService_noSecret(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	// verification is off and no secret is set, a token signed with the empty key is forged
+	svc, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	s := svc.(*Service)
+	token := s.signVerificationToken(1, "victim@example.com", time.Now().Add(time.Hour))
+	err := s.VerifyEmailService(context.Background(), token)
+	if !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("VerifyEmailService() error = %v, want %v", err, ErrInvalidVerificationToken)
+	}
+}
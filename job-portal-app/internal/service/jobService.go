@@ -3,16 +3,20 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/rs/zerolog/log"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 func (s *Service) GetJobPostingByIDService(ctx context.Context, jid uint64) (models.Jobs, error) {
 	if jid < uint64(0) {
 		return models.Jobs{}, errors.New("id cannot be 0")
 	}
-	jobData, err := s.UserRepo.FetchJobPostingByID(ctx, jid)
+	jobData, err := s.fetchJob(ctx, jid)
 	if err != nil {
 		return models.Jobs{}, err
 	}
@@ -27,15 +31,109 @@ func (s *Service) GetAllJobPostingsService(ctx context.Context) ([]models.Jobs,
 	return jobDatas, nil
 }
 
-func (s *Service) CreateJobPostingService(ctx context.Context, jobData models.NewJobRequest, cid uint64) (models.NewJobResponse, error) {
+func (s *Service) CreateJobPostingService(ctx context.Context, actor models.Actor, jobData models.NewJobRequest, cid uint64) (models.NewJobResponse, error) {
+	err := s.authorizeCompany(ctx, actor, cid)
+	if err != nil {
+		return models.NewJobResponse{}, err
+	}
 	jobData.Cid = uint(cid)
-	jobDatas, err := s.UserRepo.InsertJobPosting(ctx, jobData)
+	var jobDatas models.NewJobResponse
+	// the job.created webhooks and the matching against job preferences are queued with the
+	// job, so neither happens for a job that failed to save
+	err = s.UserRepo.Transaction(ctx, func(tx repository.UserRepo) error {
+		jobDatas, err = tx.InsertJobPosting(ctx, jobData)
+		if err != nil {
+			return err
+		}
+		err = publishWebhookEvent(ctx, tx, jobData.Cid, models.WebhookEventJobCreated, models.WebhookJobData{
+			JobID:       jobDatas.ID,
+			CompanyID:   jobData.Cid,
+			Description: jobData.Description,
+		})
+		if err != nil {
+			return err
+		}
+		match, err := newJobMatchMessage(jobDatas.ID)
+		if err != nil {
+			return err
+		}
+		return tx.EnqueueOutbox(ctx, match)
+	})
 	if err != nil {
 		return models.NewJobResponse{}, err
 	}
+	s.audit(ctx, actorEvent(actor, models.AuditEvent{EventType: models.AuditJobCreated, Details: map[string]string{"job_id": fmt.Sprint(jobDatas.ID), "company_id": fmt.Sprint(cid)}}))
 	return jobDatas, nil
 }
 
+var (
+	// ErrJobClosed is returned when closing a job that is already closed.
+	ErrJobClosed = errors.New("job is already closed")
+	// ErrJobNotFound is returned when the job does not exist.
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// CloseJobPostingService stops the job from taking applications. The actor must be a member
+// of the company that owns the job.
+func (s *Service) CloseJobPostingService(ctx context.Context, actor models.Actor, jid uint64) (models.Jobs, error) {
+	job, err := s.fetchJob(ctx, jid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	if job.ID == 0 {
+		return models.Jobs{}, ErrJobNotFound
+	}
+	err = s.authorizeCompany(ctx, actor, uint64(job.Cid))
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	closedAt := time.Now()
+	err = s.UserRepo.Transaction(ctx, func(tx repository.UserRepo) error {
+		closed, err := tx.CloseJobPosting(ctx, jid, closedAt)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return ErrJobClosed
+		}
+		return publishWebhookEvent(ctx, tx, job.Cid, models.WebhookEventJobClosed, models.WebhookJobData{
+			JobID:       job.ID,
+			CompanyID:   job.Cid,
+			Description: job.Description,
+			ClosedAt:    &closedAt,
+		})
+	})
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	s.invalidateJob(ctx, jid)
+	s.audit(ctx, actorEvent(actor, models.AuditEvent{EventType: models.AuditJobClosed, Details: map[string]string{"job_id": fmt.Sprint(job.ID), "company_id": fmt.Sprint(job.Cid)}}))
+	job.ClosedAt = &closedAt
+	return job, nil
+}
+
+// UpdateJobPostingService replaces the details of a job. The actor must be a member of the
+// company that owns the job.
+func (s *Service) UpdateJobPostingService(ctx context.Context, actor models.Actor, jid uint64, jobData models.NewJobRequest) (models.Jobs, error) {
+	job, err := s.fetchJob(ctx, jid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	if job.ID == 0 {
+		return models.Jobs{}, ErrJobNotFound
+	}
+	err = s.authorizeCompany(ctx, actor, uint64(job.Cid))
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	job, err = s.UserRepo.UpdateJobPosting(ctx, jid, jobData)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	s.invalidateJob(ctx, jid)
+	return job, nil
+}
+
 func (s *Service) ListJobsForCompanyService(ctx context.Context, cid uint64) ([]models.Jobs, error) {
 	jobData, err := s.UserRepo.FetchJobsForCompany(ctx, cid)
 	if err != nil {
@@ -44,33 +142,58 @@ func (s *Service) ListJobsForCompanyService(ctx context.Context, cid uint64) ([]
 	return jobData, nil
 }
 
-func (s *Service) ApplicationProcessor(ctx context.Context, jobApplications []models.RequestJob) ([]models.RequestJob, error) {
+// ApplicationProcessor screens applications against their jobs and returns the ones that
+// match. Every job must belong to a company the actor is a member of. Applications to closed
+// jobs are rejected, and ErrJobNotFound is returned when a job does not exist. The outcome of each application is sent to the company's webhooks and
+// to the candidate's notifications.
+func (s *Service) ApplicationProcessor(ctx context.Context, actor models.Actor, jobApplications []models.RequestJob) ([]models.RequestJob, error) {
+	var companies map[uint]bool
+	switch {
+	case actor.Role == models.RoleAdmin:
+	case actor.APIKeyID != 0:
+		// service account keys are not tied to a company, so they process no applications
+		if actor.CompanyID == 0 {
+			return nil, ErrNotCompanyMember
+		}
+		companies = map[uint]bool{actor.CompanyID: true}
+	default:
+		ids, err := s.UserRepo.FetchCompanyIDsForUser(ctx, actor.UserID)
+		if err != nil {
+			return nil, err
+		}
+		companies = make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			companies[id] = true
+		}
+	}
+
 	ch := make(chan models.RequestJob)
 	var wg sync.WaitGroup
+	var forbidden, missing atomic.Bool
+	var mu sync.Mutex
+	var outcomes []applicationOutcome
 
 	for _, application := range jobApplications {
 		wg.Add(1)
 		go func(application models.RequestJob) {
 			defer wg.Done()
-			//var redisJob models.Jobs
-			job, err := s.rdb.GetData(ctx, application.Jid)
+			job, err := s.fetchJob(ctx, application.Jid)
 			if err != nil {
-				job, err = s.UserRepo.FetchJobPostingByID(ctx, application.Jid)
-				if err != nil {
-					return
-				}
-				//fmt.Println("data from db", application.Name, dbData)
-				s.rdb.SetData(ctx, application.Jid, job)
+				return
 			}
-			//else {
-			//fmt.Println(val, "data before unmarshal")
-			//err = json.Unmarshal([]byte(val), &redisJob)
-			//fmt.Println(redisJob, "data after unmarshal", err)
-			//if err == nil {
-			//	return
-			//}
-			//}
-			if validateJobApplication(application, job) {
+			if job.ID == 0 {
+				missing.Store(true)
+				return
+			}
+			if companies != nil && !companies[job.Cid] {
+				forbidden.Store(true)
+				return
+			}
+			accepted := validateJobApplication(application, job)
+			mu.Lock()
+			outcomes = append(outcomes, applicationOutcome{application: application, cid: job.Cid, description: job.Description, accepted: accepted})
+			mu.Unlock()
+			if accepted {
 				ch <- application
 			}
 		}(application)
@@ -85,133 +208,215 @@ func (s *Service) ApplicationProcessor(ctx context.Context, jobApplications []mo
 	for app := range ch {
 		result = append(result, app)
 	}
+	if missing.Load() {
+		return nil, ErrJobNotFound
+	}
+	if forbidden.Load() {
+		return nil, ErrNotCompanyMember
+	}
+	s.reportApplicationOutcomes(ctx, outcomes)
+	s.audit(ctx, actorEvent(actor, models.AuditEvent{EventType: models.AuditApplicationsScreened, Details: map[string]string{"applications": fmt.Sprint(len(jobApplications)), "accepted": fmt.Sprint(len(result))}}))
 
 	return result, nil
 }
 
-func validateJobApplication(application models.RequestJob, job models.Jobs) bool {
+type applicationOutcome struct {
+	application models.RequestJob
+	cid         uint
+	description string
+	accepted    bool
+}
 
-	// Check Notice Period
-	if application.NoticePeriod < job.MinNP || application.NoticePeriod > job.MaxNP {
-		log.Print("Notice period criteria not matched :-", application.Name)
-		return false
-	}
-	// Compare Budget
-	if application.Budget > job.Budget {
-		log.Print("Budget criteria not matched :-", application.Name)
-		return false
-	}
-	// Check for matching location IDs
-	foundLocation := false
-	for _, v := range application.LocationsIDs {
-		for _, c := range job.Locations {
-			if v == c.ID {
-				foundLocation = true
-				break
+// reportApplicationOutcomes queues the application webhooks and notifies the candidates
+// with an account. The screening itself is not stored, so a failure here is logged rather
+// than failing the request.
+func (s *Service) reportApplicationOutcomes(ctx context.Context, outcomes []applicationOutcome) {
+	endpoints := map[uint][]models.WebhookEndpoint{}
+	candidates := s.applicationCandidates(ctx, outcomes)
+	var notifications []models.Notification
+	for _, o := range outcomes {
+		if locale, ok := candidates[o.application.CandidateID]; ok {
+			notifications = append(notifications, applicationNotification(o, locale))
+		}
+		companyEndpoints, ok := endpoints[o.cid]
+		if !ok {
+			var err error
+			companyEndpoints, err = s.UserRepo.FetchWebhookEndpoints(ctx, o.cid)
+			if err != nil {
+				log.Error().Err(err).Uint("company id", o.cid).Msg("failed to fetch webhook endpoints")
 			}
+			endpoints[o.cid] = companyEndpoints
+		}
+		event := models.WebhookEventApplicationRejected
+		if o.accepted {
+			event = models.WebhookEventApplicationAccepted
+		}
+		err := queueWebhookEvent(ctx, s.UserRepo, companyEndpoints, event, models.WebhookApplicationData{
+			JobID:     o.application.Jid,
+			CompanyID: o.cid,
+			Name:      o.application.Name,
+		})
+		if err != nil {
+			log.Error().Err(err).Uint64("job id", o.application.Jid).Msg("failed to queue application webhook")
 		}
 	}
-	if !foundLocation {
-		log.Print("location criteria not matched :-", application.Name)
-		return false
+	if len(notifications) == 0 {
+		return
 	}
+	err := s.UserRepo.InsertNotifications(ctx, notifications...)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to save application notifications")
+	}
+}
 
-	requiredTechCount := len(job.TechnologyStacks) / 2 // 50% of required technologies
+// ExplainJobApplicationService evaluates a single application against the job without
+// storing anything, and reports the outcome of every criterion.
+func (s *Service) ExplainJobApplicationService(ctx context.Context, jid uint64, application models.RequestJob) (models.ApplicationEvaluation, error) {
+	job, err := s.fetchJob(ctx, jid)
+	if err != nil {
+		return models.ApplicationEvaluation{}, err
+	}
+	if job.ID == 0 {
+		return models.ApplicationEvaluation{}, ErrJobNotFound
+	}
+	application.Jid = jid
+	return evaluateJobApplication(application, job), nil
+}
 
-	// Count how many technologies in the application match the required ones
-	matchingTechCount := 0
-	for _, techID := range application.TechnologyStackIDs {
-		for _, reqTechID := range job.TechnologyStacks {
-			if techID == reqTechID.ID {
-				matchingTechCount++
-				break
-			}
+func validateJobApplication(application models.RequestJob, job models.Jobs) bool {
+	evaluation := evaluateJobApplication(application, job)
+	for _, criterion := range evaluation.Criteria {
+		if !criterion.Passed {
+			log.Print(criterion.Criterion, " criteria not matched :-", application.Name)
 		}
 	}
-	if matchingTechCount < requiredTechCount {
-		log.Print("Skills criteria not matched :-", application.Name)
-		return false
+	return evaluation.Eligible
+}
+
+// evaluateJobApplication checks every criterion of the job against the application. Unlike a
+// plain pass/fail check it does not stop at the first failure, so the caller gets the complete
+// list of criteria that would have to change.
+func evaluateJobApplication(application models.RequestJob, job models.Jobs) models.ApplicationEvaluation {
+	var criteria []models.CriterionResult
+
+	// A closed job takes no applications. It is only listed when closed, so the criteria of
+	// open jobs are unchanged.
+	if job.ClosedAt != nil {
+		criteria = append(criteria, criterionResult("open", false, "the job must still be open"))
 	}
-	// Check Work Mode
-	workModeSatisfied := false
-	for _, appWorkMode := range application.WorkModeIDs {
-		for _, jobWorkMode := range job.WorkModes {
-			if appWorkMode == jobWorkMode.ID {
-				workModeSatisfied = true
-				break
-			}
-		}
-		if workModeSatisfied {
-			break
-		}
+
+	// Check Notice Period
+	npRequirement := fmt.Sprintf("notice period must be between %d and %d days", job.MinNP, job.MaxNP)
+	if job.MinNP <= 0 {
+		npRequirement = fmt.Sprintf("notice period must be %d days or less", job.MaxNP)
 	}
+	criteria = append(criteria, criterionResult("noticePeriod",
+		application.NoticePeriod >= job.MinNP && application.NoticePeriod <= job.MaxNP, npRequirement))
 
-	if !workModeSatisfied {
-		log.Print("workMode criteria not matched :-", application.Name)
-		return false
+	// Compare Budget
+	criteria = append(criteria, criterionResult("budget",
+		application.Budget <= job.Budget,
+		fmt.Sprintf("expected budget must be %d or less", job.Budget)))
 
+	// Check for matching location IDs
+	var locationIDs []uint
+	for _, l := range job.Locations {
+		locationIDs = append(locationIDs, l.ID)
 	}
+	criteria = append(criteria, criterionResult("location",
+		containsAny(application.LocationsIDs, locationIDs),
+		fmt.Sprintf("locations must include at least one of %v", locationIDs)))
 
-	// Check Experience
-	if application.Experience < job.MinExp || application.Experience > job.MaxExp {
-		log.Print("experience criteria not match :-", application.Name)
-		return false
+	// At least 50% of the required technologies must match
+	var techIDs []uint
+	for _, t := range job.TechnologyStacks {
+		techIDs = append(techIDs, t.ID)
+	}
+	requiredTechCount := len(techIDs) / 2
+	matchingTechCount := countMatching(application.TechnologyStackIDs, techIDs)
+	criteria = append(criteria, criterionResult("skills",
+		matchingTechCount >= requiredTechCount,
+		fmt.Sprintf("technology stacks must include at least %d of %v, %d matched", requiredTechCount, techIDs, matchingTechCount)))
+
+	// Check Work Mode
+	var workModeIDs []uint
+	for _, w := range job.WorkModes {
+		workModeIDs = append(workModeIDs, w.ID)
 	}
+	criteria = append(criteria, criterionResult("workMode",
+		containsAny(application.WorkModeIDs, workModeIDs),
+		fmt.Sprintf("work modes must include at least one of %v", workModeIDs)))
+
+	// Check Experience
+	criteria = append(criteria, criterionResult("experience",
+		application.Experience >= job.MinExp && application.Experience <= job.MaxExp,
+		fmt.Sprintf("experience must be between %d and %d years", job.MinExp, job.MaxExp)))
 
-	// Check for at least 2 qualifications
 	// Check Qualifications
-	requiredQualifications := job.Qualifications // List of required qualifications
+	var qualificationIDs []uint
+	for _, q := range job.Qualifications {
+		qualificationIDs = append(qualificationIDs, q.ID)
+	}
+	criteria = append(criteria, criterionResult("qualification",
+		containsAny(application.QualificationIDs, qualificationIDs),
+		fmt.Sprintf("qualifications must include at least one of %v", qualificationIDs)))
 
-	qualificationSatisfied := false
-	for _, reqQualificationID := range requiredQualifications {
-		for _, appQualificationID := range application.QualificationIDs {
-			if reqQualificationID.ID == appQualificationID {
-				qualificationSatisfied = true
-				break
-			}
-		}
-		if qualificationSatisfied {
-			break
-		}
+	// Check Shifts
+	var shiftIDs []uint
+	for _, sh := range job.Shifts {
+		shiftIDs = append(shiftIDs, sh.ID)
 	}
+	criteria = append(criteria, criterionResult("shift",
+		containsAny(application.ShiftIDs, shiftIDs),
+		fmt.Sprintf("shifts must include at least one of %v", shiftIDs)))
 
-	if !qualificationSatisfied {
-		log.Print("qualification criteria not match :-", application.Name)
-		return false
+	// Check Job Type
+	var jobTypeIDs []uint
+	for _, jt := range job.JobTypes {
+		jobTypeIDs = append(jobTypeIDs, jt.ID)
 	}
-	// Check Shifts
-	shiftSatisfied := false
-	for _, appShift := range application.ShiftIDs {
-		for _, jobShift := range job.Shifts {
-			if appShift == jobShift.ID {
-				shiftSatisfied = true
-				break
-			}
-		}
-		if shiftSatisfied {
+	criteria = append(criteria, criterionResult("jobType",
+		containsAny(application.JobTypeIDs, jobTypeIDs),
+		fmt.Sprintf("job types must include at least one of %v", jobTypeIDs)))
+
+	eligible := true
+	for _, c := range criteria {
+		if !c.Passed {
+			eligible = false
 			break
 		}
 	}
-	if !shiftSatisfied {
-		log.Print("shift criteria not matched :-", application.Name)
-		return false
+	return models.ApplicationEvaluation{
+		Jid:      uint64(job.ID),
+		Eligible: eligible,
+		Criteria: criteria,
 	}
-	// Check Job Type
-	jobTypeSatisfied := false
-	for _, appJobType := range application.JobTypeIDs {
-		for _, jobJobType := range job.JobTypes {
-			if appJobType == jobJobType.ID {
-				jobTypeSatisfied = true
+}
+
+func criterionResult(criterion string, passed bool, requirement string) models.CriterionResult {
+	res := models.CriterionResult{
+		Criterion: criterion,
+		Passed:    passed,
+	}
+	if !passed {
+		res.Requirement = requirement
+	}
+	return res
+}
+
+func containsAny(ids []uint, required []uint) bool {
+	return countMatching(ids, required) > 0
+}
+
+func countMatching(ids []uint, required []uint) int {
+	count := 0
+	for _, id := range ids {
+		for _, r := range required {
+			if id == r {
+				count++
 				break
 			}
 		}
-		if jobTypeSatisfied {
-			break
-		}
-	}
-	if !jobTypeSatisfied {
-		log.Print("jobType criteria not match :-", application.Name)
-		return false
 	}
-	return true
+	return count
 }
@@ -6,10 +6,13 @@ import (
 	"go.uber.org/mock/gomock"
 	"gorm.io/gorm"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
 	"job-portal-api/internal/repository"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestService_GetJobPostingByIDService(t *testing.T) {
@@ -24,9 +27,9 @@ func TestService_GetJobPostingByIDService(t *testing.T) {
 	tests := []struct {
 		name             string
 		args             args
-		want             models.NewJobRequest
+		want             models.Jobs
 		wantErr          bool
-		mockRepoResponse func() (models.NewJobRequest, error)
+		mockRepoResponse func() (models.Jobs, error)
 	}{
 		{
 			name: "error from db",
@@ -34,10 +37,10 @@ func TestService_GetJobPostingByIDService(t *testing.T) {
 				ctx: context.Background(),
 				jid: 15,
 			},
-			want:    models.NewJobRequest{},
+			want:    models.Jobs{},
 			wantErr: true,
-			mockRepoResponse: func() (models.NewJobRequest, error) {
-				return models.NewJobRequest{}, errors.New("test error")
+			mockRepoResponse: func() (models.Jobs, error) {
+				return models.Jobs{}, errors.New("test error")
 			},
 		},
 		{
@@ -46,12 +49,12 @@ func TestService_GetJobPostingByIDService(t *testing.T) {
 				ctx: context.Background(),
 				jid: 15,
 			},
-			want: models.NewJobRequest{
+			want: models.Jobs{
 				Cid: 1,
 			},
 			wantErr: false,
-			mockRepoResponse: func() (models.NewJobRequest, error) {
-				return models.NewJobRequest{
+			mockRepoResponse: func() (models.Jobs, error) {
+				return models.Jobs{
 					Cid: 1,
 				}, nil
 			},
@@ -62,10 +65,10 @@ func TestService_GetJobPostingByIDService(t *testing.T) {
 				ctx: context.Background(),
 				jid: 5,
 			},
-			want:    models.NewJobRequest{},
+			want:    models.Jobs{},
 			wantErr: true,
-			mockRepoResponse: func() (models.NewJobRequest, error) {
-				return models.NewJobRequest{}, errors.New("invalid job id")
+			mockRepoResponse: func() (models.Jobs, error) {
+				return models.Jobs{}, errors.New("invalid job id")
 			},
 		},
 	}
@@ -73,10 +76,11 @@ func TestService_GetJobPostingByIDService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().FetchJobPostingByID(tt.args.ctx, tt.args.jid).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
 			got, err := s.GetJobPostingByIDService(tt.args.ctx, tt.args.jid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetJobPostingByIDService() error = %v, wantErr %v", err, tt.wantErr)
@@ -145,10 +149,11 @@ func TestService_GetAllJobPostingsService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().FetchAllJobPostings(tt.args.ctx).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
 			got, err := s.GetAllJobPostingsService(tt.args.ctx)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllJobPostingsService() error = %v, wantErr %v", err, tt.wantErr)
@@ -176,8 +181,19 @@ func TestService_CreateJobPostingService(t *testing.T) {
 		args             args
 		want             models.NewJobResponse
 		wantErr          bool
+		member           models.CompanyMember
 		mockRepoResponse func() (models.NewJobResponse, error)
 	}{
+		{
+			name: "not a member of the company",
+			args: args{
+				context.Background(),
+				models.NewJobRequest{},
+				1,
+			},
+			want:    models.NewJobResponse{},
+			wantErr: true,
+		},
 		{
 			name: "error from db",
 			args: args{
@@ -187,6 +203,7 @@ func TestService_CreateJobPostingService(t *testing.T) {
 			},
 			want:    models.NewJobResponse{},
 			wantErr: true,
+			member:  models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleRecruiter},
 			mockRepoResponse: func() (models.NewJobResponse, error) {
 				return models.NewJobResponse{}, errors.New("db error")
 			},
@@ -204,6 +221,7 @@ func TestService_CreateJobPostingService(t *testing.T) {
 				ID: 1,
 			},
 			wantErr: false,
+			member:  models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleOwner},
 			mockRepoResponse: func() (models.NewJobResponse, error) {
 				return models.NewJobResponse{
 					ID: 1,
@@ -215,11 +233,25 @@ func TestService_CreateJobPostingService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockRepo.EXPECT().FetchCompanyMember(tt.args.ctx, tt.args.cid, uint(7)).Return(tt.member, nil).Times(1)
 			if tt.mockRepoResponse != nil {
+				mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+					return fn(mockRepo)
+				}).Times(1)
 				mockRepo.EXPECT().InsertJobPosting(tt.args.ctx, gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
+				mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), uint(1)).Return(nil, nil).AnyTimes()
+				if !tt.wantErr {
+					mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+						if len(messages) != 1 || messages[0].Kind != models.OutboxKindJobMatch {
+							t.Errorf("CreateJobPostingService() queued %+v, want the job match", messages)
+						}
+						return nil
+					}).Times(1)
+				}
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
-			got, err := s.CreateJobPostingService(tt.args.ctx, tt.args.jobData, tt.args.cid)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.CreateJobPostingService(tt.args.ctx, models.Actor{UserID: 7, Role: models.RoleRecruiter}, tt.args.jobData, tt.args.cid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateJobPostingService() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -298,10 +330,11 @@ func TestService_ListJobsForCompanyService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().FetchJobsForCompany(tt.args.ctx, tt.args.cid).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
 			got, err := s.ListJobsForCompanyService(tt.args.ctx, tt.args.cid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListJobsForCompanyService() error = %v, wantErr %v", err, tt.wantErr)
@@ -314,6 +347,69 @@ func TestService_ListJobsForCompanyService(t *testing.T) {
 	}
 }
 
+func TestService_CloseJobPostingService(t *testing.T) {
+	closedAt := time.Now()
+	subscribers := []models.WebhookEndpoint{
+		{ID: 3, CompanyID: 1, URL: "https://hooks.example.com/a", Events: []string{models.WebhookEventJobClosed}},
+		{ID: 4, CompanyID: 1, URL: "https://hooks.example.com/b", Events: []string{models.WebhookEventJobCreated}},
+	}
+	tests := []struct {
+		name       string
+		job        models.Jobs
+		closed     bool
+		wantQueued []string
+		wantErr    error
+	}{
+		{
+			name:    "already closed",
+			job:     models.Jobs{Model: gorm.Model{ID: 9}, Cid: 1, ClosedAt: &closedAt},
+			wantErr: ErrJobClosed,
+		},
+		{
+			name:       "closed and sent to the subscribed endpoints",
+			job:        models.Jobs{Model: gorm.Model{ID: 9}, Cid: 1},
+			closed:     true,
+			wantQueued: []string{"https://hooks.example.com/a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			tx := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(tt.job, nil).Times(1)
+			mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(webhookOwner, nil).Times(1)
+			mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+				return fn(tx)
+			}).Times(1)
+			tx.EXPECT().CloseJobPosting(gomock.Any(), uint64(9), gomock.Any()).Return(tt.closed, nil).Times(1)
+			var queued []string
+			if tt.closed {
+				tx.EXPECT().FetchWebhookEndpoints(gomock.Any(), uint(1)).Return(subscribers, nil).Times(1)
+				tx.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+					for _, m := range messages {
+						queued = append(queued, m.Destination)
+					}
+					return nil
+				}).Times(1)
+			}
+
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.CloseJobPostingService(context.Background(), models.Actor{UserID: 2, Role: models.RoleRecruiter}, 9)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CloseJobPostingService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(queued, tt.wantQueued) {
+				t.Errorf("CloseJobPostingService() queued %v, want %v", queued, tt.wantQueued)
+			}
+			if err == nil && got.ClosedAt == nil {
+				t.Errorf("CloseJobPostingService() returned an open job")
+			}
+		})
+	}
+}
+
 func TestService_ApplicationProcessor(t *testing.T) {
 	type args struct {
 		ctx       context.Context
@@ -508,7 +604,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(2)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 2},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -534,7 +630,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 3},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -560,7 +656,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(4)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 4},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -586,7 +682,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(5)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 5},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -612,7 +708,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(6)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 6},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -639,7 +735,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(7)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 7},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -666,7 +762,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(8)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 8},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -693,7 +789,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 9},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -720,7 +816,7 @@ func TestService_ApplicationProcessor(t *testing.T) {
 					},
 				}, nil).Times(1)
 				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(10)).Return(models.Jobs{
-					Model:  gorm.Model{ID: 1},
+					Model:  gorm.Model{ID: 10},
 					Cid:    1,
 					MinNP:  0,
 					MaxNP:  2,
@@ -754,11 +850,14 @@ func TestService_ApplicationProcessor(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			tt.setup(mockRepo)
+			mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 			s := &Service{
 				UserRepo: mockRepo,
+				rdb:      redis.NewMemory(),
 			}
-			got, err := s.ApplicationProcessor(tt.args.ctx, tt.args.applicant)
+			got, err := s.ApplicationProcessor(tt.args.ctx, models.Actor{UserID: 1, Role: models.RoleAdmin}, tt.args.applicant)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ApplicationProcessor() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -769,3 +868,256 @@ func TestService_ApplicationProcessor(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ExplainJobApplicationService(t *testing.T) {
+	job := models.Jobs{
+		Model:  gorm.Model{ID: 3},
+		MinNP:  0,
+		MaxNP:  30,
+		Budget: 600000,
+		Locations: []models.Locations{
+			{Model: gorm.Model{ID: 1}},
+		},
+		TechnologyStacks: []models.TechnologyStacks{
+			{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}},
+		},
+		WorkModes: []models.WorkModes{
+			{Model: gorm.Model{ID: 1}},
+		},
+		MinExp: 2,
+		MaxExp: 7,
+		Qualifications: []models.Qualifications{
+			{Model: gorm.Model{ID: 1}},
+		},
+		Shifts: []models.Shifts{
+			{Model: gorm.Model{ID: 1}},
+		},
+		JobTypes: []models.JobTypes{
+			{Model: gorm.Model{ID: 1}},
+		},
+	}
+	matching := models.RequestJob{
+		Name:               "candidate",
+		NoticePeriod:       15,
+		Budget:             500000,
+		LocationsIDs:       []uint{1},
+		TechnologyStackIDs: []uint{1},
+		WorkModeIDs:        []uint{1},
+		Experience:         3,
+		QualificationIDs:   []uint{1},
+		ShiftIDs:           []uint{1},
+		JobTypeIDs:         []uint{1},
+	}
+	tooLong := matching
+	tooLong.NoticePeriod = 45
+	tooLong.Budget = 700000
+
+	tests := []struct {
+		name        string
+		application models.RequestJob
+		setup       func(mockRepo *repository.MockUserRepo)
+		wantErr     bool
+		wantFailed  map[string]string
+	}{
+		{
+			name:        "error from db",
+			application: matching,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(models.Jobs{}, errors.New("db error")).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "job not found",
+			application: matching,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(models.Jobs{}, nil).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "all criteria pass",
+			application: matching,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(job, nil).Times(1)
+			},
+			wantFailed: map[string]string{},
+		},
+		{
+			name:        "every failing criterion is reported",
+			application: tooLong,
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(job, nil).Times(1)
+			},
+			wantFailed: map[string]string{
+				"noticePeriod": "notice period must be 30 days or less",
+				"budget":       "expected budget must be 600000 or less",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			tt.setup(mockRepo)
+			s := &Service{
+				UserRepo: mockRepo,
+			}
+			got, err := s.ExplainJobApplicationService(context.Background(), 3, tt.application)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExplainJobApplicationService() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Eligible != (len(tt.wantFailed) == 0) {
+				t.Errorf("ExplainJobApplicationService() eligible = %v, want %v", got.Eligible, len(tt.wantFailed) == 0)
+			}
+			if len(got.Criteria) != 9 {
+				t.Errorf("ExplainJobApplicationService() returned %d criteria, want 9", len(got.Criteria))
+			}
+			failed := map[string]string{}
+			for _, c := range got.Criteria {
+				if !c.Passed {
+					failed[c.Criterion] = c.Requirement
+				}
+			}
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("ExplainJobApplicationService() failed criteria = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestService_ApplicationProcessor_jobCache(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	// only the first screening reads the job from the database
+	mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(1)).Return(models.Jobs{Model: gorm.Model{ID: 1}, Cid: 1, Budget: 100}, nil).Times(1)
+	// unknown jobs are not cached
+	mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(2)).Return(models.Jobs{}, nil).Times(2)
+	s := &Service{
+		UserRepo: mockRepo,
+		rdb:      redis.NewMemory(),
+		cfg:      config.Config{RedisConfig: config.RedisConfig{JobTTL: time.Minute}},
+	}
+	for i := 0; i < 2; i++ {
+		_, err := s.ApplicationProcessor(context.Background(), models.Actor{UserID: 1, Role: models.RoleAdmin}, []models.RequestJob{{Name: "A", Jid: 1}, {Name: "B", Jid: 2}})
+		if !errors.Is(err, ErrJobNotFound) {
+			t.Fatalf("ApplicationProcessor() error = %v, want %v", err, ErrJobNotFound)
+		}
+	}
+}
+
+func TestService_ApplicationProcessor_apiKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   models.Actor
+		wantErr error
+	}{
+		{
+			name:  "company key on its own company",
+			actor: models.Actor{APIKeyID: 1, CompanyID: 1, Role: models.RoleService},
+		},
+		{
+			name:    "company key on another company",
+			actor:   models.Actor{APIKeyID: 1, CompanyID: 2, Role: models.RoleService},
+			wantErr: ErrNotCompanyMember,
+		},
+		{
+			name:    "service account key",
+			actor:   models.Actor{APIKeyID: 1, Role: models.RoleService},
+			wantErr: ErrNotCompanyMember,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, event models.AuditEvent) error {
+				// the key is recorded as who screened the applications
+				if event.ActorAPIKeyID == nil || *event.ActorAPIKeyID != tt.actor.APIKeyID || event.ActorUserID != nil {
+					t.Errorf("audited %+v, want api key %d as the actor", event, tt.actor.APIKeyID)
+				}
+				return nil
+			}).AnyTimes()
+			mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(1)).Return(models.Jobs{Model: gorm.Model{ID: 1}, Cid: 1, Budget: 100}, nil).AnyTimes()
+			mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			s := &Service{
+				UserRepo: mockRepo,
+				rdb:      redis.NewMemory(),
+				cfg:      config.Config{RedisConfig: config.RedisConfig{JobTTL: time.Minute}},
+			}
+			_, err := s.ApplicationProcessor(context.Background(), tt.actor, []models.RequestJob{{Name: "A", Jid: 1, Budget: 100}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplicationProcessor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_jobCacheInvalidation(t *testing.T) {
+	job := models.Jobs{Model: gorm.Model{ID: 4}, Cid: 1, Description: "old"}
+	updated := models.Jobs{Model: gorm.Model{ID: 4}, Cid: 1, Description: "new"}
+	tests := []struct {
+		name   string
+		mutate func(s *Service, mockRepo *repository.MockUserRepo) error
+	}{
+		{
+			name: "update",
+			mutate: func(s *Service, mockRepo *repository.MockUserRepo) error {
+				mockRepo.EXPECT().UpdateJobPosting(gomock.Any(), uint64(4), gomock.Any()).Return(updated, nil).Times(1)
+				_, err := s.UpdateJobPostingService(context.Background(), models.Actor{UserID: 1, Role: models.RoleAdmin}, 4, models.NewJobRequest{Description: "new"})
+				return err
+			},
+		},
+		{
+			name: "close",
+			mutate: func(s *Service, mockRepo *repository.MockUserRepo) error {
+				mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+					return fn(mockRepo)
+				}).Times(1)
+				mockRepo.EXPECT().CloseJobPosting(gomock.Any(), uint64(4), gomock.Any()).Return(true, nil).Times(1)
+				mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+				_, err := s.CloseJobPostingService(context.Background(), models.Actor{UserID: 1, Role: models.RoleAdmin}, 4)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			s := &Service{
+				UserRepo: mockRepo,
+				rdb:      redis.NewMemory(),
+				cfg:      config.Config{RedisConfig: config.RedisConfig{JobTTL: time.Minute}},
+			}
+			// the first read caches the job, the mutation reads it from the cache
+			mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(4)).Return(job, nil).Times(1)
+			_, err := s.GetJobPostingByIDService(context.Background(), 4)
+			if err != nil {
+				t.Fatalf("GetJobPostingByIDService() error = %v", err)
+			}
+			err = tt.mutate(s, mockRepo)
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			// the next read misses the cache and sees the change
+			mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(4)).Return(updated, nil).Times(1)
+			got, err := s.GetJobPostingByIDService(context.Background(), 4)
+			if err != nil {
+				t.Fatalf("GetJobPostingByIDService() error = %v", err)
+			}
+			if got.Description != "new" {
+				t.Errorf("GetJobPostingByIDService() description = %q after %s, want %q", got.Description, tt.name, "new")
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"job-portal-api/internal/models"
+)
+
+const maxLockoutEvents = 500
+
+// RecordLockoutEvent stores a lockout for the admin review endpoint.
+func (s *Service) RecordLockoutEvent(ctx context.Context, event models.LockoutEvent) error {
+	return s.UserRepo.InsertLockoutEvent(ctx, event)
+}
+
+// ListLockoutEventsService returns the latest lockouts, at most limit of them.
+func (s *Service) ListLockoutEventsService(ctx context.Context, limit int) ([]models.LockoutEvent, error) {
+	if limit <= 0 || limit > maxLockoutEvents {
+		limit = maxLockoutEvents
+	}
+	return s.UserRepo.FetchLockoutEvents(ctx, limit)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+)
+
+var (
+	// ErrNotCompanyMember is returned when the actor lacks the company rights an operation needs.
+	ErrNotCompanyMember = errors.New("you do not have access to this company")
+	// ErrLastOwner is returned when a change would leave a company without an owner.
+	ErrLastOwner = errors.New("a company must keep at least one owner")
+	// ErrNotRecruiter is returned when adding a user without the recruiter role to a company.
+	ErrNotRecruiter = errors.New("only recruiters can be added to a company, an admin has to grant the role first")
+)
+
+// authorizeCompany checks that the actor belongs to the company. When roles are given the
+// membership must also hold one of them. Platform admins are allowed everywhere.
+// API keys are checked against the company they were issued for.
+func (s *Service) authorizeCompany(ctx context.Context, actor models.Actor, cid uint64, roles ...string) error {
+	if actor.Role == models.RoleAdmin {
+		return nil
+	}
+	if actor.APIKeyID != 0 {
+		// company keys only reach their own company. Service account keys are not tied to a
+		// company and reach none. Keys never hold a membership role.
+		if len(roles) == 0 && actor.CompanyID != 0 && uint64(actor.CompanyID) == cid {
+			return nil
+		}
+		return ErrNotCompanyMember
+	}
+	member, err := s.UserRepo.FetchCompanyMember(ctx, cid, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if member.ID == 0 {
+		return ErrNotCompanyMember
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return nil
+		}
+	}
+	return ErrNotCompanyMember
+}
+
+func (s *Service) ListCompanyMembersService(ctx context.Context, actor models.Actor, cid uint64) ([]models.CompanyMember, error) {
+	err := s.authorizeCompany(ctx, actor, cid)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.FetchCompanyMembers(ctx, cid)
+}
+
+// AddCompanyMemberService adds a user to the company or changes their rights in it. Only
+// owners can do this. Members need the recruiter platform role to reach the job routes, and
+// only admins grant it, so users without it cannot be added.
+func (s *Service) AddCompanyMemberService(ctx context.Context, actor models.Actor, cid uint64, memberData models.NewMemberRequest) (models.CompanyMember, error) {
+	err := s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(memberData.UserID))
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+
+	if user.Role != models.RoleRecruiter && user.Role != models.RoleAdmin {
+		return models.CompanyMember{}, ErrNotRecruiter
+	}
+
+	if memberData.Role != models.MemberRoleOwner {
+		err = s.ensureAnotherOwner(ctx, cid, memberData.UserID)
+		if err != nil {
+			return models.CompanyMember{}, err
+		}
+	}
+
+	member, err := s.UserRepo.SaveCompanyMember(ctx, models.CompanyMember{
+		CompanyID: uint(cid),
+		UserID:    user.ID,
+		Role:      memberData.Role,
+	})
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+	log.Info().Uint64("company id", cid).Uint("user id", user.ID).Str("role", memberData.Role).Msg("company member saved")
+	return member, nil
+}
+
+// RemoveCompanyMemberService removes a user from the company. Only owners can do this and
+// the last owner cannot be removed.
+func (s *Service) RemoveCompanyMemberService(ctx context.Context, actor models.Actor, cid uint64, uid uint) error {
+	err := s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+	if err != nil {
+		return err
+	}
+	err = s.ensureAnotherOwner(ctx, cid, uid)
+	if err != nil {
+		return err
+	}
+	err = s.UserRepo.DeleteCompanyMember(ctx, cid, uid)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint64("company id", cid).Uint("user id", uid).Msg("company member removed")
+	return nil
+}
+
+// ensureAnotherOwner returns ErrLastOwner when uid is the only owner of the company.
+func (s *Service) ensureAnotherOwner(ctx context.Context, cid uint64, uid uint) error {
+	members, err := s.UserRepo.FetchCompanyMembers(ctx, cid)
+	if err != nil {
+		return err
+	}
+	isOwner := false
+	owners := 0
+	for _, m := range members {
+		if m.Role != models.MemberRoleOwner {
+			continue
+		}
+		owners++
+		if m.UserID == uid {
+			isOwner = true
+		}
+	}
+	if isOwner && owners == 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+)
+
+func TestService_AddCompanyMemberService(t *testing.T) {
+	owner := models.Actor{UserID: 1, Role: models.RoleRecruiter}
+	tests := []struct {
+		name    string
+		actor   models.Actor
+		request models.NewMemberRequest
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name:    "actor is not a member",
+			actor:   owner,
+			request: models.NewMemberRequest{UserID: 2, Role: models.MemberRoleRecruiter},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(5), uint(1)).Return(models.CompanyMember{}, nil).Times(1)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{
+			name:    "actor is only a recruiter",
+			actor:   owner,
+			request: models.NewMemberRequest{UserID: 2, Role: models.MemberRoleRecruiter},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(5), uint(1)).Return(models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleRecruiter}, nil).Times(1)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{
+			name:    "last owner demotes themselves",
+			actor:   owner,
+			request: models.NewMemberRequest{UserID: 1, Role: models.MemberRoleRecruiter},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(5), uint(1)).Return(models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleOwner}, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Model: gorm.Model{ID: 1}, Role: models.RoleRecruiter}, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMembers(gomock.Any(), uint64(5)).Return([]models.CompanyMember{
+					{UserID: 1, Role: models.MemberRoleOwner},
+				}, nil).Times(1)
+			},
+			wantErr: ErrLastOwner,
+		},
+		{
+			name:    "candidates are not promoted",
+			actor:   owner,
+			request: models.NewMemberRequest{UserID: 2, Role: models.MemberRoleRecruiter},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(5), uint(1)).Return(models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleOwner}, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(2)).Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleCandidate}, nil).Times(1)
+			},
+			wantErr: ErrNotRecruiter,
+		},
+		{
+			name:    "recruiter is added",
+			actor:   owner,
+			request: models.NewMemberRequest{UserID: 2, Role: models.MemberRoleRecruiter},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(5), uint(1)).Return(models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleOwner}, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(2)).Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleRecruiter}, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMembers(gomock.Any(), uint64(5)).Return([]models.CompanyMember{
+					{UserID: 1, Role: models.MemberRoleOwner},
+				}, nil).Times(1)
+				mockRepo.EXPECT().SaveCompanyMember(gomock.Any(), models.CompanyMember{CompanyID: 5, UserID: 2, Role: models.MemberRoleRecruiter}).
+					Return(models.CompanyMember{CompanyID: 5, UserID: 2, Role: models.MemberRoleRecruiter}, nil).Times(1)
+			},
+		},
+		{
+			name:    "admins bypass membership",
+			actor:   models.Actor{UserID: 9, Role: models.RoleAdmin},
+			request: models.NewMemberRequest{UserID: 2, Role: models.MemberRoleOwner},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(2)).Return(models.User{}, errors.New("user not found")).Times(1)
+			},
+			wantErr: errors.New("user not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			_, err := s.AddCompanyMemberService(context.Background(), tt.actor, 5, tt.request)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("AddCompanyMemberService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_RemoveCompanyMemberService(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(5), uint(1)).Return(models.CompanyMember{Model: gorm.Model{ID: 1}, Role: models.MemberRoleOwner}, nil).Times(2)
+	mockRepo.EXPECT().FetchCompanyMembers(gomock.Any(), uint64(5)).Return([]models.CompanyMember{
+		{UserID: 1, Role: models.MemberRoleOwner},
+		{UserID: 2, Role: models.MemberRoleRecruiter},
+	}, nil).Times(2)
+	mockRepo.EXPECT().DeleteCompanyMember(gomock.Any(), uint64(5), uint(2)).Return(nil).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	actor := models.Actor{UserID: 1, Role: models.RoleRecruiter}
+
+	err := s.RemoveCompanyMemberService(context.Background(), actor, 5, 1)
+	if !errors.Is(err, ErrLastOwner) {
+		t.Errorf("RemoveCompanyMemberService() removing last owner error = %v, want %v", err, ErrLastOwner)
+	}
+	err = s.RemoveCompanyMemberService(context.Background(), actor, 5, 2)
+	if err != nil {
+		t.Errorf("RemoveCompanyMemberService() error = %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/i18n"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+)
+
+const (
+	defaultNotificationPage = 20
+	maxNotificationPage     = 100
+	// jobMatchPage is how many job preferences are matched against a new job at a time.
+	jobMatchPage = 500
+)
+
+// OutboxJobMatch is the payload of a job match outbox message.
+type OutboxJobMatch struct {
+	JobID uint `json:"jobId"`
+}
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrCandidateNotFound    = errors.New("candidate not found")
+)
+
+// ListNotificationsService returns a page of the user's notifications, newest first, with
+// the number of unread ones.
+func (s *Service) ListNotificationsService(ctx context.Context, uid uint, query models.NotificationQuery) (models.NotificationPage, error) {
+	query.UserID = uid
+	if query.Limit <= 0 {
+		query.Limit = defaultNotificationPage
+	}
+	if query.Limit > maxNotificationPage {
+		query.Limit = maxNotificationPage
+	}
+	limit := query.Limit
+	// one extra tells whether there is another page
+	query.Limit++
+	notifications, err := s.UserRepo.FetchNotifications(ctx, query)
+	if err != nil {
+		return models.NotificationPage{}, err
+	}
+	unread, err := s.UserRepo.CountUnreadNotifications(ctx, uid)
+	if err != nil {
+		return models.NotificationPage{}, err
+	}
+
+	page := models.NotificationPage{Notifications: notifications, UnreadCount: unread}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		page.NextBefore = page.Notifications[limit-1].ID
+	}
+	if page.Notifications == nil {
+		page.Notifications = []models.Notification{}
+	}
+	return page, nil
+}
+
+func (s *Service) UnreadNotificationCountService(ctx context.Context, uid uint) (int64, error) {
+	return s.UserRepo.CountUnreadNotifications(ctx, uid)
+}
+
+func (s *Service) MarkNotificationReadService(ctx context.Context, uid uint, id uint) error {
+	found, err := s.UserRepo.MarkNotificationRead(ctx, uid, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllNotificationsReadService marks every notification of the user read and returns
+// how many were unread.
+func (s *Service) MarkAllNotificationsReadService(ctx context.Context, uid uint) (int64, error) {
+	return s.UserRepo.MarkAllNotificationsRead(ctx, uid, time.Now())
+}
+
+func (s *Service) GetJobPreferenceService(ctx context.Context, uid uint) (models.JobPreference, error) {
+	return s.UserRepo.FetchJobPreference(ctx, uid)
+}
+
+// SaveJobPreferenceService stores what the user is looking for. New jobs that match it are
+// sent to their notifications.
+func (s *Service) SaveJobPreferenceService(ctx context.Context, uid uint, criteria models.RequestJob) (models.JobPreference, error) {
+	// these describe a single application, not a preference
+	criteria.Jid = 0
+	criteria.CandidateID = 0
+	return s.UserRepo.SaveJobPreference(ctx, models.JobPreference{UserID: uid, Criteria: criteria})
+}
+
+// InviteToInterviewService sends an interview invitation for the job to a candidate's
+// notifications. The actor must be a member of the company that owns the job.
+func (s *Service) InviteToInterviewService(ctx context.Context, actor models.Actor, jid uint64, invite models.InterviewInviteRequest) error {
+	job, err := s.fetchJob(ctx, jid)
+	if err != nil {
+		return err
+	}
+	if job.ID == 0 {
+		return ErrJobNotFound
+	}
+	err = s.authorizeCompany(ctx, actor, uint64(job.Cid))
+	if err != nil {
+		return err
+	}
+	if job.ClosedAt != nil {
+		return ErrJobClosed
+	}
+	candidate, err := s.UserRepo.GetUserByID(ctx, uint64(invite.CandidateID))
+	if err != nil || candidate.ID == 0 || candidate.Role != models.RoleCandidate {
+		return ErrCandidateNotFound
+	}
+
+	locale := notificationLocale(candidate)
+	body := fmt.Sprintf(i18n.T(locale, "You are invited to interview for %q on %s."), job.Description, invite.ScheduledAt.UTC().Format(time.RFC1123))
+	if invite.Message != "" {
+		body += "\n\n" + invite.Message
+	}
+	return s.UserRepo.InsertNotifications(ctx, models.Notification{
+		UserID: candidate.ID,
+		Type:   models.NotificationInterviewInvite,
+		Title:  i18n.T(locale, "Interview invitation"),
+		Body:   body,
+		Data: map[string]string{
+			"jobId":       fmt.Sprint(job.ID),
+			"companyId":   fmt.Sprint(job.Cid),
+			"scheduledAt": invite.ScheduledAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
+
+func newJobMatchMessage(jid uint) (models.OutboxMessage, error) {
+	payload, err := json.Marshal(OutboxJobMatch{JobID: jid})
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return models.OutboxMessage{
+		Kind:        models.OutboxKindJobMatch,
+		Destination: fmt.Sprintf("job %d", jid),
+		Summary:     "job preference matching",
+		Payload:     payload,
+	}, nil
+}
+
+// notifyJobMatches tells every candidate whose job preference passes the job about it. The
+// outbox dispatcher runs it for every new job, going through the preferences a page at a
+// time. A retry after a failure can notify some candidates twice.
+func notifyJobMatches(ctx context.Context, repo repository.UserRepo, jid uint) error {
+	job, err := repo.FetchJobPostingByID(ctx, uint64(jid))
+	if err != nil {
+		return err
+	}
+	if job.ID == 0 {
+		return permanentError{ErrJobNotFound}
+	}
+	// nobody can apply to a job closed in the meantime
+	if job.ClosedAt != nil {
+		return nil
+	}
+	var after uint
+	for {
+		preferences, err := repo.FetchJobPreferences(ctx, after, jobMatchPage)
+		if err != nil {
+			return err
+		}
+		var matched []uint
+		for _, preference := range preferences {
+			if evaluateJobApplication(preference.Criteria, job).Eligible {
+				matched = append(matched, preference.UserID)
+			}
+		}
+		var users []models.User
+		if len(matched) > 0 {
+			users, err = repo.FetchUsersByIDs(ctx, matched)
+			if err != nil {
+				return err
+			}
+		}
+		var notifications []models.Notification
+		for _, user := range users {
+			notifications = append(notifications, models.Notification{
+				UserID: user.ID,
+				Type:   models.NotificationJobMatch,
+				Title:  i18n.T(notificationLocale(user), "New job matching your preferences"),
+				Body:   job.Description,
+				Data:   map[string]string{"jobId": fmt.Sprint(job.ID), "companyId": fmt.Sprint(job.Cid)},
+			})
+		}
+		if len(notifications) > 0 {
+			err = repo.InsertNotifications(ctx, notifications...)
+			if err != nil {
+				return err
+			}
+		}
+		if len(preferences) < jobMatchPage {
+			return nil
+		}
+		after = preferences[len(preferences)-1].UserID
+	}
+}
+
+// applicationCandidates returns the locale of the notifications of every candidate named
+// by the applications. Recruiters pass the ids, so users that are not candidates are left
+// out and not notified.
+func (s *Service) applicationCandidates(ctx context.Context, outcomes []applicationOutcome) map[uint]string {
+	seen := map[uint]bool{}
+	var ids []uint
+	for _, o := range outcomes {
+		if id := o.application.CandidateID; id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	users, err := s.UserRepo.FetchUsersByIDs(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check the candidates of the applications")
+		return nil
+	}
+	locales := make(map[uint]string, len(users))
+	for _, user := range users {
+		if user.Role == models.RoleCandidate {
+			locales[user.ID] = notificationLocale(user)
+		}
+	}
+	if len(locales) < len(ids) {
+		log.Warn().Int("unknown", len(ids)-len(locales)).Msg("applications name users that are not candidates")
+	}
+	return locales
+}
+
+// applicationNotification tells the candidate behind an application how it was screened.
+func applicationNotification(o applicationOutcome, locale string) models.Notification {
+	n := models.Notification{
+		UserID: o.application.CandidateID,
+		Type:   models.NotificationApplicationStatus,
+		Title:  i18n.T(locale, "Application update"),
+		Data: map[string]string{
+			"jobId":     fmt.Sprint(o.application.Jid),
+			"companyId": fmt.Sprint(o.cid),
+			"status":    "rejected",
+		},
+	}
+	if o.accepted {
+		n.Data["status"] = "accepted"
+		n.Body = fmt.Sprintf(i18n.T(locale, "Your application for %q has been shortlisted."), o.description)
+	} else {
+		n.Body = fmt.Sprintf(i18n.T(locale, "Your application for %q was not taken forward."), o.description)
+	}
+	return n
+}
+
+// notificationLocale is the locale of the notifications of user. They are written when
+// someone else acts, so the request cannot tell it: it is the locale the user saved, or
+// else the default.
+func notificationLocale(user models.User) string {
+	if locale, ok := i18n.Normalize(user.Locale); ok {
+		return locale
+	}
+	return i18n.Default
+}
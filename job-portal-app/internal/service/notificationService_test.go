@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_ListNotificationsService(t *testing.T) {
+	stored := []models.Notification{{ID: 9}, {ID: 8}, {ID: 7}}
+	tests := []struct {
+		name           string
+		query          models.NotificationQuery
+		wantFetchLimit int
+		fetched        []models.Notification
+		wantIDs        []uint
+		wantNextBefore uint
+	}{
+		{
+			name:           "default page size",
+			wantFetchLimit: defaultNotificationPage + 1,
+			fetched:        stored,
+			wantIDs:        []uint{9, 8, 7},
+		},
+		{
+			name:           "page size is capped",
+			query:          models.NotificationQuery{Limit: 1000},
+			wantFetchLimit: maxNotificationPage + 1,
+			wantIDs:        []uint{},
+		},
+		{
+			name:           "more pages",
+			query:          models.NotificationQuery{Limit: 2, Before: 10},
+			wantFetchLimit: 3,
+			fetched:        stored,
+			wantIDs:        []uint{9, 8},
+			wantNextBefore: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().FetchNotifications(gomock.Any(), models.NotificationQuery{UserID: 2, Before: tt.query.Before, Limit: tt.wantFetchLimit}).Return(tt.fetched, nil).Times(1)
+			mockRepo.EXPECT().CountUnreadNotifications(gomock.Any(), uint(2)).Return(int64(4), nil).Times(1)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.ListNotificationsService(context.Background(), 2, tt.query)
+			if err != nil {
+				t.Fatalf("ListNotificationsService() error = %v", err)
+			}
+			var ids []uint
+			for _, n := range got.Notifications {
+				ids = append(ids, n.ID)
+			}
+			if len(ids) != len(tt.wantIDs) || got.Notifications == nil {
+				t.Fatalf("ListNotificationsService() got %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ListNotificationsService() got %v, want %v", ids, tt.wantIDs)
+				}
+			}
+			if got.NextBefore != tt.wantNextBefore || got.UnreadCount != 4 {
+				t.Errorf("ListNotificationsService() nextBefore = %d, unreadCount = %d", got.NextBefore, got.UnreadCount)
+			}
+		})
+	}
+}
+
+func TestService_MarkNotificationReadService(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().MarkNotificationRead(gomock.Any(), uint(2), uint(7), gomock.Any()).Return(false, nil).Times(1)
+	mockRepo.EXPECT().MarkNotificationRead(gomock.Any(), uint(2), uint(8), gomock.Any()).Return(true, nil).Times(1)
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+
+	err := s.MarkNotificationReadService(context.Background(), 2, 7)
+	if !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("MarkNotificationReadService() error = %v, want %v", err, ErrNotificationNotFound)
+	}
+	err = s.MarkNotificationReadService(context.Background(), 2, 8)
+	if err != nil {
+		t.Errorf("MarkNotificationReadService() error = %v", err)
+	}
+}
+
+func TestService_InviteToInterviewService(t *testing.T) {
+	closedAt := time.Now()
+	job := models.Jobs{Model: gorm.Model{ID: 9}, Cid: 1, Description: "Backend engineer"}
+	closed := job
+	closed.ClosedAt = &closedAt
+	invite := models.InterviewInviteRequest{
+		CandidateID: 4,
+		ScheduledAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		Message:     "Bring your portfolio.",
+	}
+	tests := []struct {
+		name    string
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name: "not a member of the company",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(job, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(models.CompanyMember{}, nil).Times(1)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{
+			name: "closed job",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(closed, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(webhookOwner, nil).Times(1)
+			},
+			wantErr: ErrJobClosed,
+		},
+		{
+			name: "unknown candidate",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(job, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(webhookOwner, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(4)).Return(models.User{}, nil).Times(1)
+			},
+			wantErr: ErrCandidateNotFound,
+		},
+		{
+			name: "not a candidate",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(job, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(webhookOwner, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(4)).Return(models.User{Model: gorm.Model{ID: 4}, Role: models.RoleRecruiter}, nil).Times(1)
+			},
+			wantErr: ErrCandidateNotFound,
+		},
+		{
+			name: "success",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(9)).Return(job, nil).Times(1)
+				mockRepo.EXPECT().FetchCompanyMember(gomock.Any(), uint64(1), uint(2)).Return(webhookOwner, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(4)).Return(models.User{Model: gorm.Model{ID: 4}, Role: models.RoleCandidate, Locale: "hi"}, nil).Times(1)
+				mockRepo.EXPECT().InsertNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, notifications ...models.Notification) error {
+					n := notifications[0]
+					if len(notifications) != 1 || n.UserID != 4 || n.Type != models.NotificationInterviewInvite || n.Title != "साक्षात्कार का निमंत्रण" {
+						t.Errorf("InviteToInterviewService() saved %+v", notifications)
+					}
+					if n.Data["jobId"] != "9" || n.Data["scheduledAt"] != "2024-03-01T10:00:00Z" || !strings.HasSuffix(n.Body, invite.Message) {
+						t.Errorf("InviteToInterviewService() saved %+v", n)
+					}
+					return nil
+				}).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			err := s.InviteToInterviewService(context.Background(), models.Actor{UserID: 2, Role: models.RoleRecruiter}, 9, invite)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("InviteToInterviewService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_notifyJobMatches(t *testing.T) {
+	job := models.Jobs{
+		Model:            gorm.Model{ID: 3},
+		Cid:              1,
+		MaxNP:            30,
+		Budget:           600000,
+		Locations:        []models.Locations{{Model: gorm.Model{ID: 1}}},
+		WorkModes:        []models.WorkModes{{Model: gorm.Model{ID: 1}}},
+		MaxExp:           7,
+		Qualifications:   []models.Qualifications{{Model: gorm.Model{ID: 1}}},
+		Shifts:           []models.Shifts{{Model: gorm.Model{ID: 1}}},
+		JobTypes:         []models.JobTypes{{Model: gorm.Model{ID: 1}}},
+		Description:      "Backend engineer",
+		TechnologyStacks: []models.TechnologyStacks{{Model: gorm.Model{ID: 1}}},
+	}
+	matching := models.RequestJob{
+		NoticePeriod:     15,
+		Budget:           500000,
+		LocationsIDs:     []uint{1},
+		WorkModeIDs:      []uint{1},
+		Experience:       3,
+		QualificationIDs: []uint{1},
+		ShiftIDs:         []uint{1},
+		JobTypeIDs:       []uint{1},
+	}
+	tooExpensive := matching
+	tooExpensive.Budget = 700000
+
+	closedAt := time.Now()
+	closed := job
+	closed.ClosedAt = &closedAt
+	// a full first page, with one match, and a second page with another
+	firstPage := []models.JobPreference{{UserID: 1, Criteria: matching}}
+	for uid := uint(2); uid <= jobMatchPage; uid++ {
+		firstPage = append(firstPage, models.JobPreference{UserID: uid, Criteria: tooExpensive})
+	}
+	secondPage := []models.JobPreference{{UserID: jobMatchPage + 1, Criteria: matching}}
+
+	tests := []struct {
+		name      string
+		setup     func(mockRepo *repository.MockUserRepo, notified *[]uint)
+		wantUsers []uint
+		wantErr   bool
+	}{
+		{
+			name: "matches on every page",
+			setup: func(mockRepo *repository.MockUserRepo, notified *[]uint) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(job, nil).Times(1)
+				mockRepo.EXPECT().FetchJobPreferences(gomock.Any(), uint(0), jobMatchPage).Return(firstPage, nil).Times(1)
+				mockRepo.EXPECT().FetchJobPreferences(gomock.Any(), uint(jobMatchPage), jobMatchPage).Return(secondPage, nil).Times(1)
+				mockRepo.EXPECT().FetchUsersByIDs(gomock.Any(), []uint{1}).Return([]models.User{{Model: gorm.Model{ID: 1}, Locale: "hi"}}, nil).Times(1)
+				mockRepo.EXPECT().FetchUsersByIDs(gomock.Any(), []uint{jobMatchPage + 1}).Return([]models.User{{Model: gorm.Model{ID: jobMatchPage + 1}}}, nil).Times(1)
+				titles := map[uint]string{1: "आपकी प्राथमिकताओं से मेल खाती नई नौकरी", jobMatchPage + 1: "New job matching your preferences"}
+				mockRepo.EXPECT().InsertNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, notifications ...models.Notification) error {
+					for _, n := range notifications {
+						if n.Type != models.NotificationJobMatch || n.Data["jobId"] != "3" || n.Title != titles[n.UserID] {
+							t.Errorf("notifyJobMatches() saved %+v", n)
+						}
+						*notified = append(*notified, n.UserID)
+					}
+					return nil
+				}).Times(2)
+			},
+			wantUsers: []uint{1, jobMatchPage + 1},
+		},
+		{
+			name: "closed job",
+			setup: func(mockRepo *repository.MockUserRepo, notified *[]uint) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(closed, nil).Times(1)
+			},
+		},
+		{
+			name: "deleted job",
+			setup: func(mockRepo *repository.MockUserRepo, notified *[]uint) {
+				mockRepo.EXPECT().FetchJobPostingByID(gomock.Any(), uint64(3)).Return(models.Jobs{}, nil).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			var notified []uint
+			tt.setup(mockRepo, &notified)
+			err := notifyJobMatches(context.Background(), mockRepo, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("notifyJobMatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(notified, tt.wantUsers) {
+				t.Errorf("notifyJobMatches() notified %v, want %v", notified, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestService_reportApplicationOutcomes_notifications(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().FetchWebhookEndpoints(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+	// user 6 is not a candidate
+	mockRepo.EXPECT().FetchUsersByIDs(gomock.Any(), []uint{4, 5, 6}).Return([]models.User{
+		{Model: gorm.Model{ID: 4}, Role: models.RoleCandidate, Locale: "hi"},
+		{Model: gorm.Model{ID: 5}, Role: models.RoleCandidate},
+		{Model: gorm.Model{ID: 6}, Role: models.RoleRecruiter},
+	}, nil).Times(1)
+	mockRepo.EXPECT().InsertNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, notifications ...models.Notification) error {
+		if len(notifications) != 3 {
+			t.Fatalf("reportApplicationOutcomes() saved %+v", notifications)
+		}
+		// each in the language its candidate saved
+		if notifications[0].UserID != 4 || notifications[0].Data["status"] != "accepted" || notifications[0].Title != "आवेदन की जानकारी" {
+			t.Errorf("reportApplicationOutcomes() saved %+v", notifications[0])
+		}
+		if notifications[1].UserID != 5 || notifications[1].Data["status"] != "rejected" || notifications[1].Title != "Application update" {
+			t.Errorf("reportApplicationOutcomes() saved %+v", notifications[1])
+		}
+		return nil
+	}).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	s.(*Service).reportApplicationOutcomes(context.Background(), []applicationOutcome{
+		{application: models.RequestJob{Name: "a", Jid: 9, CandidateID: 4}, cid: 1, accepted: true},
+		{application: models.RequestJob{Name: "b", Jid: 9, CandidateID: 5}, cid: 1, accepted: false},
+		{application: models.RequestJob{Name: "c", Jid: 9}, cid: 1, accepted: true},
+		{application: models.RequestJob{Name: "d", Jid: 9, CandidateID: 6}, cid: 1, accepted: true},
+		{application: models.RequestJob{Name: "e", Jid: 9, CandidateID: 4}, cid: 1, accepted: false},
+	})
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+)
+
+const (
+	// oidcDiscoveryTTL is how long a provider's configuration and keys are cached. Keys are
+	// also refetched when a token is signed with a key id we have not seen yet.
+	oidcDiscoveryTTL = time.Hour
+	// oidcMinRefreshInterval stops tokens with made up key ids from making us refetch keys
+	// on every request.
+	oidcMinRefreshInterval = time.Minute
+	oidcHTTPTimeout        = 10 * time.Second
+	// oidcMaxResponseSize caps what we read from a provider.
+	oidcMaxResponseSize = 1 << 20
+)
+
+// oidcProvider talks to one OpenID Connect provider. Its discovery document and signing keys
+// are fetched on first use and cached.
+type oidcProvider struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	client       *http.Client
+
+	mu          sync.Mutex
+	discovery   oidcDiscovery
+	keys        map[string]*rsa.PublicKey
+	refreshedAt time.Time
+}
+
+// oidcDiscovery is the part of the provider's /.well-known/openid-configuration we use.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcIdentity is what a verified ID token tells us about the user.
+type oidcIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// oidcIDTokenClaims are the ID token claims we check. email_verified is sent as a string by
+// some providers, hence the custom type.
+type oidcIDTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string    `json:"azp"`
+	Nonce           string    `json:"nonce"`
+	Email           string    `json:"email"`
+	EmailVerified   flexiBool `json:"email_verified"`
+}
+
+type flexiBool bool
+
+func (b *flexiBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+// newOIDCProviders parses the configured providers. baseURL is used for the default
+// redirect URL.
+func newOIDCProviders(cfg config.OIDCConfig, baseURL string) (map[string]*oidcProvider, error) {
+	providers := make(map[string]*oidcProvider)
+	if cfg.Providers == "" {
+		return providers, nil
+	}
+
+	var configured []config.OIDCProvider
+	err := json.Unmarshal([]byte(cfg.Providers), &configured)
+	if err != nil {
+		return nil, fmt.Errorf("parsing OIDC_PROVIDERS: %w", err)
+	}
+	client := &http.Client{Timeout: oidcHTTPTimeout}
+	for _, c := range configured {
+		if c.Name == "" || c.Issuer == "" || c.ClientID == "" {
+			return nil, errors.New("every OIDC provider needs a name, issuer and client_id")
+		}
+		if _, ok := providers[c.Name]; ok {
+			return nil, fmt.Errorf("OIDC provider %q is configured twice", c.Name)
+		}
+		redirectURL := c.RedirectURL
+		if redirectURL == "" {
+			if baseURL == "" {
+				return nil, fmt.Errorf("OIDC provider %q needs a redirect_url or APP_BASE_URL", c.Name)
+			}
+			redirectURL = strings.TrimRight(baseURL, "/") + "/api/oidc/" + url.PathEscape(c.Name) + "/callback"
+		}
+		scopes := c.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+		providers[c.Name] = &oidcProvider{
+			name:         c.Name,
+			issuer:       strings.TrimRight(c.Issuer, "/"),
+			clientID:     c.ClientID,
+			clientSecret: c.ClientSecret,
+			redirectURL:  redirectURL,
+			scopes:       scopes,
+			client:       client,
+		}
+	}
+	return providers, nil
+}
+
+// pkceChallenge is the S256 code challenge for verifier (RFC 7636).
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authCodeURL is where the user is sent to sign in.
+func (p *oidcProvider) authCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.clientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", strings.Join(p.scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", pkceChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// exchange trades the authorization code for tokens and returns the verified identity.
+func (p *oidcProvider) exchange(ctx context.Context, code, verifier, nonce string) (oidcIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return oidcIdentity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.clientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oidcIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	err = p.do(req, &tokens)
+	if err != nil {
+		return oidcIdentity{}, fmt.Errorf("exchanging authorization code: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return oidcIdentity{}, errors.New("provider did not return an id token")
+	}
+	return p.verifyIDToken(ctx, tokens.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, raw, nonce string) (oidcIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return oidcIdentity{}, err
+	}
+
+	var claims oidcIDTokenClaims
+	_, err = jwt.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return oidcIdentity{}, fmt.Errorf("invalid id token: %w", err)
+	}
+	// jwt only checks exp when it is present, ID tokens must always have one
+	if claims.ExpiresAt == nil {
+		return oidcIdentity{}, errors.New("invalid id token: missing expiry")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.clientID {
+		return oidcIdentity{}, errors.New("invalid id token: issued to another client")
+	}
+	if claims.Nonce != nonce {
+		return oidcIdentity{}, errors.New("invalid id token: nonce does not match")
+	}
+	if claims.Subject == "" {
+		return oidcIdentity{}, errors.New("invalid id token: missing subject")
+	}
+	return oidcIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+	}, nil
+}
+
+// discover returns the provider's configuration, fetching it when the cache is stale.
+func (p *oidcProvider) discover(ctx context.Context) (oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Since(p.refreshedAt) < oidcDiscoveryTTL {
+		return p.discovery, nil
+	}
+	err := p.refresh(ctx)
+	if err != nil {
+		return oidcDiscovery{}, err
+	}
+	return p.discovery, nil
+}
+
+// key returns the provider's signing key with the given id, refetching the keys once if
+// it is unknown so that key rotations at the provider are picked up.
+func (p *oidcProvider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key, ok := p.keys[kid]
+	if ok && time.Since(p.refreshedAt) < oidcDiscoveryTTL {
+		return key, nil
+	}
+	if !ok && time.Since(p.refreshedAt) < oidcMinRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	err := p.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, ok = p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// refresh fetches the discovery document and the signing keys. p.mu must be held.
+func (p *oidcProvider) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return err
+	}
+	var d oidcDiscovery
+	err = p.do(req, &d)
+	if err != nil {
+		return fmt.Errorf("fetching provider configuration: %w", err)
+	}
+	// the issuer in the document must be the one we were configured with (OIDC Discovery 4.3)
+	if strings.TrimRight(d.Issuer, "/") != p.issuer {
+		return fmt.Errorf("provider reports issuer %q, expected %q", d.Issuer, p.issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return errors.New("provider configuration is incomplete")
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, d.JWKSURI, nil)
+	if err != nil {
+		return err
+	}
+	var set auth.JWKS
+	err = p.do(req, &set)
+	if err != nil {
+		return fmt.Errorf("fetching provider keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			return err
+		}
+		keys[k.Kid] = key
+	}
+
+	p.discovery = d
+	p.keys = keys
+	p.refreshedAt = time.Now()
+	return nil
+}
+
+// do sends the request and decodes a JSON response into v.
+func (p *oidcProvider) do(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("provider responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
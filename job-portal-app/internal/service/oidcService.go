@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+)
+
+// oidcLoginTTL is how long the user has to sign in at the provider.
+const oidcLoginTTL = 10 * time.Minute
+
+var (
+	ErrUnknownOIDCProvider  = errors.New("unknown identity provider")
+	ErrInvalidOIDCState     = errors.New("invalid or expired login request, please try again")
+	ErrOIDCLoginFailed      = errors.New("could not sign in with the identity provider")
+	ErrOIDCEmailNotVerified = errors.New("the identity provider did not confirm your email address")
+)
+
+// StartOIDCLoginService starts an authorization code login with PKCE and returns the URL
+// the user has to be sent to. The state, nonce and code verifier are kept until the callback.
+func (s *Service) StartOIDCLoginService(ctx context.Context, provider string) (string, error) {
+	p, ok := s.oidc[provider]
+	if !ok {
+		return "", ErrUnknownOIDCProvider
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := p.authCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Error().Err(err).Str("provider", provider).Msg("failed to reach identity provider")
+		return "", ErrOIDCLoginFailed
+	}
+	err = s.UserRepo.InsertOIDCLoginState(ctx, models.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcLoginTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// OIDCCallbackService completes the login when the provider redirects back. The user is found
+// by the linked identity, else by the verified email, else created; then they are logged in
+// the same way as with a password.
+func (s *Service) OIDCCallbackService(ctx context.Context, provider, code, state string) (models.TokenPair, error) {
+	p, ok := s.oidc[provider]
+	if !ok {
+		return models.TokenPair{}, ErrUnknownOIDCProvider
+	}
+
+	login, err := s.UserRepo.ConsumeOIDCLoginState(ctx, hashToken(state))
+	if err != nil {
+		log.Info().Err(err).Msg("unknown oidc login state")
+		return models.TokenPair{}, ErrInvalidOIDCState
+	}
+	if login.Provider != provider || time.Now().After(login.ExpiresAt) {
+		return models.TokenPair{}, ErrInvalidOIDCState
+	}
+
+	identity, err := p.exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", provider).Msg("oidc code exchange failed")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginFailed, Details: map[string]string{"method": "oidc", "provider": provider, "reason": "code exchange failed"}})
+		return models.TokenPair{}, ErrOIDCLoginFailed
+	}
+
+	user, err := s.userForIdentity(ctx, provider, identity)
+	if err != nil {
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginFailed, Details: map[string]string{"method": "oidc", "provider": provider, "subject": identity.Subject, "reason": err.Error()}})
+		return models.TokenPair{}, err
+	}
+	if user.TwoFactorEnabled {
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditTwoFactorChallenged, TargetUserID: uintRef(user.ID), Details: map[string]string{"method": "oidc", "provider": provider}})
+		return s.newChallengeToken(user)
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginSucceeded, TargetUserID: uintRef(user.ID), Details: map[string]string{"method": "oidc", "provider": provider}})
+	return s.startSession(ctx, user)
+}
+
+func (s *Service) userForIdentity(ctx context.Context, provider string, identity oidcIdentity) (models.User, error) {
+	linked, found, err := s.UserRepo.FetchExternalIdentity(ctx, provider, identity.Subject)
+	if err != nil {
+		return models.User{}, err
+	}
+	if found {
+		return s.UserRepo.GetUserByID(ctx, uint64(linked.UserID))
+	}
+
+	// without a verified email anyone could claim an existing account
+	if !identity.EmailVerified || identity.Email == "" {
+		return models.User{}, ErrOIDCEmailNotVerified
+	}
+	email := normalizeEmail(identity.Email)
+	link := models.ExternalIdentity{Provider: provider, Subject: identity.Subject, Email: email}
+
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		now := time.Now()
+		user, err = s.UserRepo.InsertUserWithIdentity(ctx, models.User{
+			Username:        email,
+			Email:           email,
+			Role:            models.RoleCandidate,
+			EmailVerifiedAt: &now,
+		}, link)
+		if err != nil {
+			return models.User{}, err
+		}
+		log.Info().Uint("user id", user.ID).Str("provider", provider).Msg("created user from identity provider")
+		return user, nil
+	}
+
+	if user.EmailVerifiedAt == nil {
+		user, err = s.claimUnverifiedAccount(ctx, user)
+		if err != nil {
+			return models.User{}, err
+		}
+	}
+	link.UserID = user.ID
+	err = s.UserRepo.InsertExternalIdentity(ctx, link)
+	if err != nil {
+		return models.User{}, err
+	}
+	log.Info().Uint("user id", user.ID).Str("provider", provider).Msg("linked identity provider account")
+	return user, nil
+}
+
+// claimUnverifiedAccount hands an account registered with an unverified email over to the
+// owner of the email. Whoever registered it may not own the address, so their password,
+// 2FA and sessions are dropped.
+func (s *Service) claimUnverifiedAccount(ctx context.Context, user models.User) (models.User, error) {
+	err := s.UserRepo.UpdatePassword(ctx, user.Email, "")
+	if err != nil {
+		return models.User{}, err
+	}
+	if user.TwoFactorEnabled {
+		err = s.UserRepo.DisableTOTP(ctx, user.ID)
+		if err != nil {
+			return models.User{}, err
+		}
+		user.TwoFactorEnabled = false
+	}
+	err = s.UserRepo.RevokeUserSessions(ctx, user.ID, "")
+	if err != nil {
+		return models.User{}, err
+	}
+	err = s.UserRepo.MarkEmailVerified(ctx, user.ID)
+	if err != nil {
+		return models.User{}, err
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return user, nil
+}
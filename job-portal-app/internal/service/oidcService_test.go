@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// stubOIDCProvider is a minimal OpenID provider. It hands out an ID token with idClaims for
+// code, provided the PKCE verifier matches challenge.
+type stubOIDCProvider struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	code      string
+	challenge string
+	idClaims  jwt.MapClaims
+}
+
+func newStubOIDCProvider(t *testing.T) *stubOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.NewKeySet("idp-key", auth.SigningKey{ID: "idp-key", Private: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &stubOIDCProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		if clientID != "portal" || secret != "portal-secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code") != p.code ||
+			r.PostFormValue("redirect_uri") != "http://portal.test/callback" ||
+			pkceChallenge(r.PostFormValue("code_verifier")) != p.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.idClaims)
+		token.Header["kid"] = "idp-key"
+		idToken, err := token.SignedString(p.key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *stubOIDCProvider) config() config.Config {
+	return config.Config{OIDCConfig: config.OIDCConfig{Providers: fmt.Sprintf(
+		`[{"name":"stub","issuer":%q,"client_id":"portal","client_secret":"portal-secret","redirect_url":"http://portal.test/callback"}]`, p.URL,
+	)}}
+}
+
+func TestService_StartOIDCLoginService(t *testing.T) {
+	stub := newStubOIDCProvider(t)
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	var stored models.OIDCLoginState
+	mockRepo.EXPECT().InsertOIDCLoginState(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, state models.OIDCLoginState) error {
+		stored = state
+		return nil
+	}).Times(1)
+
+	s, err := NewService(mockRepo, &auth.Auth{}, nil, stub.config())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	_, err = s.StartOIDCLoginService(context.Background(), "unknown")
+	if !errors.Is(err, ErrUnknownOIDCProvider) {
+		t.Errorf("StartOIDCLoginService() error = %v, want %v", err, ErrUnknownOIDCProvider)
+	}
+
+	got, err := s.StartOIDCLoginService(context.Background(), "stub")
+	if err != nil {
+		t.Fatalf("StartOIDCLoginService() error = %v", err)
+	}
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("client_id") != "portal" || q.Get("response_type") != "code" ||
+		q.Get("redirect_uri") != "http://portal.test/callback" || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("StartOIDCLoginService() url = %v", got)
+	}
+	if stored.Provider != "stub" || stored.StateHash != hashToken(q.Get("state")) || stored.Nonce != q.Get("nonce") {
+		t.Errorf("StartOIDCLoginService() stored state %+v does not match url %v", stored, got)
+	}
+	if pkceChallenge(stored.CodeVerifier) != q.Get("code_challenge") {
+		t.Errorf("StartOIDCLoginService() code challenge does not match the stored verifier")
+	}
+}
+
+func TestService_OIDCCallbackService(t *testing.T) {
+	stub := newStubOIDCProvider(t)
+	stub.code = "auth-code"
+	stub.challenge = pkceChallenge("verifier")
+
+	login := models.OIDCLoginState{
+		StateHash:    hashToken("state"),
+		Provider:     "stub",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	idClaims := func(emailVerified bool, nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            stub.URL,
+			"aud":            "portal",
+			"sub":            "idp-user-1",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"nonce":          nonce,
+			"email":          "Jane@Example.com",
+			"email_verified": emailVerified,
+		}
+	}
+	user := models.User{Email: "jane@example.com", Role: models.RoleCandidate}
+	user.ID = 7
+
+	tests := []struct {
+		name     string
+		code     string
+		idClaims jwt.MapClaims
+		setup    func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication)
+		wantErr  error
+	}{
+		{
+			name:     "unknown state",
+			code:     "auth-code",
+			idClaims: idClaims(true, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(models.OIDCLoginState{}, errors.New("login state not found")).Times(1)
+			},
+			wantErr: ErrInvalidOIDCState,
+		},
+		{
+			name:     "expired state",
+			code:     "auth-code",
+			idClaims: idClaims(true, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				expired := login
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(expired, nil).Times(1)
+			},
+			wantErr: ErrInvalidOIDCState,
+		},
+		{
+			name:     "code rejected by the provider",
+			code:     "stolen-code",
+			idClaims: idClaims(true, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(login, nil).Times(1)
+			},
+			wantErr: ErrOIDCLoginFailed,
+		},
+		{
+			name:     "id token with another nonce",
+			code:     "auth-code",
+			idClaims: idClaims(true, "replayed-nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(login, nil).Times(1)
+			},
+			wantErr: ErrOIDCLoginFailed,
+		},
+		{
+			name:     "unverified email",
+			code:     "auth-code",
+			idClaims: idClaims(false, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(login, nil).Times(1)
+				mockRepo.EXPECT().FetchExternalIdentity(gomock.Any(), "stub", "idp-user-1").Return(models.ExternalIdentity{}, false, nil).Times(1)
+			},
+			wantErr: ErrOIDCEmailNotVerified,
+		},
+		{
+			name:     "already linked identity",
+			code:     "auth-code",
+			idClaims: idClaims(false, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(login, nil).Times(1)
+				mockRepo.EXPECT().FetchExternalIdentity(gomock.Any(), "stub", "idp-user-1").Return(models.ExternalIdentity{UserID: 7}, true, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(7)).Return(user, nil).Times(1)
+				mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).Return("access-token", nil).Times(1)
+				mockRepo.EXPECT().InsertSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name:     "new user is created",
+			code:     "auth-code",
+			idClaims: idClaims(true, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(login, nil).Times(1)
+				mockRepo.EXPECT().FetchExternalIdentity(gomock.Any(), "stub", "idp-user-1").Return(models.ExternalIdentity{}, false, nil).Times(1)
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(models.User{}, errors.New("user not found")).Times(1)
+				mockRepo.EXPECT().InsertUserWithIdentity(gomock.Any(), gomock.Any(), models.ExternalIdentity{Provider: "stub", Subject: "idp-user-1", Email: "jane@example.com"}).
+					DoAndReturn(func(ctx context.Context, u models.User, identity models.ExternalIdentity) (models.User, error) {
+						if u.Email != "jane@example.com" || u.EmailVerifiedAt == nil || u.PasswordHash != "" {
+							t.Errorf("InsertUserWithIdentity() user = %+v", u)
+						}
+						return user, nil
+					}).Times(1)
+				mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).Return("access-token", nil).Times(1)
+				mockRepo.EXPECT().InsertSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name:     "unverified local account is claimed",
+			code:     "auth-code",
+			idClaims: idClaims(true, "nonce"),
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				squatted := user
+				squatted.TwoFactorEnabled = true
+				mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), hashToken("state")).Return(login, nil).Times(1)
+				mockRepo.EXPECT().FetchExternalIdentity(gomock.Any(), "stub", "idp-user-1").Return(models.ExternalIdentity{}, false, nil).Times(1)
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(squatted, nil).Times(1)
+				mockRepo.EXPECT().UpdatePassword(gomock.Any(), "jane@example.com", "").Return(nil).Times(1)
+				mockRepo.EXPECT().DisableTOTP(gomock.Any(), uint(7)).Return(nil).Times(1)
+				mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(7), "").Return(nil).Times(1)
+				mockRepo.EXPECT().MarkEmailVerified(gomock.Any(), uint(7)).Return(nil).Times(1)
+				mockRepo.EXPECT().InsertExternalIdentity(gomock.Any(), models.ExternalIdentity{UserID: 7, Provider: "stub", Subject: "idp-user-1", Email: "jane@example.com"}).Return(nil).Times(1)
+				mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).Return("access-token", nil).Times(1)
+				mockRepo.EXPECT().InsertSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub.idClaims = tt.idClaims
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockAuth := auth.NewMockAuthentication(mc)
+			tt.setup(mockRepo, mockAuth)
+			s, err := NewService(mockRepo, mockAuth, nil, stub.config())
+			if err != nil {
+				t.Fatalf("NewService() error = %v", err)
+			}
+			got, err := s.OIDCCallbackService(context.Background(), "stub", tt.code, "state")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OIDCCallbackService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.AccessToken != "access-token" || got.RefreshToken == "") {
+				t.Errorf("OIDCCallbackService() got = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+)
+
+const (
+	// outboxLease is how long a claimed message is hidden from other dispatchers. The
+	// deliveries of a batch have to end before it does, or a slow one could be sent twice.
+	outboxLease          = 5 * time.Minute
+	webhookTimeout       = 10 * time.Second
+	maxOutboxErrorLength = 1000
+	outboxPrune          = time.Hour
+)
+
+// Headers of a webhook request. The signature is "sha256=" followed by the hex encoded
+// HMAC-SHA256, keyed with the endpoint secret, of the timestamp, a "." and the body.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// OutboxWebhook is the payload of a webhook outbox message.
+type OutboxWebhook struct {
+	EndpointID uint            `json:"endpointId"`
+	Event      string          `json:"event"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// permanentError is a delivery failure that retrying cannot fix.
+type permanentError struct{ error }
+
+// OutboxDispatcher delivers the emails, webhooks and job matches queued in the outbox. Several can run
+// at once, each message is claimed by one of them.
+type OutboxDispatcher struct {
+	repo   repository.UserRepo
+	mailer mail.Mailer
+	client *http.Client
+	cfg    config.OutboxConfig
+	lease  time.Duration
+}
+
+func NewOutboxDispatcher(repo repository.UserRepo, mailer mail.Mailer, cfg config.OutboxConfig) (*OutboxDispatcher, error) {
+	if repo == nil || mailer == nil {
+		return nil, errors.New("the outbox dispatcher needs a repository and a mailer")
+	}
+	if cfg.PollInterval <= 0 || cfg.BatchSize <= 0 || cfg.MaxAttempts <= 0 || cfg.BackoffBase <= 0 || cfg.BackoffMax < cfg.BackoffBase {
+		return nil, errors.New("invalid outbox settings")
+	}
+	return &OutboxDispatcher{
+		repo:   repo,
+		mailer: mailer,
+		client: newWebhookClient(cfg.AllowPrivateWebhookTargets),
+		cfg:    cfg,
+		lease:  outboxLease,
+	}, nil
+}
+
+// Run delivers due messages every poll interval, and prunes delivered ones, until ctx is
+// cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	if d.cfg.Retention > 0 {
+		go d.prune(ctx)
+	}
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		// keep going while full batches come back, there is more waiting
+		for {
+			n, err := d.DispatchDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("outbox dispatch failed")
+			}
+			if err != nil || n < d.cfg.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune drops the messages delivered longer ago than the retention.
+func (d *OutboxDispatcher) prune(ctx context.Context) {
+	ticker := time.NewTicker(outboxPrune)
+	defer ticker.Stop()
+	for {
+		n, err := d.repo.DeleteDeliveredOutboxMessages(ctx, time.Now().Add(-d.cfg.Retention))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to prune the outbox")
+		} else if n > 0 {
+			log.Info().Int64("deleted", n).Msg("pruned delivered outbox messages")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue delivers one batch of due messages and returns how many it picked up.
+// Deliveries are cut off a fifth of the lease before it runs out, leaving time to record
+// them; the messages not started by then are claimed again once the lease is over.
+func (d *OutboxDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	claimedAt := time.Now()
+	messages, err := d.repo.ClaimOutboxMessages(ctx, claimedAt, d.lease, d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	deliverCtx, cancel := context.WithDeadline(ctx, claimedAt.Add(d.lease-d.lease/5))
+	defer cancel()
+	for i, msg := range messages {
+		if deliverCtx.Err() != nil {
+			log.Warn().Int("left", len(messages)-i).Msg("outbox batch ran out of its lease")
+			break
+		}
+		d.dispatch(ctx, deliverCtx, msg)
+	}
+	return len(messages), nil
+}
+
+// dispatch delivers msg within deliverCtx and records the outcome with ctx, so a delivery
+// cut off by the batch deadline is still recorded.
+func (d *OutboxDispatcher) dispatch(ctx, deliverCtx context.Context, msg models.OutboxMessage) {
+	err := d.deliver(deliverCtx, msg)
+	if err == nil {
+		err = d.repo.MarkOutboxDelivered(ctx, msg.ID, time.Now())
+		if err != nil {
+			// the lease runs out and the message is sent again, better twice than never
+			log.Error().Err(err).Uint("outbox id", msg.ID).Msg("failed to mark outbox message delivered")
+		}
+		return
+	}
+
+	attempts := msg.Attempts + 1
+	status := models.OutboxStatusPending
+	next := time.Now().Add(d.backoff(attempts))
+	var permanent permanentError
+	if attempts >= d.cfg.MaxAttempts || errors.As(err, &permanent) {
+		status = models.OutboxStatusDead
+		log.Error().Err(err).Uint("outbox id", msg.ID).Str("kind", msg.Kind).Int("attempts", attempts).Msg("giving up on outbox message")
+	} else {
+		log.Warn().Err(err).Uint("outbox id", msg.ID).Str("kind", msg.Kind).Int("attempts", attempts).Time("retry at", next).Msg("outbox delivery failed")
+	}
+	err = d.repo.RecordOutboxFailure(ctx, msg.ID, attempts, truncateError(err), status, next)
+	if err != nil {
+		log.Error().Err(err).Uint("outbox id", msg.ID).Msg("failed to record outbox failure")
+	}
+}
+
+// backoff is the wait before the next attempt: BackoffBase doubled per failed attempt,
+// capped at BackoffMax.
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.BackoffBase
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.cfg.BackoffMax {
+			return d.cfg.BackoffMax
+		}
+	}
+	return wait
+}
+
+func (d *OutboxDispatcher) deliver(ctx context.Context, msg models.OutboxMessage) error {
+	switch msg.Kind {
+	case models.OutboxKindEmail:
+		var email mail.Message
+		err := json.Unmarshal(msg.Payload, &email)
+		if err != nil {
+			return permanentError{fmt.Errorf("invalid email payload: %w", err)}
+		}
+		return d.mailer.Send(ctx, email)
+	case models.OutboxKindWebhook:
+		var hook OutboxWebhook
+		err := json.Unmarshal(msg.Payload, &hook)
+		if err != nil {
+			return permanentError{fmt.Errorf("invalid webhook payload: %w", err)}
+		}
+		return d.deliverWebhook(ctx, msg, hook)
+	case models.OutboxKindJobMatch:
+		var match OutboxJobMatch
+		err := json.Unmarshal(msg.Payload, &match)
+		if err != nil {
+			return permanentError{fmt.Errorf("invalid job match payload: %w", err)}
+		}
+		return notifyJobMatches(ctx, d.repo, match.JobID)
+	default:
+		return permanentError{fmt.Errorf("unknown outbox message kind %q", msg.Kind)}
+	}
+}
+
+// deliverWebhook posts the event to its endpoint and adds the attempt to the endpoint's
+// delivery log.
+func (d *OutboxDispatcher) deliverWebhook(ctx context.Context, msg models.OutboxMessage, hook OutboxWebhook) error {
+	endpoint, err := d.repo.FetchWebhookEndpoint(ctx, hook.EndpointID)
+	if err != nil {
+		return err
+	}
+	if endpoint.ID == 0 {
+		return permanentError{errors.New("the webhook endpoint was deleted")}
+	}
+
+	start := time.Now()
+	statusCode, err := d.postWebhook(ctx, endpoint, msg.ID, hook, start)
+	delivery := models.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		OutboxID:   msg.ID,
+		Event:      hook.Event,
+		Attempt:    msg.Attempts + 1,
+		StatusCode: statusCode,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		delivery.Error = truncateError(err)
+	}
+	recordErr := d.repo.InsertWebhookDelivery(ctx, delivery)
+	if recordErr != nil {
+		log.Error().Err(recordErr).Uint("outbox id", msg.ID).Msg("failed to record webhook delivery")
+	}
+	return err
+}
+
+// postWebhook sends the signed event and returns the response status, 0 when there was none.
+func (d *OutboxDispatcher) postWebhook(ctx context.Context, endpoint models.WebhookEndpoint, deliveryID uint, hook OutboxWebhook, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(hook.Body))
+	if err != nil {
+		return 0, permanentError{err}
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "job-portal-webhooks")
+	req.Header.Set(WebhookEventHeader, hook.Event)
+	req.Header.Set(WebhookDeliveryHeader, fmt.Sprint(deliveryID))
+	req.Header.Set(WebhookTimestampHeader, fmt.Sprint(timestamp))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, timestamp, hook.Body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the signature header value of a webhook body sent at timestamp.
+// Receivers compute the same and compare, and should refuse old timestamps to stop replays.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookClient returns the client webhooks are posted with. Redirects are not followed,
+// and unless allowPrivate is set only public addresses can be reached. The check is made on
+// the address actually dialled, so a hostname resolving to a private address fails too.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivateAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be dialled instead of the target and defeat the address check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("webhook target %s is not a public address", host)
+	}
+	return nil
+}
+
+func truncateError(err error) string {
+	msg := err.Error()
+	if len(msg) > maxOutboxErrorLength {
+		msg = msg[:maxOutboxErrorLength]
+	}
+	return msg
+}
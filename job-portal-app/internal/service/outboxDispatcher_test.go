@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"io"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testOutboxConfig = config.OutboxConfig{
+	PollInterval:               time.Second,
+	BatchSize:                  10,
+	MaxAttempts:                3,
+	BackoffBase:                time.Minute,
+	BackoffMax:                 time.Hour,
+	AllowPrivateWebhookTargets: true,
+}
+
+func TestOutboxDispatcher_DispatchDue(t *testing.T) {
+	const secret = "whsec_test"
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if err != nil || !hmac.Equal([]byte(r.Header.Get(WebhookSignatureHeader)), []byte(SignWebhook(secret, timestamp, body))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(WebhookEventHeader) != "job.created" || r.Header.Get(WebhookDeliveryHeader) != "1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+	endpoint := models.WebhookEndpoint{ID: 7, URL: hook.URL, Secret: secret}
+
+	email, _ := json.Marshal(mail.Message{To: []string{"test@example.com"}, Subject: "hello", Text: "hi"})
+	webhook := func(event string) json.RawMessage {
+		payload, _ := json.Marshal(OutboxWebhook{EndpointID: 7, Event: event, Body: json.RawMessage(`{"type":"` + event + `"}`)})
+		return payload
+	}
+
+	tests := []struct {
+		name       string
+		message    models.OutboxMessage
+		mailErr    error
+		endpoint   *models.WebhookEndpoint
+		wantSent   int
+		wantStatus string
+		wantRetry  time.Duration
+		// wantDelivery is the status code in the delivery log, -1 when nothing is logged
+		wantDelivery int
+	}{
+		{
+			name:         "email delivered",
+			wantDelivery: -1,
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindEmail, Payload: email},
+			wantSent:     1,
+			wantStatus:   models.OutboxStatusDelivered,
+		},
+		{
+			name:         "email failure is retried",
+			wantDelivery: -1,
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindEmail, Payload: email},
+			mailErr:      errors.New("connection refused"),
+			wantStatus:   models.OutboxStatusPending,
+			wantRetry:    time.Minute,
+		},
+		{
+			name:         "backoff doubles",
+			wantDelivery: -1,
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindEmail, Payload: email, Attempts: 1},
+			mailErr:      errors.New("connection refused"),
+			wantStatus:   models.OutboxStatusPending,
+			wantRetry:    2 * time.Minute,
+		},
+		{
+			name:         "dead after the last attempt",
+			wantDelivery: -1,
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindEmail, Payload: email, Attempts: 2},
+			mailErr:      errors.New("connection refused"),
+			wantStatus:   models.OutboxStatusDead,
+		},
+		{
+			name:         "webhook delivered",
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindWebhook, Payload: webhook("job.created")},
+			endpoint:     &endpoint,
+			wantStatus:   models.OutboxStatusDelivered,
+			wantDelivery: http.StatusNoContent,
+		},
+		{
+			name:         "webhook error response",
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindWebhook, Payload: webhook("other")},
+			endpoint:     &endpoint,
+			wantStatus:   models.OutboxStatusPending,
+			wantRetry:    time.Minute,
+			wantDelivery: http.StatusInternalServerError,
+		},
+		{
+			name:         "webhook secret rotated",
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindWebhook, Payload: webhook("job.created")},
+			endpoint:     &models.WebhookEndpoint{ID: 7, URL: hook.URL, Secret: "whsec_other"},
+			wantStatus:   models.OutboxStatusPending,
+			wantRetry:    time.Minute,
+			wantDelivery: http.StatusUnauthorized,
+		},
+		{
+			name:         "webhook endpoint deleted",
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindWebhook, Payload: webhook("job.created")},
+			endpoint:     &models.WebhookEndpoint{},
+			wantStatus:   models.OutboxStatusDead,
+			wantDelivery: -1,
+		},
+		{
+			name:         "invalid job match",
+			message:      models.OutboxMessage{ID: 1, Kind: models.OutboxKindJobMatch, Payload: json.RawMessage(`"job"`)},
+			wantStatus:   models.OutboxStatusDead,
+			wantDelivery: -1,
+		},
+		{
+			name:         "unknown kind",
+			message:      models.OutboxMessage{ID: 1, Kind: "pigeon"},
+			wantStatus:   models.OutboxStatusDead,
+			wantDelivery: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().ClaimOutboxMessages(gomock.Any(), gomock.Any(), outboxLease, 10).Return([]models.OutboxMessage{tt.message}, nil).Times(1)
+			status := ""
+			var retryAt time.Time
+			if tt.endpoint != nil {
+				mockRepo.EXPECT().FetchWebhookEndpoint(gomock.Any(), uint(7)).Return(*tt.endpoint, nil).Times(1)
+			}
+			delivered := -1
+			if tt.wantDelivery != -1 {
+				mockRepo.EXPECT().InsertWebhookDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, delivery models.WebhookDelivery) error {
+					if delivery.EndpointID != 7 || delivery.OutboxID != 1 || delivery.Attempt != tt.message.Attempts+1 {
+						t.Errorf("InsertWebhookDelivery() got %+v", delivery)
+					}
+					delivered = delivery.StatusCode
+					return nil
+				}).Times(1)
+			}
+			if tt.wantStatus == models.OutboxStatusDelivered {
+				mockRepo.EXPECT().MarkOutboxDelivered(gomock.Any(), uint(1), gomock.Any()).DoAndReturn(func(ctx context.Context, id uint, at time.Time) error {
+					status = models.OutboxStatusDelivered
+					return nil
+				}).Times(1)
+			} else {
+				mockRepo.EXPECT().RecordOutboxFailure(gomock.Any(), uint(1), tt.message.Attempts+1, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id uint, attempts int, lastError, s string, next time.Time) error {
+					if lastError == "" {
+						t.Error("RecordOutboxFailure() without an error")
+					}
+					status, retryAt = s, next
+					return nil
+				}).Times(1)
+			}
+
+			mailer := mail.NewMemory()
+			mailer.Err = tt.mailErr
+			d, err := NewOutboxDispatcher(mockRepo, mailer, testOutboxConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			n, err := d.DispatchDue(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("DispatchDue() = %d, %v", n, err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("DispatchDue() left the message %q, want %q", status, tt.wantStatus)
+			}
+			if delivered != tt.wantDelivery {
+				t.Errorf("DispatchDue() logged status %d, want %d", delivered, tt.wantDelivery)
+			}
+			if len(mailer.Sent()) != tt.wantSent {
+				t.Errorf("DispatchDue() sent %d emails, want %d", len(mailer.Sent()), tt.wantSent)
+			}
+			if tt.wantRetry != 0 {
+				wait := retryAt.Sub(start)
+				if wait < tt.wantRetry || wait > tt.wantRetry+time.Second {
+					t.Errorf("DispatchDue() retries in %v, want %v", wait, tt.wantRetry)
+				}
+			}
+		})
+	}
+}
+
+// slowMailer takes delay to send a message, or gives up when the context ends first.
+type slowMailer struct {
+	*mail.Memory
+	delay time.Duration
+}
+
+func (m *slowMailer) Send(ctx context.Context, msg mail.Message) error {
+	select {
+	case <-time.After(m.delay):
+		return m.Memory.Send(ctx, msg)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestOutboxDispatcher_batchOutlastsLease(t *testing.T) {
+	email, _ := json.Marshal(mail.Message{To: []string{"test@example.com"}, Subject: "hello", Text: "hi"})
+	var messages []models.OutboxMessage
+	for id := uint(1); id <= 4; id++ {
+		messages = append(messages, models.OutboxMessage{ID: id, Kind: models.OutboxKindEmail, Payload: email})
+	}
+	const lease = time.Second
+
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	var claimedAt time.Time
+	mockRepo.EXPECT().ClaimOutboxMessages(gomock.Any(), gomock.Any(), lease, 10).DoAndReturn(func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+		claimedAt = now
+		return messages, nil
+	}).Times(1)
+	// two emails fit in the batch deadline, the third is cut off by it and the fourth is
+	// left for when the lease is over
+	mockRepo.EXPECT().MarkOutboxDelivered(gomock.Any(), uint(1), gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().MarkOutboxDelivered(gomock.Any(), uint(2), gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().RecordOutboxFailure(gomock.Any(), uint(3), 1, gomock.Any(), models.OutboxStatusPending, gomock.Any()).DoAndReturn(func(ctx context.Context, id uint, attempts int, lastError, s string, next time.Time) error {
+		if ctx.Err() != nil {
+			t.Errorf("RecordOutboxFailure() with an ended context: %v", ctx.Err())
+		}
+		if !strings.Contains(lastError, context.DeadlineExceeded.Error()) {
+			t.Errorf("RecordOutboxFailure() error = %q, want the deadline", lastError)
+		}
+		return nil
+	}).Times(1)
+
+	mailer := &slowMailer{Memory: mail.NewMemory(), delay: 300 * time.Millisecond}
+	d, err := NewOutboxDispatcher(mockRepo, mailer, testOutboxConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.lease = lease
+	n, err := d.DispatchDue(context.Background())
+	if err != nil || n != 4 {
+		t.Fatalf("DispatchDue() = %d, %v", n, err)
+	}
+	if took := time.Since(claimedAt); took >= lease {
+		t.Errorf("DispatchDue() took %v, longer than the lease", took)
+	}
+	if len(mailer.Sent()) != 2 {
+		t.Errorf("DispatchDue() sent %d emails, want 2", len(mailer.Sent()))
+	}
+}
+
+func TestOutboxDispatcher_backoff(t *testing.T) {
+	d := &OutboxDispatcher{cfg: testOutboxConfig}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, time.Hour},
+		{40, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOutboxDispatcher_prune(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	cfg := testOutboxConfig
+	cfg.Retention = 24 * time.Hour
+	start := time.Now()
+	mockRepo.EXPECT().DeleteDeliveredOutboxMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+		if before.Before(start.Add(-cfg.Retention)) || before.After(time.Now().Add(-cfg.Retention)) {
+			t.Errorf("DeleteDeliveredOutboxMessages() before = %v, want a day ago", before)
+		}
+		return 2, nil
+	}).Times(1)
+	d, err := NewOutboxDispatcher(mockRepo, mail.NewMemory(), cfg)
+	if err != nil {
+		t.Fatalf("NewOutboxDispatcher() error = %v", err)
+	}
+	// a cancelled context stops the pruner after its first pass
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d.prune(ctx)
+}
+
+func TestOutboxDispatcher_privateWebhookTargets(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+	endpoint := models.WebhookEndpoint{ID: 7, URL: hook.URL, Secret: "whsec_test"}
+	hookData := OutboxWebhook{EndpointID: 7, Event: models.WebhookEventTest, Body: json.RawMessage(`{}`)}
+
+	cfg := testOutboxConfig
+	cfg.AllowPrivateWebhookTargets = false
+	d, err := NewOutboxDispatcher(repository.NewMockUserRepo(gomock.NewController(t)), mail.NewMemory(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := d.postWebhook(context.Background(), endpoint, 1, hookData, time.Now())
+	if err == nil || status != 0 || !strings.Contains(err.Error(), "not a public address") {
+		t.Errorf("postWebhook() to a loopback address = %d, %v", status, err)
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/models"
+)
+
+const maxOutboxMessages = 500
+
+var ErrNoDeadOutboxMessage = errors.New("no failed delivery with that id")
+
+// ListOutboxMessagesService returns the outbox messages in the given status, dead ones when
+// none is given.
+func (s *Service) ListOutboxMessagesService(ctx context.Context, status string, limit int) ([]models.OutboxMessage, error) {
+	switch status {
+	case "":
+		status = models.OutboxStatusDead
+	case models.OutboxStatusPending, models.OutboxStatusDelivered, models.OutboxStatusDead:
+	default:
+		return nil, fmt.Errorf("unknown status %q", status)
+	}
+	if limit <= 0 || limit > maxOutboxMessages {
+		limit = maxOutboxMessages
+	}
+	return s.UserRepo.FetchOutboxMessages(ctx, status, limit)
+}
+
+// ReplayOutboxMessageService queues a dead message for delivery again.
+func (s *Service) ReplayOutboxMessageService(ctx context.Context, id uint) error {
+	ok, err := s.UserRepo.ReplayOutboxMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoDeadOutboxMessage
+	}
+	log.Info().Uint("outbox id", id).Msg("outbox message replayed")
+	return nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+)
+
+func TestService_ListOutboxMessagesService(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		limit      int
+		wantStatus string
+		wantLimit  int
+		wantErr    bool
+	}{
+		{name: "dead by default", wantStatus: models.OutboxStatusDead, wantLimit: maxOutboxMessages},
+		{name: "pending", status: models.OutboxStatusPending, limit: 20, wantStatus: models.OutboxStatusPending, wantLimit: 20},
+		{name: "limit capped", status: models.OutboxStatusDead, limit: 10000, wantStatus: models.OutboxStatusDead, wantLimit: maxOutboxMessages},
+		{name: "unknown status", status: "lost", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			if !tt.wantErr {
+				mockRepo.EXPECT().FetchOutboxMessages(gomock.Any(), tt.wantStatus, tt.wantLimit).Return([]models.OutboxMessage{{ID: 1}}, nil).Times(1)
+			}
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			_, err := s.ListOutboxMessagesService(context.Background(), tt.status, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListOutboxMessagesService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ReplayOutboxMessageService(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name: "not dead",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().ReplayOutboxMessage(gomock.Any(), uint(4)).Return(false, nil).Times(1)
+			},
+			wantErr: ErrNoDeadOutboxMessage,
+		},
+		{
+			name: "error from db",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().ReplayOutboxMessage(gomock.Any(), uint(4)).Return(false, errors.New("could not update the outbox")).Times(1)
+			},
+			wantErr: errors.New("could not update the outbox"),
+		},
+		{
+			name: "success",
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().ReplayOutboxMessage(gomock.Any(), uint(4)).Return(true, nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			err := s.ReplayOutboxMessageService(context.Background(), 4)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("ReplayOutboxMessageService() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
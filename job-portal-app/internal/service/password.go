@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+)
+
+const (
+	// minPasswordLength is the floor for the configured minimum length.
+	minPasswordLength = 8
+	// bcrypt ignores everything after 72 bytes
+	maxPasswordLength = 72
+	// minIdentityLength stops very short usernames from ruling out half of all passwords.
+	minIdentityLength = 3
+)
+
+var (
+	ErrInvalidOldPassword = errors.New("invalid old password")
+	ErrPasswordMismatch   = errors.New("new password and confirmation do not match")
+	ErrWeakPassword       = errors.New("password does not meet the password policy")
+)
+
+//go:embed breached_passwords.txt
+var breachedPasswords []byte
+
+// PasswordError lists every rule of the password policy a password broke. Field is the
+// request field the password came from so clients can show the problems next to it.
+type PasswordError struct {
+	Field    string
+	Problems []string
+}
+
+func (e *PasswordError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrWeakPassword, strings.Join(e.Problems, "; "))
+}
+
+func (e *PasswordError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// passwordPolicy checks new passwords at registration, reset and change.
+type passwordPolicy struct {
+	minLength     int
+	requireLower  bool
+	requireUpper  bool
+	requireDigit  bool
+	requireSymbol bool
+	breached      map[string]struct{}
+}
+
+// newPasswordPolicy builds the policy from the config and loads the breached-password list,
+// including the extra file when one is configured.
+func newPasswordPolicy(cfg config.PasswordConfig) (passwordPolicy, error) {
+	p := passwordPolicy{
+		minLength:     cfg.MinLength,
+		requireLower:  cfg.RequireLower,
+		requireUpper:  cfg.RequireUpper,
+		requireDigit:  cfg.RequireDigit,
+		requireSymbol: cfg.RequireSymbol,
+		breached:      make(map[string]struct{}),
+	}
+	if p.minLength > maxPasswordLength {
+		return passwordPolicy{}, fmt.Errorf("password minimum length cannot be more than %d", maxPasswordLength)
+	}
+
+	err := p.addBreached(bytes.NewReader(breachedPasswords))
+	if err != nil {
+		return passwordPolicy{}, fmt.Errorf("reading breached password list: %w", err)
+	}
+	if cfg.BreachedListFile != "" {
+		f, err := os.Open(cfg.BreachedListFile)
+		if err != nil {
+			return passwordPolicy{}, fmt.Errorf("opening breached password list: %w", err)
+		}
+		defer f.Close()
+		err = p.addBreached(f)
+		if err != nil {
+			return passwordPolicy{}, fmt.Errorf("reading breached password list: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// addBreached reads one password per line, skipping blank lines and # comments.
+func (p *passwordPolicy) addBreached(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// check validates a new password for the user and reports every problem at once.
+func (p passwordPolicy) check(field, password string, user models.User) error {
+	var problems []string
+
+	minLength := p.minLength
+	if minLength < minPasswordLength {
+		minLength = minPasswordLength
+	}
+	if len([]rune(password)) < minLength {
+		problems = append(problems, fmt.Sprintf("it must be at least %d characters long", minLength))
+	}
+	if len(password) > maxPasswordLength {
+		problems = append(problems, fmt.Sprintf("it must be at most %d bytes long", maxPasswordLength))
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.requireLower && !lower {
+		problems = append(problems, "it must contain a lowercase letter")
+	}
+	if p.requireUpper && !upper {
+		problems = append(problems, "it must contain an uppercase letter")
+	}
+	if p.requireDigit && !digit {
+		problems = append(problems, "it must contain a digit")
+	}
+	if p.requireSymbol && !symbol {
+		problems = append(problems, "it must contain a symbol")
+	}
+
+	folded := strings.ToLower(password)
+	if containsIdentity(folded, user.Username) {
+		problems = append(problems, "it must not contain your username")
+	}
+	email := strings.ToLower(user.Email)
+	local, _, _ := strings.Cut(email, "@")
+	if containsIdentity(folded, email) || containsIdentity(folded, local) {
+		problems = append(problems, "it must not contain your email address")
+	}
+	if _, ok := p.breached[folded]; ok {
+		problems = append(problems, "it appears in a list of breached passwords, please choose another one")
+	}
+
+	if len(problems) > 0 {
+		return &PasswordError{Field: field, Problems: problems}
+	}
+	return nil
+}
+
+func containsIdentity(password, identity string) bool {
+	identity = strings.ToLower(strings.TrimSpace(identity))
+	return len(identity) >= minIdentityLength && strings.Contains(password, identity)
+}
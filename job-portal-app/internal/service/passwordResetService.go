@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+)
+
+const (
+	otpTTL         = 5 * time.Minute
+	otpCooldown    = time.Minute
+	otpMaxAttempts = 5
+	// otpLockout is how long an email stays locked after otpMaxAttempts failures.
+	otpLockout    = 15 * time.Minute
+	resetTokenTTL = 10 * time.Minute
+)
+
+var (
+	ErrInvalidOTP        = errors.New("invalid or expired OTP")
+	ErrOTPLocked         = errors.New("too many failed attempts, please try again later")
+	ErrOTPCooldown       = errors.New("please wait before requesting another OTP")
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+)
+
+// forgetPasswordResponse is returned whether or not the account exists, so the endpoint
+// cannot be used to find out which emails are registered.
+var forgetPasswordResponse = models.ForgetPasswordResponse{
+	Message: "If the email is registered, an OTP has been sent to it",
+}
+
+// ForgetPasswordService emails a one time password to the user. Only a hash of the OTP is
+// stored, and a new one cannot be requested until the cooldown has passed.
+func (s *Service) ForgetPasswordService(ctx context.Context, data models.ForgetPasswordRequest) (models.ForgetPasswordResponse, error) {
+	email := normalizeEmail(data.Email)
+
+	// the cooldown applies to unknown emails as well, otherwise it would reveal which exist
+	allowed, err := s.rdb.StartOTPCooldown(ctx, email, otpCooldown)
+	if err != nil {
+		log.Error().Err(err).Msg("error starting OTP cooldown")
+		return models.ForgetPasswordResponse{}, errors.New("failed to generate OTP")
+	}
+	if !allowed {
+		return models.ForgetPasswordResponse{}, ErrOTPCooldown
+	}
+
+	attempts, err := s.rdb.GetOTPAttempts(ctx, email)
+	if err != nil {
+		log.Error().Err(err).Msg("error reading OTP attempts")
+		return models.ForgetPasswordResponse{}, errors.New("failed to generate OTP")
+	}
+	if attempts >= otpMaxAttempts {
+		log.Warn().Msg("OTP requested for a locked email")
+		return forgetPasswordResponse, nil
+	}
+
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		log.Info().Err(err).Msg("OTP requested for an unknown email")
+		return forgetPasswordResponse, nil
+	}
+
+	otp, err := generateOTP()
+	if err != nil {
+		log.Error().Err(err).Msg("error generating OTP")
+		return models.ForgetPasswordResponse{}, errors.New("failed to generate OTP")
+	}
+
+	err = s.rdb.SaveOTP(ctx, email, hashToken(otp), otpTTL)
+	if err != nil {
+		log.Error().Err(err).Msg("error saving OTP in cache")
+		return models.ForgetPasswordResponse{}, errors.New("failed to generate OTP")
+	}
+
+	err = queueEmail(ctx, s.UserRepo, emailLocale(ctx, user), email, mail.TemplateOTP, mail.OTPData{Code: otp, Minutes: int(otpTTL.Minutes())})
+	if err != nil {
+		log.Error().Err(err).Msg("error queueing OTP email")
+		return models.ForgetPasswordResponse{}, errors.New("failed to send OTP via email")
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditOTPIssued, TargetUserID: uintRef(user.ID)})
+
+	return forgetPasswordResponse, nil
+}
+
+// VerifyOTPService checks the OTP and exchanges it for a single use reset token. Every wrong
+// OTP counts towards the lockout; once it is reached the OTP is discarded.
+func (s *Service) VerifyOTPService(ctx context.Context, data models.VerifyOTPRequest) (models.VerifyOTPResponse, error) {
+	email := normalizeEmail(data.Email)
+
+	attempts, err := s.rdb.GetOTPAttempts(ctx, email)
+	if err != nil {
+		return models.VerifyOTPResponse{}, errors.New("failed to verify OTP")
+	}
+	if attempts >= otpMaxAttempts {
+		return models.VerifyOTPResponse{}, ErrOTPLocked
+	}
+
+	storedHash, err := s.rdb.GetOTP(ctx, email)
+	if err != nil {
+		return models.VerifyOTPResponse{}, errors.New("failed to verify OTP")
+	}
+	if storedHash == "" || subtle.ConstantTimeCompare([]byte(storedHash), []byte(hashToken(data.OTP))) != 1 {
+		attempts, err = s.rdb.IncrOTPAttempts(ctx, email, otpLockout)
+		if err != nil {
+			return models.VerifyOTPResponse{}, errors.New("failed to verify OTP")
+		}
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditOTPFailed, Details: map[string]string{"email": email, "attempts": fmt.Sprint(attempts)}})
+		if attempts >= otpMaxAttempts {
+			log.Warn().Msg("email locked after too many failed OTP attempts")
+			_ = s.rdb.DeleteOTP(ctx, email)
+			return models.VerifyOTPResponse{}, ErrOTPLocked
+		}
+		return models.VerifyOTPResponse{}, ErrInvalidOTP
+	}
+
+	err = s.rdb.DeleteOTP(ctx, email)
+	if err != nil {
+		return models.VerifyOTPResponse{}, errors.New("failed to verify OTP")
+	}
+	err = s.rdb.ResetOTPAttempts(ctx, email)
+	if err != nil {
+		log.Error().Err(err).Msg("error resetting OTP attempts")
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditOTPVerified, Details: map[string]string{"email": email}})
+
+	resetToken, err := randomToken(32)
+	if err != nil {
+		return models.VerifyOTPResponse{}, err
+	}
+	err = s.rdb.SaveResetToken(ctx, hashToken(resetToken), email, resetTokenTTL)
+	if err != nil {
+		return models.VerifyOTPResponse{}, errors.New("failed to verify OTP")
+	}
+	return models.VerifyOTPResponse{ResetToken: resetToken}, nil
+}
+
+// ResetPasswordService sets a new password using the reset token from VerifyOTPService.
+// The token is only used up once the new password is accepted, so a typo does not send
+// the user through the OTP again.
+func (s *Service) ResetPasswordService(ctx context.Context, data models.ResetPasswordRequest) error {
+	tokenHash := hashToken(data.ResetToken)
+	email, err := s.rdb.GetResetToken(ctx, tokenHash)
+	if err != nil {
+		return errors.New("failed to verify reset token")
+	}
+	if email == "" {
+		return ErrInvalidResetToken
+	}
+	if data.NewPassword != data.ConfirmPassword {
+		return ErrPasswordMismatch
+	}
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to update the password: %w", err)
+	}
+	err = s.passwords.check("new_password", data.NewPassword, user)
+	if err != nil {
+		return err
+	}
+
+	// consuming is atomic, only one of two concurrent resets gets the token
+	consumed, err := s.rdb.ConsumeResetToken(ctx, tokenHash)
+	if err != nil {
+		return errors.New("failed to verify reset token")
+	}
+	if consumed != email {
+		return ErrInvalidResetToken
+	}
+	return s.updatePassword(ctx, user, data.NewPassword)
+}
+
+// updatePassword stores the new password and logs out every session of the user.
+func (s *Service) updatePassword(ctx context.Context, user models.User, newPassword string) error {
+	// Hash the new password before updating
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("failed to hash the new password")
+	}
+
+	// Update the password in the database
+	err = s.UserRepo.UpdatePassword(ctx, user.Email, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to update the password: %w", err)
+	}
+
+	// A changed password logs out every session
+	err = s.LogoutAllService(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	log.Info().Uint("user id", user.ID).Msg("password reset successfully")
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditPasswordReset, TargetUserID: uintRef(user.ID)})
+	return nil
+}
+
+// generateOTP returns a uniformly random, zero padded 6 digit code.
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/redis"
+	"job-portal-api/internal/repository"
+	"regexp"
+	"testing"
+)
+
+func Test_generateOTP(t *testing.T) {
+	sixDigits := regexp.MustCompile(`^[0-9]{6}$`)
+	for i := 0; i < 1000; i++ {
+		otp, err := generateOTP()
+		if err != nil {
+			t.Fatalf("generateOTP() error = %v", err)
+		}
+		if !sixDigits.MatchString(otp) {
+			t.Fatalf("generateOTP() = %q, want 6 digits", otp)
+		}
+	}
+}
+
+func TestService_passwordResetFlow(t *testing.T) {
+	ctx := context.Background()
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(models.User{Model: gorm.Model{ID: 1}, Email: "jane@example.com"}, nil).Times(3)
+	mockRepo.EXPECT().UpdatePassword(gomock.Any(), "jane@example.com", gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(1), "").Return(nil).Times(1)
+	var otp string
+	mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+		var msg mail.Message
+		_ = json.Unmarshal(messages[0].Payload, &msg)
+		otp = regexp.MustCompile(`[0-9]{6}`).FindString(msg.Text)
+		return nil
+	}).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, redis.NewMemory(), config.Config{})
+
+	_, err := s.ForgetPasswordService(ctx, models.ForgetPasswordRequest{Email: "Jane@Example.com"})
+	if err != nil || otp == "" {
+		t.Fatalf("ForgetPasswordService() error = %v, otp %q", err, otp)
+	}
+	_, err = s.ForgetPasswordService(ctx, models.ForgetPasswordRequest{Email: "jane@example.com"})
+	if !errors.Is(err, ErrOTPCooldown) {
+		t.Fatalf("ForgetPasswordService() during the cooldown error = %v, want %v", err, ErrOTPCooldown)
+	}
+
+	wrong := "000000"
+	if otp == wrong {
+		wrong = "111111"
+	}
+	_, err = s.VerifyOTPService(ctx, models.VerifyOTPRequest{Email: "jane@example.com", OTP: wrong})
+	if !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("VerifyOTPService() with a wrong OTP error = %v, want %v", err, ErrInvalidOTP)
+	}
+	verified, err := s.VerifyOTPService(ctx, models.VerifyOTPRequest{Email: "jane@example.com", OTP: otp})
+	if err != nil || verified.ResetToken == "" {
+		t.Fatalf("VerifyOTPService() error = %v", err)
+	}
+	_, err = s.VerifyOTPService(ctx, models.VerifyOTPRequest{Email: "jane@example.com", OTP: otp})
+	if !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("VerifyOTPService() with a used OTP error = %v, want %v", err, ErrInvalidOTP)
+	}
+
+	// the token survives a rejected password and is used up by the accepted one
+	err = s.ResetPasswordService(ctx, models.ResetPasswordRequest{ResetToken: verified.ResetToken, NewPassword: "a", ConfirmPassword: "b"})
+	if !errors.Is(err, ErrPasswordMismatch) {
+		t.Fatalf("ResetPasswordService() error = %v, want %v", err, ErrPasswordMismatch)
+	}
+	err = s.ResetPasswordService(ctx, models.ResetPasswordRequest{ResetToken: verified.ResetToken, NewPassword: "a", ConfirmPassword: "a"})
+	if !errors.Is(err, ErrWeakPassword) {
+		t.Fatalf("ResetPasswordService() with a weak password error = %v, want %v", err, ErrWeakPassword)
+	}
+	err = s.ResetPasswordService(ctx, models.ResetPasswordRequest{ResetToken: verified.ResetToken, NewPassword: "tulip-harbour-42", ConfirmPassword: "tulip-harbour-42"})
+	if err != nil {
+		t.Fatalf("ResetPasswordService() error = %v", err)
+	}
+	err = s.ResetPasswordService(ctx, models.ResetPasswordRequest{ResetToken: verified.ResetToken, NewPassword: "tulip-harbour-42", ConfirmPassword: "tulip-harbour-42"})
+	if !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("ResetPasswordService() with a used token error = %v, want %v", err, ErrInvalidResetToken)
+	}
+}
+
+func TestService_mixedCaseEmail(t *testing.T) {
+	ctx := context.Background()
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+		return fn(mockRepo)
+	}).Times(1)
+	// the repo finds users by the email as it was stored
+	var stored models.User
+	mockRepo.EXPECT().InsertUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user models.User) (models.User, error) {
+		user.ID = 1
+		stored = user
+		return user, nil
+	}).Times(1)
+	found := 0
+	lookup := func(ctx context.Context, email string) (models.User, error) {
+		if email != stored.Email {
+			return models.User{}, errors.New("user not found")
+		}
+		found++
+		return stored, nil
+	}
+	mockRepo.EXPECT().VerifyUserCredentials(gomock.Any(), gomock.Any()).DoAndReturn(lookup).Times(1)
+	mockRepo.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).DoAndReturn(lookup).Times(1)
+	var otpSent bool
+	mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+		otpSent = messages[0].Summary != "Verify your email address"
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().InsertSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	mockAuth := auth.NewMockAuthentication(mc)
+	mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).Return("token", nil).AnyTimes()
+
+	cfg := config.Config{AuthConfig: config.AuthConfig{EmailVerificationSecret: "secret"}}
+	s, _ := NewService(mockRepo, mockAuth, redis.NewMemory(), cfg)
+	user, err := s.RegisterUserService(ctx, models.NewUser{Username: "jane", Email: "Jane@Example.com", Password: "tulip-harbour-42"})
+	if err != nil {
+		t.Fatalf("RegisterUserService() error = %v", err)
+	}
+	if user.Email != "jane@example.com" {
+		t.Errorf("RegisterUserService() stored email %q, want it lowercase", user.Email)
+	}
+	// the login and the reset find the account however the email is typed
+	_, err = s.UserLoginService(ctx, models.NewUser{Email: "JANE@example.com", Password: "tulip-harbour-42"})
+	if err != nil {
+		t.Errorf("UserLoginService() error = %v", err)
+	}
+	_, err = s.ForgetPasswordService(ctx, models.ForgetPasswordRequest{Email: "JANE@EXAMPLE.COM"})
+	if err != nil || !otpSent {
+		t.Errorf("ForgetPasswordService() error = %v, otp sent %v", err, otpSent)
+	}
+	if found != 2 {
+		t.Errorf("the account was found %d times, want 2", found)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_passwordPolicy_check(t *testing.T) {
+	user := models.User{Username: "sandeep", Email: "sandy.s@example.com"}
+	tests := []struct {
+		name         string
+		cfg          config.PasswordConfig
+		password     string
+		wantProblems []string
+	}{
+		{
+			name:     "valid password",
+			cfg:      config.PasswordConfig{MinLength: 10, RequireLower: true, RequireDigit: true},
+			password: "correct horse 42",
+		},
+		{
+			name:         "minimum length is never below the floor",
+			cfg:          config.PasswordConfig{MinLength: 4},
+			password:     "abc12",
+			wantProblems: []string{"it must be at least 8 characters long"},
+		},
+		{
+			name:     "every missing character class is reported",
+			cfg:      config.PasswordConfig{RequireLower: true, RequireUpper: true, RequireDigit: true, RequireSymbol: true},
+			password: "abcdefghij",
+			wantProblems: []string{
+				"it must contain an uppercase letter",
+				"it must contain a digit",
+				"it must contain a symbol",
+			},
+		},
+		{
+			name:         "contains the username",
+			password:     "i-am-Sandeep-2",
+			wantProblems: []string{"it must not contain your username"},
+		},
+		{
+			name:         "contains the email local part",
+			password:     "sandy.s.secret",
+			wantProblems: []string{"it must not contain your email address"},
+		},
+		{
+			name:         "breached password",
+			password:     "P@ssw0rd",
+			wantProblems: []string{"it appears in a list of breached passwords, please choose another one"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newPasswordPolicy(tt.cfg)
+			if err != nil {
+				t.Fatalf("newPasswordPolicy() error = %v", err)
+			}
+			err = p.check("password", tt.password, user)
+			if tt.wantProblems == nil {
+				if err != nil {
+					t.Errorf("check() error = %v, want nil", err)
+				}
+				return
+			}
+			var passwordErr *PasswordError
+			if !errors.As(err, &passwordErr) || !errors.Is(err, ErrWeakPassword) {
+				t.Fatalf("check() error = %v, want a PasswordError", err)
+			}
+			if passwordErr.Field != "password" {
+				t.Errorf("check() field = %v, want password", passwordErr.Field)
+			}
+			if !reflect.DeepEqual(passwordErr.Problems, tt.wantProblems) {
+				t.Errorf("check() problems = %v, want %v", passwordErr.Problems, tt.wantProblems)
+			}
+		})
+	}
+}
+
+func Test_newPasswordPolicy_breachedListFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	err := os.WriteFile(path, []byte("# local additions\n\nCompanyName2026\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := newPasswordPolicy(config.PasswordConfig{BreachedListFile: path})
+	if err != nil {
+		t.Fatalf("newPasswordPolicy() error = %v", err)
+	}
+	if err := p.check("password", "companyname2026", models.User{}); !errors.Is(err, ErrWeakPassword) {
+		t.Errorf("check() error = %v, want ErrWeakPassword", err)
+	}
+
+	_, err = newPasswordPolicy(config.PasswordConfig{BreachedListFile: filepath.Join(t.TempDir(), "missing.txt")})
+	if err == nil {
+		t.Error("newPasswordPolicy() with a missing file should fail")
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/repository"
+)
+
+// revocationPrune is how often the expired entries of the token revocation list are dropped.
+const revocationPrune = time.Hour
+
+// RevocationPruner keeps the access token revocation list small. A revoked token only has
+// to stay listed until it expires, after that it is rejected for its expiry.
+type RevocationPruner struct {
+	repo repository.UserRepo
+}
+
+func NewRevocationPruner(repo repository.UserRepo) (*RevocationPruner, error) {
+	if repo == nil {
+		return nil, errors.New("the revocation pruner needs a repository")
+	}
+	return &RevocationPruner{repo: repo}, nil
+}
+
+// Run prunes the revocation list every hour until ctx is cancelled.
+func (p *RevocationPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(revocationPrune)
+	defer ticker.Stop()
+	for {
+		n, err := p.repo.DeleteExpiredRevokedTokens(ctx, time.Now())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to prune revoked tokens")
+		} else if n > 0 {
+			log.Info().Int64("deleted", n).Msg("pruned revoked tokens")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
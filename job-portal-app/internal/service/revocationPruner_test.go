@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestRevocationPruner_Run(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "expired tokens deleted"},
+		{name: "error from db", err: errors.New("test error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			start := time.Now()
+			mockRepo.EXPECT().DeleteExpiredRevokedTokens(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+				if before.Before(start) || before.After(time.Now()) {
+					t.Errorf("DeleteExpiredRevokedTokens() before = %v, want the current time", before)
+				}
+				return 3, tt.err
+			}).Times(1)
+			p, err := NewRevocationPruner(mockRepo)
+			if err != nil {
+				t.Fatalf("NewRevocationPruner() error = %v", err)
+			}
+			// a cancelled context stops the pruner after its first pass
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			p.Run(ctx)
+		})
+	}
+}
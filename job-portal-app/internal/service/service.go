@@ -2,42 +2,106 @@ package service
 
 import (
 	"context"
+	"errors"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/redis"
 	"job-portal-api/internal/repository"
 )
 
 type Service struct {
-	UserRepo repository.UserRepo
-	auth     auth.Authentication
-	rdb      *redis.RedisClient
+	UserRepo  repository.UserRepo
+	auth      auth.Authentication
+	rdb       redis.Redis
+	cfg       config.Config
+	passwords passwordPolicy
+	oidc      map[string]*oidcProvider
 }
 
 //go:generate mockgen -source=service.go -destination=service_mock.go -package=service
 type UserService interface {
 	RegisterUserService(ctx context.Context, userData models.NewUser) (models.User, error)
-	UserLoginService(ctx context.Context, userData models.NewUser) (string, error)
-	CreateCompanyService(ctx context.Context, companyData models.Company) (models.Company, error)
+	UserLoginService(ctx context.Context, userData models.NewUser) (models.TokenPair, error)
+	RefreshTokenService(ctx context.Context, refreshToken string) (models.TokenPair, error)
+	LogoutService(ctx context.Context, claims auth.Claims) error
+	LogoutAllService(ctx context.Context, uid uint) error
+	CreateCompanyService(ctx context.Context, actor models.Actor, companyData models.Company) (models.Company, error)
 	ListCompaniesService(ctx context.Context) ([]models.Company, error)
 	GetCompanyService(ctx context.Context, cid uint64) (models.Company, error)
 	ListJobsForCompanyService(ctx context.Context, cid uint64) ([]models.Jobs, error)
-	CreateJobPostingService(ctx context.Context, jobData models.NewJobRequest, cid uint64) (models.NewJobResponse, error)
+	CreateJobPostingService(ctx context.Context, actor models.Actor, jobData models.NewJobRequest, cid uint64) (models.NewJobResponse, error)
+	UpdateJobPostingService(ctx context.Context, actor models.Actor, jid uint64, jobData models.NewJobRequest) (models.Jobs, error)
+	CloseJobPostingService(ctx context.Context, actor models.Actor, jid uint64) (models.Jobs, error)
 	GetAllJobPostingsService(ctx context.Context) ([]models.Jobs, error)
 	GetJobPostingByIDService(ctx context.Context, jid uint64) (models.Jobs, error)
-	ApplicationProcessor(ctx context.Context, job []models.RequestJob) ([]models.RequestJob, error)
+	ApplicationProcessor(ctx context.Context, actor models.Actor, job []models.RequestJob) ([]models.RequestJob, error)
+	ExplainJobApplicationService(ctx context.Context, jid uint64, application models.RequestJob) (models.ApplicationEvaluation, error)
 	ForgetPasswordService(ctx context.Context, data models.ForgetPasswordRequest) (models.ForgetPasswordResponse, error)
-	VerifyOTPService(ctx context.Context, email string, otp string) error
-	UpdatePasswordService(ctx context.Context, email string, newPass string) error
-	VerifyOldPassword(ctx context.Context, email string, oldPass string) error
+	VerifyOTPService(ctx context.Context, data models.VerifyOTPRequest) (models.VerifyOTPResponse, error)
+	ResetPasswordService(ctx context.Context, data models.ResetPasswordRequest) error
+	VerifyEmailService(ctx context.Context, token string) error
+	ResendVerificationService(ctx context.Context, email string) error
+	VerifyTwoFactorLoginService(ctx context.Context, data models.TwoFactorLoginRequest) (models.TokenPair, error)
+	EnrolTOTPService(ctx context.Context, uid uint, password string) (models.TOTPEnrolment, error)
+	ConfirmTOTPService(ctx context.Context, uid uint, code string) (models.RecoveryCodes, error)
+	DisableTOTPService(ctx context.Context, uid uint, password string) error
+	StartOIDCLoginService(ctx context.Context, provider string) (string, error)
+	OIDCCallbackService(ctx context.Context, provider, code, state string) (models.TokenPair, error)
+	GetProfileService(ctx context.Context, uid uint) (models.User, error)
+	UpdateProfileService(ctx context.Context, uid uint, data models.UpdateProfileRequest) (models.User, error)
+	ExportAccountService(ctx context.Context, uid uint) (models.AccountExport, error)
+	DeleteAccountService(ctx context.Context, uid uint, password string) error
+	ChangePasswordService(ctx context.Context, uid uint, sessionID string, data models.ChangePasswordRequest) error
+	GrantRoleService(ctx context.Context, uid uint64, role string) (models.User, error)
+	RevokeRoleService(ctx context.Context, uid uint64) (models.User, error)
+	BootstrapAdminsService(ctx context.Context, emails []string) error
+	ListCompanyMembersService(ctx context.Context, actor models.Actor, cid uint64) ([]models.CompanyMember, error)
+	AddCompanyMemberService(ctx context.Context, actor models.Actor, cid uint64, memberData models.NewMemberRequest) (models.CompanyMember, error)
+	RemoveCompanyMemberService(ctx context.Context, actor models.Actor, cid uint64, uid uint) error
+	CreateAPIKeyService(ctx context.Context, actor models.Actor, cid uint64, keyData models.NewAPIKeyRequest) (models.NewAPIKeyResponse, error)
+	ListAPIKeysService(ctx context.Context, actor models.Actor, cid uint64) ([]models.APIKey, error)
+	RevokeAPIKeyService(ctx context.Context, actor models.Actor, cid uint64, keyID uint) error
+	CreateWebhookEndpointService(ctx context.Context, actor models.Actor, cid uint64, data models.NewWebhookEndpointRequest) (models.NewWebhookEndpointResponse, error)
+	ListWebhookEndpointsService(ctx context.Context, actor models.Actor, cid uint64) ([]models.WebhookEndpoint, error)
+	DeleteWebhookEndpointService(ctx context.Context, actor models.Actor, cid uint64, id uint) error
+	ListWebhookDeliveriesService(ctx context.Context, actor models.Actor, cid uint64, id uint, limit int) ([]models.WebhookDelivery, error)
+	SendTestWebhookService(ctx context.Context, actor models.Actor, cid uint64, id uint) error
+	ListNotificationsService(ctx context.Context, uid uint, query models.NotificationQuery) (models.NotificationPage, error)
+	UnreadNotificationCountService(ctx context.Context, uid uint) (int64, error)
+	MarkNotificationReadService(ctx context.Context, uid uint, id uint) error
+	MarkAllNotificationsReadService(ctx context.Context, uid uint) (int64, error)
+	GetJobPreferenceService(ctx context.Context, uid uint) (models.JobPreference, error)
+	SaveJobPreferenceService(ctx context.Context, uid uint, criteria models.RequestJob) (models.JobPreference, error)
+	InviteToInterviewService(ctx context.Context, actor models.Actor, jid uint64, invite models.InterviewInviteRequest) error
+	ValidateAPIKey(ctx context.Context, key string) (auth.Claims, error)
+	RecordLockoutEvent(ctx context.Context, event models.LockoutEvent) error
+	ListLockoutEventsService(ctx context.Context, limit int) ([]models.LockoutEvent, error)
+	ListAuditEventsService(ctx context.Context, query models.AuditQuery) ([]models.AuditEvent, error)
+	ListOutboxMessagesService(ctx context.Context, status string, limit int) ([]models.OutboxMessage, error)
+	ReplayOutboxMessageService(ctx context.Context, id uint) error
 }
 
 // NewService creates a new UserService with the provided user repository and authentication service.
 // It returns a UserService and an error if the user repository is nil.
-func NewService(userRepo repository.UserRepo, a auth.Authentication, rdb *redis.RedisClient) (UserService, error) {
+func NewService(userRepo repository.UserRepo, a auth.Authentication, rdb redis.Redis, cfg config.Config) (UserService, error) {
+	if cfg.AuthConfig.EmailVerification != config.EmailVerificationOff && cfg.AuthConfig.EmailVerificationSecret == "" {
+		return nil, errors.New("email verification requires EMAIL_VERIFICATION_SECRET")
+	}
+	passwords, err := newPasswordPolicy(cfg.PasswordConfig)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := newOIDCProviders(cfg.OIDCConfig, cfg.AppConfig.BaseURL)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
-		UserRepo: userRepo,
-		auth:     a,
-		rdb:      rdb,
+		UserRepo:  userRepo,
+		auth:      a,
+		rdb:       rdb,
+		cfg:       cfg,
+		passwords: passwords,
+		oidc:      providers,
 	}, nil
 }
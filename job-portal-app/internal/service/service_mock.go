@@ -10,6 +10,7 @@ package service
 
 import (
 	context "context"
+	auth "job-portal-api/internal/auth"
 	models "job-portal-api/internal/models"
 	reflect "reflect"
 
@@ -39,49 +40,239 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// AddCompanyMemberService mocks base method.
+func (m *MockUserService) AddCompanyMemberService(ctx context.Context, actor models.Actor, cid uint64, memberData models.NewMemberRequest) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCompanyMemberService", ctx, actor, cid, memberData)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCompanyMemberService indicates an expected call of AddCompanyMemberService.
+func (mr *MockUserServiceMockRecorder) AddCompanyMemberService(ctx, actor, cid, memberData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyMemberService", reflect.TypeOf((*MockUserService)(nil).AddCompanyMemberService), ctx, actor, cid, memberData)
+}
+
 // ApplicationProcessor mocks base method.
-func (m *MockUserService) ApplicationProcessor(ctx context.Context, job []models.RequestJob) ([]models.RequestJob, error) {
+func (m *MockUserService) ApplicationProcessor(ctx context.Context, actor models.Actor, job []models.RequestJob) ([]models.RequestJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ApplicationProcessor", ctx, job)
+	ret := m.ctrl.Call(m, "ApplicationProcessor", ctx, actor, job)
 	ret0, _ := ret[0].([]models.RequestJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ApplicationProcessor indicates an expected call of ApplicationProcessor.
-func (mr *MockUserServiceMockRecorder) ApplicationProcessor(ctx, job any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) ApplicationProcessor(ctx, actor, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationProcessor", reflect.TypeOf((*MockUserService)(nil).ApplicationProcessor), ctx, actor, job)
+}
+
+// BootstrapAdminsService mocks base method.
+func (m *MockUserService) BootstrapAdminsService(ctx context.Context, emails []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapAdminsService", ctx, emails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BootstrapAdminsService indicates an expected call of BootstrapAdminsService.
+func (mr *MockUserServiceMockRecorder) BootstrapAdminsService(ctx, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapAdminsService", reflect.TypeOf((*MockUserService)(nil).BootstrapAdminsService), ctx, emails)
+}
+
+// ChangePasswordService mocks base method.
+func (m *MockUserService) ChangePasswordService(ctx context.Context, uid uint, sessionID string, data models.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePasswordService", ctx, uid, sessionID, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePasswordService indicates an expected call of ChangePasswordService.
+func (mr *MockUserServiceMockRecorder) ChangePasswordService(ctx, uid, sessionID, data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationProcessor", reflect.TypeOf((*MockUserService)(nil).ApplicationProcessor), ctx, job)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePasswordService", reflect.TypeOf((*MockUserService)(nil).ChangePasswordService), ctx, uid, sessionID, data)
+}
+
+// CloseJobPostingService mocks base method.
+func (m *MockUserService) CloseJobPostingService(ctx context.Context, actor models.Actor, jid uint64) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseJobPostingService", ctx, actor, jid)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseJobPostingService indicates an expected call of CloseJobPostingService.
+func (mr *MockUserServiceMockRecorder) CloseJobPostingService(ctx, actor, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseJobPostingService", reflect.TypeOf((*MockUserService)(nil).CloseJobPostingService), ctx, actor, jid)
+}
+
+// ConfirmTOTPService mocks base method.
+func (m *MockUserService) ConfirmTOTPService(ctx context.Context, uid uint, code string) (models.RecoveryCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTPService", ctx, uid, code)
+	ret0, _ := ret[0].(models.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTPService indicates an expected call of ConfirmTOTPService.
+func (mr *MockUserServiceMockRecorder) ConfirmTOTPService(ctx, uid, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTPService", reflect.TypeOf((*MockUserService)(nil).ConfirmTOTPService), ctx, uid, code)
+}
+
+// CreateAPIKeyService mocks base method.
+func (m *MockUserService) CreateAPIKeyService(ctx context.Context, actor models.Actor, cid uint64, keyData models.NewAPIKeyRequest) (models.NewAPIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKeyService", ctx, actor, cid, keyData)
+	ret0, _ := ret[0].(models.NewAPIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKeyService indicates an expected call of CreateAPIKeyService.
+func (mr *MockUserServiceMockRecorder) CreateAPIKeyService(ctx, actor, cid, keyData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKeyService", reflect.TypeOf((*MockUserService)(nil).CreateAPIKeyService), ctx, actor, cid, keyData)
 }
 
 // CreateCompanyService mocks base method.
-func (m *MockUserService) CreateCompanyService(ctx context.Context, companyData models.Company) (models.Company, error) {
+func (m *MockUserService) CreateCompanyService(ctx context.Context, actor models.Actor, companyData models.Company) (models.Company, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCompanyService", ctx, companyData)
+	ret := m.ctrl.Call(m, "CreateCompanyService", ctx, actor, companyData)
 	ret0, _ := ret[0].(models.Company)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateCompanyService indicates an expected call of CreateCompanyService.
-func (mr *MockUserServiceMockRecorder) CreateCompanyService(ctx, companyData any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) CreateCompanyService(ctx, actor, companyData any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompanyService", reflect.TypeOf((*MockUserService)(nil).CreateCompanyService), ctx, companyData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompanyService", reflect.TypeOf((*MockUserService)(nil).CreateCompanyService), ctx, actor, companyData)
 }
 
 // CreateJobPostingService mocks base method.
-func (m *MockUserService) CreateJobPostingService(ctx context.Context, jobData models.NewJobRequest, cid uint64) (models.NewJobResponse, error) {
+func (m *MockUserService) CreateJobPostingService(ctx context.Context, actor models.Actor, jobData models.NewJobRequest, cid uint64) (models.NewJobResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateJobPostingService", ctx, jobData, cid)
+	ret := m.ctrl.Call(m, "CreateJobPostingService", ctx, actor, jobData, cid)
 	ret0, _ := ret[0].(models.NewJobResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateJobPostingService indicates an expected call of CreateJobPostingService.
-func (mr *MockUserServiceMockRecorder) CreateJobPostingService(ctx, jobData, cid any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) CreateJobPostingService(ctx, actor, jobData, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobPostingService", reflect.TypeOf((*MockUserService)(nil).CreateJobPostingService), ctx, actor, jobData, cid)
+}
+
+// CreateWebhookEndpointService mocks base method.
+func (m *MockUserService) CreateWebhookEndpointService(ctx context.Context, actor models.Actor, cid uint64, data models.NewWebhookEndpointRequest) (models.NewWebhookEndpointResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpointService", ctx, actor, cid, data)
+	ret0, _ := ret[0].(models.NewWebhookEndpointResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpointService indicates an expected call of CreateWebhookEndpointService.
+func (mr *MockUserServiceMockRecorder) CreateWebhookEndpointService(ctx, actor, cid, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpointService", reflect.TypeOf((*MockUserService)(nil).CreateWebhookEndpointService), ctx, actor, cid, data)
+}
+
+// DeleteAccountService mocks base method.
+func (m *MockUserService) DeleteAccountService(ctx context.Context, uid uint, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountService", ctx, uid, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountService indicates an expected call of DeleteAccountService.
+func (mr *MockUserServiceMockRecorder) DeleteAccountService(ctx, uid, password any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobPostingService", reflect.TypeOf((*MockUserService)(nil).CreateJobPostingService), ctx, jobData, cid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountService", reflect.TypeOf((*MockUserService)(nil).DeleteAccountService), ctx, uid, password)
+}
+
+// DeleteWebhookEndpointService mocks base method.
+func (m *MockUserService) DeleteWebhookEndpointService(ctx context.Context, actor models.Actor, cid uint64, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpointService", ctx, actor, cid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpointService indicates an expected call of DeleteWebhookEndpointService.
+func (mr *MockUserServiceMockRecorder) DeleteWebhookEndpointService(ctx, actor, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpointService", reflect.TypeOf((*MockUserService)(nil).DeleteWebhookEndpointService), ctx, actor, cid, id)
+}
+
+// DisableTOTPService mocks base method.
+func (m *MockUserService) DisableTOTPService(ctx context.Context, uid uint, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTPService", ctx, uid, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTPService indicates an expected call of DisableTOTPService.
+func (mr *MockUserServiceMockRecorder) DisableTOTPService(ctx, uid, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTPService", reflect.TypeOf((*MockUserService)(nil).DisableTOTPService), ctx, uid, password)
+}
+
+// EnrolTOTPService mocks base method.
+func (m *MockUserService) EnrolTOTPService(ctx context.Context, uid uint, password string) (models.TOTPEnrolment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrolTOTPService", ctx, uid, password)
+	ret0, _ := ret[0].(models.TOTPEnrolment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrolTOTPService indicates an expected call of EnrolTOTPService.
+func (mr *MockUserServiceMockRecorder) EnrolTOTPService(ctx, uid, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrolTOTPService", reflect.TypeOf((*MockUserService)(nil).EnrolTOTPService), ctx, uid, password)
+}
+
+// ExplainJobApplicationService mocks base method.
+func (m *MockUserService) ExplainJobApplicationService(ctx context.Context, jid uint64, application models.RequestJob) (models.ApplicationEvaluation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainJobApplicationService", ctx, jid, application)
+	ret0, _ := ret[0].(models.ApplicationEvaluation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainJobApplicationService indicates an expected call of ExplainJobApplicationService.
+func (mr *MockUserServiceMockRecorder) ExplainJobApplicationService(ctx, jid, application any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainJobApplicationService", reflect.TypeOf((*MockUserService)(nil).ExplainJobApplicationService), ctx, jid, application)
+}
+
+// ExportAccountService mocks base method.
+func (m *MockUserService) ExportAccountService(ctx context.Context, uid uint) (models.AccountExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAccountService", ctx, uid)
+	ret0, _ := ret[0].(models.AccountExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportAccountService indicates an expected call of ExportAccountService.
+func (mr *MockUserServiceMockRecorder) ExportAccountService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccountService", reflect.TypeOf((*MockUserService)(nil).ExportAccountService), ctx, uid)
 }
 
 // ForgetPasswordService mocks base method.
@@ -144,6 +335,95 @@ func (mr *MockUserServiceMockRecorder) GetJobPostingByIDService(ctx, jid any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobPostingByIDService", reflect.TypeOf((*MockUserService)(nil).GetJobPostingByIDService), ctx, jid)
 }
 
+// GetJobPreferenceService mocks base method.
+func (m *MockUserService) GetJobPreferenceService(ctx context.Context, uid uint) (models.JobPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobPreferenceService", ctx, uid)
+	ret0, _ := ret[0].(models.JobPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobPreferenceService indicates an expected call of GetJobPreferenceService.
+func (mr *MockUserServiceMockRecorder) GetJobPreferenceService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobPreferenceService", reflect.TypeOf((*MockUserService)(nil).GetJobPreferenceService), ctx, uid)
+}
+
+// GetProfileService mocks base method.
+func (m *MockUserService) GetProfileService(ctx context.Context, uid uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfileService", ctx, uid)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfileService indicates an expected call of GetProfileService.
+func (mr *MockUserServiceMockRecorder) GetProfileService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfileService", reflect.TypeOf((*MockUserService)(nil).GetProfileService), ctx, uid)
+}
+
+// GrantRoleService mocks base method.
+func (m *MockUserService) GrantRoleService(ctx context.Context, uid uint64, role string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantRoleService", ctx, uid, role)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantRoleService indicates an expected call of GrantRoleService.
+func (mr *MockUserServiceMockRecorder) GrantRoleService(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRoleService", reflect.TypeOf((*MockUserService)(nil).GrantRoleService), ctx, uid, role)
+}
+
+// InviteToInterviewService mocks base method.
+func (m *MockUserService) InviteToInterviewService(ctx context.Context, actor models.Actor, jid uint64, invite models.InterviewInviteRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteToInterviewService", ctx, actor, jid, invite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InviteToInterviewService indicates an expected call of InviteToInterviewService.
+func (mr *MockUserServiceMockRecorder) InviteToInterviewService(ctx, actor, jid, invite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteToInterviewService", reflect.TypeOf((*MockUserService)(nil).InviteToInterviewService), ctx, actor, jid, invite)
+}
+
+// ListAPIKeysService mocks base method.
+func (m *MockUserService) ListAPIKeysService(ctx context.Context, actor models.Actor, cid uint64) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeysService", ctx, actor, cid)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeysService indicates an expected call of ListAPIKeysService.
+func (mr *MockUserServiceMockRecorder) ListAPIKeysService(ctx, actor, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeysService", reflect.TypeOf((*MockUserService)(nil).ListAPIKeysService), ctx, actor, cid)
+}
+
+// ListAuditEventsService mocks base method.
+func (m *MockUserService) ListAuditEventsService(ctx context.Context, query models.AuditQuery) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEventsService", ctx, query)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEventsService indicates an expected call of ListAuditEventsService.
+func (mr *MockUserServiceMockRecorder) ListAuditEventsService(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEventsService", reflect.TypeOf((*MockUserService)(nil).ListAuditEventsService), ctx, query)
+}
+
 // ListCompaniesService mocks base method.
 func (m *MockUserService) ListCompaniesService(ctx context.Context) ([]models.Company, error) {
 	m.ctrl.T.Helper()
@@ -159,6 +439,21 @@ func (mr *MockUserServiceMockRecorder) ListCompaniesService(ctx any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCompaniesService", reflect.TypeOf((*MockUserService)(nil).ListCompaniesService), ctx)
 }
 
+// ListCompanyMembersService mocks base method.
+func (m *MockUserService) ListCompanyMembersService(ctx context.Context, actor models.Actor, cid uint64) ([]models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCompanyMembersService", ctx, actor, cid)
+	ret0, _ := ret[0].([]models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCompanyMembersService indicates an expected call of ListCompanyMembersService.
+func (mr *MockUserServiceMockRecorder) ListCompanyMembersService(ctx, actor, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCompanyMembersService", reflect.TypeOf((*MockUserService)(nil).ListCompanyMembersService), ctx, actor, cid)
+}
+
 // ListJobsForCompanyService mocks base method.
 func (m *MockUserService) ListJobsForCompanyService(ctx context.Context, cid uint64) ([]models.Jobs, error) {
 	m.ctrl.T.Helper()
@@ -174,6 +469,182 @@ func (mr *MockUserServiceMockRecorder) ListJobsForCompanyService(ctx, cid any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobsForCompanyService", reflect.TypeOf((*MockUserService)(nil).ListJobsForCompanyService), ctx, cid)
 }
 
+// ListLockoutEventsService mocks base method.
+func (m *MockUserService) ListLockoutEventsService(ctx context.Context, limit int) ([]models.LockoutEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLockoutEventsService", ctx, limit)
+	ret0, _ := ret[0].([]models.LockoutEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLockoutEventsService indicates an expected call of ListLockoutEventsService.
+func (mr *MockUserServiceMockRecorder) ListLockoutEventsService(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLockoutEventsService", reflect.TypeOf((*MockUserService)(nil).ListLockoutEventsService), ctx, limit)
+}
+
+// ListNotificationsService mocks base method.
+func (m *MockUserService) ListNotificationsService(ctx context.Context, uid uint, query models.NotificationQuery) (models.NotificationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationsService", ctx, uid, query)
+	ret0, _ := ret[0].(models.NotificationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationsService indicates an expected call of ListNotificationsService.
+func (mr *MockUserServiceMockRecorder) ListNotificationsService(ctx, uid, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationsService", reflect.TypeOf((*MockUserService)(nil).ListNotificationsService), ctx, uid, query)
+}
+
+// ListOutboxMessagesService mocks base method.
+func (m *MockUserService) ListOutboxMessagesService(ctx context.Context, status string, limit int) ([]models.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxMessagesService", ctx, status, limit)
+	ret0, _ := ret[0].([]models.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxMessagesService indicates an expected call of ListOutboxMessagesService.
+func (mr *MockUserServiceMockRecorder) ListOutboxMessagesService(ctx, status, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxMessagesService", reflect.TypeOf((*MockUserService)(nil).ListOutboxMessagesService), ctx, status, limit)
+}
+
+// ListWebhookDeliveriesService mocks base method.
+func (m *MockUserService) ListWebhookDeliveriesService(ctx context.Context, actor models.Actor, cid uint64, id uint, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveriesService", ctx, actor, cid, id, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveriesService indicates an expected call of ListWebhookDeliveriesService.
+func (mr *MockUserServiceMockRecorder) ListWebhookDeliveriesService(ctx, actor, cid, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveriesService", reflect.TypeOf((*MockUserService)(nil).ListWebhookDeliveriesService), ctx, actor, cid, id, limit)
+}
+
+// ListWebhookEndpointsService mocks base method.
+func (m *MockUserService) ListWebhookEndpointsService(ctx context.Context, actor models.Actor, cid uint64) ([]models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpointsService", ctx, actor, cid)
+	ret0, _ := ret[0].([]models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpointsService indicates an expected call of ListWebhookEndpointsService.
+func (mr *MockUserServiceMockRecorder) ListWebhookEndpointsService(ctx, actor, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpointsService", reflect.TypeOf((*MockUserService)(nil).ListWebhookEndpointsService), ctx, actor, cid)
+}
+
+// LogoutAllService mocks base method.
+func (m *MockUserService) LogoutAllService(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAllService", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAllService indicates an expected call of LogoutAllService.
+func (mr *MockUserServiceMockRecorder) LogoutAllService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAllService", reflect.TypeOf((*MockUserService)(nil).LogoutAllService), ctx, uid)
+}
+
+// LogoutService mocks base method.
+func (m *MockUserService) LogoutService(ctx context.Context, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutService", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutService indicates an expected call of LogoutService.
+func (mr *MockUserServiceMockRecorder) LogoutService(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutService", reflect.TypeOf((*MockUserService)(nil).LogoutService), ctx, claims)
+}
+
+// MarkAllNotificationsReadService mocks base method.
+func (m *MockUserService) MarkAllNotificationsReadService(ctx context.Context, uid uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsReadService", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsReadService indicates an expected call of MarkAllNotificationsReadService.
+func (mr *MockUserServiceMockRecorder) MarkAllNotificationsReadService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsReadService", reflect.TypeOf((*MockUserService)(nil).MarkAllNotificationsReadService), ctx, uid)
+}
+
+// MarkNotificationReadService mocks base method.
+func (m *MockUserService) MarkNotificationReadService(ctx context.Context, uid, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationReadService", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationReadService indicates an expected call of MarkNotificationReadService.
+func (mr *MockUserServiceMockRecorder) MarkNotificationReadService(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationReadService", reflect.TypeOf((*MockUserService)(nil).MarkNotificationReadService), ctx, uid, id)
+}
+
+// OIDCCallbackService mocks base method.
+func (m *MockUserService) OIDCCallbackService(ctx context.Context, provider, code, state string) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCCallbackService", ctx, provider, code, state)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCCallbackService indicates an expected call of OIDCCallbackService.
+func (mr *MockUserServiceMockRecorder) OIDCCallbackService(ctx, provider, code, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCCallbackService", reflect.TypeOf((*MockUserService)(nil).OIDCCallbackService), ctx, provider, code, state)
+}
+
+// RecordLockoutEvent mocks base method.
+func (m *MockUserService) RecordLockoutEvent(ctx context.Context, event models.LockoutEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLockoutEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLockoutEvent indicates an expected call of RecordLockoutEvent.
+func (mr *MockUserServiceMockRecorder) RecordLockoutEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLockoutEvent", reflect.TypeOf((*MockUserService)(nil).RecordLockoutEvent), ctx, event)
+}
+
+// RefreshTokenService mocks base method.
+func (m *MockUserService) RefreshTokenService(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokenService", ctx, refreshToken)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokenService indicates an expected call of RefreshTokenService.
+func (mr *MockUserServiceMockRecorder) RefreshTokenService(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenService", reflect.TypeOf((*MockUserService)(nil).RefreshTokenService), ctx, refreshToken)
+}
+
 // RegisterUserService mocks base method.
 func (m *MockUserService) RegisterUserService(ctx context.Context, userData models.NewUser) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -189,25 +660,185 @@ func (mr *MockUserServiceMockRecorder) RegisterUserService(ctx, userData any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUserService", reflect.TypeOf((*MockUserService)(nil).RegisterUserService), ctx, userData)
 }
 
-// UpdatePasswordService mocks base method.
-func (m *MockUserService) UpdatePasswordService(ctx context.Context, email, newPass string) error {
+// RemoveCompanyMemberService mocks base method.
+func (m *MockUserService) RemoveCompanyMemberService(ctx context.Context, actor models.Actor, cid uint64, uid uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePasswordService", ctx, email, newPass)
+	ret := m.ctrl.Call(m, "RemoveCompanyMemberService", ctx, actor, cid, uid)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdatePasswordService indicates an expected call of UpdatePasswordService.
-func (mr *MockUserServiceMockRecorder) UpdatePasswordService(ctx, email, newPass any) *gomock.Call {
+// RemoveCompanyMemberService indicates an expected call of RemoveCompanyMemberService.
+func (mr *MockUserServiceMockRecorder) RemoveCompanyMemberService(ctx, actor, cid, uid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordService", reflect.TypeOf((*MockUserService)(nil).UpdatePasswordService), ctx, email, newPass)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCompanyMemberService", reflect.TypeOf((*MockUserService)(nil).RemoveCompanyMemberService), ctx, actor, cid, uid)
+}
+
+// ReplayOutboxMessageService mocks base method.
+func (m *MockUserService) ReplayOutboxMessageService(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayOutboxMessageService", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayOutboxMessageService indicates an expected call of ReplayOutboxMessageService.
+func (mr *MockUserServiceMockRecorder) ReplayOutboxMessageService(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayOutboxMessageService", reflect.TypeOf((*MockUserService)(nil).ReplayOutboxMessageService), ctx, id)
+}
+
+// ResendVerificationService mocks base method.
+func (m *MockUserService) ResendVerificationService(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerificationService", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerificationService indicates an expected call of ResendVerificationService.
+func (mr *MockUserServiceMockRecorder) ResendVerificationService(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerificationService", reflect.TypeOf((*MockUserService)(nil).ResendVerificationService), ctx, email)
+}
+
+// ResetPasswordService mocks base method.
+func (m *MockUserService) ResetPasswordService(ctx context.Context, data models.ResetPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordService", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPasswordService indicates an expected call of ResetPasswordService.
+func (mr *MockUserServiceMockRecorder) ResetPasswordService(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordService", reflect.TypeOf((*MockUserService)(nil).ResetPasswordService), ctx, data)
+}
+
+// RevokeAPIKeyService mocks base method.
+func (m *MockUserService) RevokeAPIKeyService(ctx context.Context, actor models.Actor, cid uint64, keyID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKeyService", ctx, actor, cid, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKeyService indicates an expected call of RevokeAPIKeyService.
+func (mr *MockUserServiceMockRecorder) RevokeAPIKeyService(ctx, actor, cid, keyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKeyService", reflect.TypeOf((*MockUserService)(nil).RevokeAPIKeyService), ctx, actor, cid, keyID)
+}
+
+// RevokeRoleService mocks base method.
+func (m *MockUserService) RevokeRoleService(ctx context.Context, uid uint64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRoleService", ctx, uid)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeRoleService indicates an expected call of RevokeRoleService.
+func (mr *MockUserServiceMockRecorder) RevokeRoleService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRoleService", reflect.TypeOf((*MockUserService)(nil).RevokeRoleService), ctx, uid)
+}
+
+// SaveJobPreferenceService mocks base method.
+func (m *MockUserService) SaveJobPreferenceService(ctx context.Context, uid uint, criteria models.RequestJob) (models.JobPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveJobPreferenceService", ctx, uid, criteria)
+	ret0, _ := ret[0].(models.JobPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveJobPreferenceService indicates an expected call of SaveJobPreferenceService.
+func (mr *MockUserServiceMockRecorder) SaveJobPreferenceService(ctx, uid, criteria any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveJobPreferenceService", reflect.TypeOf((*MockUserService)(nil).SaveJobPreferenceService), ctx, uid, criteria)
+}
+
+// SendTestWebhookService mocks base method.
+func (m *MockUserService) SendTestWebhookService(ctx context.Context, actor models.Actor, cid uint64, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTestWebhookService", ctx, actor, cid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendTestWebhookService indicates an expected call of SendTestWebhookService.
+func (mr *MockUserServiceMockRecorder) SendTestWebhookService(ctx, actor, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTestWebhookService", reflect.TypeOf((*MockUserService)(nil).SendTestWebhookService), ctx, actor, cid, id)
+}
+
+// StartOIDCLoginService mocks base method.
+func (m *MockUserService) StartOIDCLoginService(ctx context.Context, provider string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartOIDCLoginService", ctx, provider)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartOIDCLoginService indicates an expected call of StartOIDCLoginService.
+func (mr *MockUserServiceMockRecorder) StartOIDCLoginService(ctx, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartOIDCLoginService", reflect.TypeOf((*MockUserService)(nil).StartOIDCLoginService), ctx, provider)
+}
+
+// UnreadNotificationCountService mocks base method.
+func (m *MockUserService) UnreadNotificationCountService(ctx context.Context, uid uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnreadNotificationCountService", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnreadNotificationCountService indicates an expected call of UnreadNotificationCountService.
+func (mr *MockUserServiceMockRecorder) UnreadNotificationCountService(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreadNotificationCountService", reflect.TypeOf((*MockUserService)(nil).UnreadNotificationCountService), ctx, uid)
+}
+
+// UpdateJobPostingService mocks base method.
+func (m *MockUserService) UpdateJobPostingService(ctx context.Context, actor models.Actor, jid uint64, jobData models.NewJobRequest) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobPostingService", ctx, actor, jid, jobData)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJobPostingService indicates an expected call of UpdateJobPostingService.
+func (mr *MockUserServiceMockRecorder) UpdateJobPostingService(ctx, actor, jid, jobData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobPostingService", reflect.TypeOf((*MockUserService)(nil).UpdateJobPostingService), ctx, actor, jid, jobData)
+}
+
+// UpdateProfileService mocks base method.
+func (m *MockUserService) UpdateProfileService(ctx context.Context, uid uint, data models.UpdateProfileRequest) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfileService", ctx, uid, data)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfileService indicates an expected call of UpdateProfileService.
+func (mr *MockUserServiceMockRecorder) UpdateProfileService(ctx, uid, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfileService", reflect.TypeOf((*MockUserService)(nil).UpdateProfileService), ctx, uid, data)
 }
 
 // UserLoginService mocks base method.
-func (m *MockUserService) UserLoginService(ctx context.Context, userData models.NewUser) (string, error) {
+func (m *MockUserService) UserLoginService(ctx context.Context, userData models.NewUser) (models.TokenPair, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UserLoginService", ctx, userData)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(models.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -218,16 +849,61 @@ func (mr *MockUserServiceMockRecorder) UserLoginService(ctx, userData any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserLoginService", reflect.TypeOf((*MockUserService)(nil).UserLoginService), ctx, userData)
 }
 
-// VerifyOTPService mocks base method.
-func (m *MockUserService) VerifyOTPService(ctx context.Context, email, otp string) error {
+// ValidateAPIKey mocks base method.
+func (m *MockUserService) ValidateAPIKey(ctx context.Context, key string) (auth.Claims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyOTPService", ctx, email, otp)
+	ret := m.ctrl.Call(m, "ValidateAPIKey", ctx, key)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateAPIKey indicates an expected call of ValidateAPIKey.
+func (mr *MockUserServiceMockRecorder) ValidateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAPIKey", reflect.TypeOf((*MockUserService)(nil).ValidateAPIKey), ctx, key)
+}
+
+// VerifyEmailService mocks base method.
+func (m *MockUserService) VerifyEmailService(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailService", ctx, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// VerifyEmailService indicates an expected call of VerifyEmailService.
+func (mr *MockUserServiceMockRecorder) VerifyEmailService(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailService", reflect.TypeOf((*MockUserService)(nil).VerifyEmailService), ctx, token)
+}
+
+// VerifyOTPService mocks base method.
+func (m *MockUserService) VerifyOTPService(ctx context.Context, data models.VerifyOTPRequest) (models.VerifyOTPResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyOTPService", ctx, data)
+	ret0, _ := ret[0].(models.VerifyOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // VerifyOTPService indicates an expected call of VerifyOTPService.
-func (mr *MockUserServiceMockRecorder) VerifyOTPService(ctx, email, otp any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) VerifyOTPService(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyOTPService", reflect.TypeOf((*MockUserService)(nil).VerifyOTPService), ctx, data)
+}
+
+// VerifyTwoFactorLoginService mocks base method.
+func (m *MockUserService) VerifyTwoFactorLoginService(ctx context.Context, data models.TwoFactorLoginRequest) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactorLoginService", ctx, data)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTwoFactorLoginService indicates an expected call of VerifyTwoFactorLoginService.
+func (mr *MockUserServiceMockRecorder) VerifyTwoFactorLoginService(ctx, data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyOTPService", reflect.TypeOf((*MockUserService)(nil).VerifyOTPService), ctx, email, otp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactorLoginService", reflect.TypeOf((*MockUserService)(nil).VerifyTwoFactorLoginService), ctx, data)
 }
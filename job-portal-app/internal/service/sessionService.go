@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// startSession creates a new session for the user and returns its first token pair.
+func (s *Service) startSession(ctx context.Context, user models.User) (models.TokenPair, error) {
+	sessionID := uuid.NewString()
+
+	accessToken, err := s.newAccessToken(user, sessionID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	refreshToken, record, err := newRefreshToken(sessionID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	err = s.UserRepo.InsertSession(ctx, models.Session{ID: sessionID, UserID: user.ID}, record)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	return models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *Service) newAccessToken(user models.User, sessionID string) (string, error) {
+	role := user.Role
+	if role == "" {
+		role = models.RoleCandidate
+	}
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "job portal project",
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Audience:  jwt.ClaimStrings{auth.AudienceUsers},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Role:          role,
+		SessionID:     sessionID,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		Locale:        user.Locale,
+	}
+
+	token, err := s.auth.GenerateAuthToken(claims)
+	if err != nil {
+		log.Info().Err(err).Msg("Failed to generate authentication token")
+		return "", fmt.Errorf("failed to generate authentication token: %w", err)
+	}
+	return token, nil
+}
+
+// newRefreshToken returns a random refresh token and the record to store for it.
+func newRefreshToken(sessionID string) (string, models.RefreshToken, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", models.RefreshToken{}, err
+	}
+	return token, models.RefreshToken{
+		SessionID: sessionID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}, nil
+}
+
+// RefreshTokenService exchanges a refresh token for a new token pair. The presented token is
+// used up; presenting it a second time revokes the session, because it means the token was
+// copied.
+func (s *Service) RefreshTokenService(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	token, session, err := s.UserRepo.FetchRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return models.TokenPair{}, errInvalidRefreshToken
+	}
+	if session.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return models.TokenPair{}, errInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		s.revokeReusedSession(ctx, session)
+		return models.TokenPair{}, errInvalidRefreshToken
+	}
+
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(session.UserID))
+	if err != nil {
+		return models.TokenPair{}, errInvalidRefreshToken
+	}
+
+	nextToken, record, err := newRefreshToken(session.ID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	err = s.UserRepo.RotateRefreshToken(ctx, token.ID, record)
+	if err != nil {
+		// Another request used the token first.
+		s.revokeReusedSession(ctx, session)
+		return models.TokenPair{}, errInvalidRefreshToken
+	}
+
+	accessToken, err := s.newAccessToken(user, session.ID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	return models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: nextToken,
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *Service) revokeReusedSession(ctx context.Context, session models.Session) {
+	log.Warn().Str("session id", session.ID).Uint("user id", session.UserID).Msg("refresh token reuse detected, revoking session")
+	err := s.UserRepo.RevokeSession(ctx, session.ID)
+	if err != nil {
+		log.Error().Err(err).Str("session id", session.ID).Msg("failed to revoke session")
+	}
+}
+
+// LogoutService revokes the access token the request was made with and the session it
+// belongs to.
+func (s *Service) LogoutService(ctx context.Context, claims auth.Claims) error {
+	if claims.ID != "" {
+		expiresAt := time.Now().Add(accessTokenTTL)
+		if claims.ExpiresAt != nil {
+			expiresAt = claims.ExpiresAt.Time
+		}
+		err := s.UserRepo.RevokeToken(ctx, claims.ID, expiresAt)
+		if err != nil {
+			return err
+		}
+	}
+	if claims.SessionID != "" {
+		return s.UserRepo.RevokeSession(ctx, claims.SessionID)
+	}
+	return nil
+}
+
+// LogoutAllService revokes every session of the user.
+func (s *Service) LogoutAllService(ctx context.Context, uid uint) error {
+	err := s.UserRepo.RevokeUserSessions(ctx, uid, "")
+	if err != nil {
+		return err
+	}
+	log.Info().Uint("user id", uid).Msg("all sessions revoked")
+	return nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generating random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex SHA-256 of a high-entropy token. Such tokens do not need a slow
+// password hash, and a fast one lets us look them up directly.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestService_RefreshTokenService(t *testing.T) {
+	session := models.Session{ID: "sid-1", UserID: 4}
+	fresh := models.RefreshToken{ID: 10, SessionID: "sid-1", ExpiresAt: time.Now().Add(time.Hour)}
+	used := fresh
+	usedAt := time.Now().Add(-time.Minute)
+	used.UsedAt = &usedAt
+	revokedSession := session
+	revokedSession.RevokedAt = &usedAt
+
+	tests := []struct {
+		name      string
+		setup     func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication)
+		wantToken string
+		wantErr   bool
+	}{
+		{
+			name: "unknown token",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().FetchRefreshToken(gomock.Any(), hashToken("refresh")).Return(models.RefreshToken{}, models.Session{}, errors.New("invalid refresh token")).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "revoked session",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().FetchRefreshToken(gomock.Any(), hashToken("refresh")).Return(fresh, revokedSession, nil).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "reused token revokes the session",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().FetchRefreshToken(gomock.Any(), hashToken("refresh")).Return(used, session, nil).Times(1)
+				mockRepo.EXPECT().RevokeSession(gomock.Any(), "sid-1").Return(nil).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "concurrent use loses the race",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().FetchRefreshToken(gomock.Any(), hashToken("refresh")).Return(fresh, session, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(4)).Return(models.User{Model: gorm.Model{ID: 4}}, nil).Times(1)
+				mockRepo.EXPECT().RotateRefreshToken(gomock.Any(), uint(10), gomock.Any()).Return(errors.New("failed to rotate refresh token")).Times(1)
+				mockRepo.EXPECT().RevokeSession(gomock.Any(), "sid-1").Return(nil).Times(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success rotates the token",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockRepo.EXPECT().FetchRefreshToken(gomock.Any(), hashToken("refresh")).Return(fresh, session, nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(4)).Return(models.User{Model: gorm.Model{ID: 4}, Role: models.RoleRecruiter}, nil).Times(1)
+				mockRepo.EXPECT().RotateRefreshToken(gomock.Any(), uint(10), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ uint, next models.RefreshToken) error {
+						if next.SessionID != "sid-1" || next.TokenHash == hashToken("refresh") {
+							t.Errorf("RotateRefreshToken() got unexpected successor %+v", next)
+						}
+						return nil
+					}).Times(1)
+				mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).DoAndReturn(func(c auth.Claims) (string, error) {
+					if c.Subject != "4" || c.Role != models.RoleRecruiter || c.SessionID != "sid-1" || c.ID == "" {
+						t.Errorf("GenerateAuthToken() got unexpected claims %+v", c)
+					}
+					return "access", nil
+				}).Times(1)
+			},
+			wantToken: "access",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockAuth := auth.NewMockAuthentication(mc)
+			tt.setup(mockRepo, mockAuth)
+			s, _ := NewService(mockRepo, mockAuth, nil, config.Config{})
+			got, err := s.RefreshTokenService(context.Background(), "refresh")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefreshTokenService() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.AccessToken != tt.wantToken {
+				t.Errorf("RefreshTokenService() access token = %v, want %v", got.AccessToken, tt.wantToken)
+			}
+			if !tt.wantErr && (got.RefreshToken == "" || got.RefreshToken == "refresh") {
+				t.Errorf("RefreshTokenService() refresh token was not rotated")
+			}
+		})
+	}
+}
+
+func TestService_LogoutService(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().RevokeToken(gomock.Any(), "jti-1", gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().RevokeSession(gomock.Any(), "sid-1").Return(nil).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	claims := auth.Claims{SessionID: "sid-1"}
+	claims.ID = "jti-1"
+	err := s.LogoutService(context.Background(), claims)
+	if err != nil {
+		t.Errorf("LogoutService() error = %v", err)
+	}
+}
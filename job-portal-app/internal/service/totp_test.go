@@ -0,0 +1,56 @@
+package service
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+func Test_totpCode(t *testing.T) {
+	// SHA1 test vectors from RFC 6238 appendix B, truncated to 6 digits
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tt := range tests {
+		got, err := totpCode(secret, tt.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("totpCode() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("totpCode(%d) = %v, want %v", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func Test_validateTOTP(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generateTOTPSecret() error = %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	step := now.Unix() / totpPeriod
+	previous, _ := totpCode(secret, step-1)
+	current, _ := totpCode(secret, step)
+	tooOld, _ := totpCode(secret, step-2)
+
+	if got, ok := validateTOTP(secret, current, now, 0); !ok || got != step {
+		t.Errorf("validateTOTP() current code = %v %v, want %v true", got, ok, step)
+	}
+	if _, ok := validateTOTP(secret, previous, now, 0); !ok {
+		t.Errorf("validateTOTP() should accept the previous step")
+	}
+	if _, ok := validateTOTP(secret, tooOld, now, 0); ok {
+		t.Errorf("validateTOTP() should refuse codes outside the skew")
+	}
+	if _, ok := validateTOTP(secret, current, now, step); ok {
+		t.Errorf("validateTOTP() should refuse a replayed code")
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+)
+
+const (
+	challengeTokenTTL = 5 * time.Minute
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrInvalidPassword      = errors.New("invalid password")
+	ErrInvalidChallenge     = errors.New("invalid or expired login challenge, please log in again")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrNoPendingTOTP        = errors.New("start two-factor enrolment first")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+)
+
+var (
+	recoveryCodeEncoding     = base32.StdEncoding.WithPadding(base32.NoPadding)
+	recoveryCodeReplacements = strings.NewReplacer("-", "", " ", "")
+)
+
+// newChallengeToken issues the token returned by the password step of a 2FA login. Its
+// audience keeps it from being accepted as an access token.
+func (s *Service) newChallengeToken(user models.User) (models.TokenPair, error) {
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "job portal project",
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Audience:  jwt.ClaimStrings{auth.AudienceChallenge},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(challengeTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token, err := s.auth.GenerateAuthToken(claims)
+	if err != nil {
+		log.Info().Err(err).Msg("Failed to generate challenge token")
+		return models.TokenPair{}, errors.New("failed to generate challenge token")
+	}
+	return models.TokenPair{ChallengeToken: token}, nil
+}
+
+// VerifyTwoFactorLoginService completes a 2FA login. Each challenge token allows a single
+// attempt, so guessing codes needs the password every time.
+func (s *Service) VerifyTwoFactorLoginService(ctx context.Context, data models.TwoFactorLoginRequest) (models.TokenPair, error) {
+	claims, err := s.auth.ValidateChallengeToken(ctx, data.ChallengeToken)
+	if err != nil {
+		log.Info().Err(err).Msg("invalid challenge token")
+		return models.TokenPair{}, ErrInvalidChallenge
+	}
+	err = s.UserRepo.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return models.TokenPair{}, ErrInvalidChallenge
+	}
+	user, err := s.UserRepo.GetUserByID(ctx, uid)
+	if err != nil || !user.TwoFactorEnabled {
+		return models.TokenPair{}, ErrInvalidChallenge
+	}
+
+	ok, err := s.verifySecondFactor(ctx, user, data.Code)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	if !ok {
+		log.Warn().Uint("user id", user.ID).Msg("invalid two-factor code")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditTwoFactorFailed, TargetUserID: uintRef(user.ID)})
+		return models.TokenPair{}, ErrInvalidTwoFactorCode
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginSucceeded, TargetUserID: uintRef(user.ID), Details: map[string]string{"method": "password+2fa"}})
+	return s.startSession(ctx, user)
+}
+
+// verifySecondFactor accepts a TOTP code or an unused recovery code.
+func (s *Service) verifySecondFactor(ctx context.Context, user models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totpDigits {
+		step, ok := validateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+		if !ok {
+			return false, nil
+		}
+		return s.UserRepo.AdvanceTOTPStep(ctx, user.ID, step)
+	}
+	return s.UserRepo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// EnrolTOTPService starts (re-)enrolment. The new secret only replaces the current one once
+// ConfirmTOTPService has seen a valid code for it.
+func (s *Service) EnrolTOTPService(ctx context.Context, uid uint, password string) (models.TOTPEnrolment, error) {
+	user, err := s.checkPassword(ctx, uid, password)
+	if err != nil {
+		return models.TOTPEnrolment{}, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return models.TOTPEnrolment{}, err
+	}
+	err = s.UserRepo.SaveTOTPPendingSecret(ctx, uid, secret)
+	if err != nil {
+		return models.TOTPEnrolment{}, err
+	}
+	return models.TOTPEnrolment{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTPService enables 2FA with the pending secret and returns a fresh set of recovery
+// codes. This is the only time the codes are shown.
+func (s *Service) ConfirmTOTPService(ctx context.Context, uid uint, code string) (models.RecoveryCodes, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(uid))
+	if err != nil {
+		return models.RecoveryCodes{}, err
+	}
+	if user.TOTPPendingSecret == "" {
+		return models.RecoveryCodes{}, ErrNoPendingTOTP
+	}
+	step, ok := validateTOTP(user.TOTPPendingSecret, strings.TrimSpace(code), time.Now(), 0)
+	if !ok {
+		return models.RecoveryCodes{}, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	records := make([]models.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = generateRecoveryCode()
+		if err != nil {
+			return models.RecoveryCodes{}, err
+		}
+		records[i] = models.RecoveryCode{UserID: uid, CodeHash: hashToken(normalizeRecoveryCode(codes[i]))}
+	}
+
+	err = s.UserRepo.EnableTOTP(ctx, uid, step, records)
+	if err != nil {
+		return models.RecoveryCodes{}, err
+	}
+	return models.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTPService turns 2FA off and deletes the recovery codes.
+func (s *Service) DisableTOTPService(ctx context.Context, uid uint, password string) error {
+	user, err := s.checkPassword(ctx, uid, password)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled && user.TOTPPendingSecret == "" {
+		return ErrTwoFactorNotEnabled
+	}
+	return s.UserRepo.DisableTOTP(ctx, uid)
+}
+
+// checkPassword loads the user and checks their password with confirmPassword.
+func (s *Service) checkPassword(ctx context.Context, uid uint, password string) (models.User, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(uid))
+	if err != nil {
+		return models.User{}, err
+	}
+	err = confirmPassword(user, password)
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// generateRecoveryCode returns a code like "k3jf8-2mq9x" with 50 bits of entropy.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(recoveryCodeReplacements.Replace(code))
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestService_VerifyTwoFactorLoginService(t *testing.T) {
+	secret, _ := generateTOTPSecret()
+	code, _ := totpCode(secret, time.Now().Unix()/totpPeriod)
+	challenge := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "challenge-jti",
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}}
+	user := models.User{Email: "test@example.com", TwoFactorEnabled: true, TOTPSecret: secret}
+	user.ID = 1
+
+	tests := []struct {
+		name    string
+		code    string
+		setup   func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication)
+		wantErr error
+	}{
+		{
+			name: "invalid challenge token",
+			code: code,
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateChallengeToken(gomock.Any(), "challenge").Return(auth.Claims{}, errors.New("token is expired")).Times(1)
+			},
+			wantErr: ErrInvalidChallenge,
+		},
+		{
+			name: "wrong code",
+			code: "000000",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateChallengeToken(gomock.Any(), "challenge").Return(challenge, nil).Times(1)
+				mockRepo.EXPECT().RevokeToken(gomock.Any(), "challenge-jti", gomock.Any()).Return(nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+			},
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "used recovery code",
+			code: "abcde-fghij",
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateChallengeToken(gomock.Any(), "challenge").Return(challenge, nil).Times(1)
+				mockRepo.EXPECT().RevokeToken(gomock.Any(), "challenge-jti", gomock.Any()).Return(nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+				mockRepo.EXPECT().UseRecoveryCode(gomock.Any(), uint(1), hashToken("abcdefghij")).Return(false, nil).Times(1)
+			},
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "success",
+			code: code,
+			setup: func(mockRepo *repository.MockUserRepo, mockAuth *auth.MockAuthentication) {
+				mockAuth.EXPECT().ValidateChallengeToken(gomock.Any(), "challenge").Return(challenge, nil).Times(1)
+				mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).Return("access", nil).Times(1)
+				mockRepo.EXPECT().RevokeToken(gomock.Any(), "challenge-jti", gomock.Any()).Return(nil).Times(1)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).Times(1)
+				mockRepo.EXPECT().AdvanceTOTPStep(gomock.Any(), uint(1), gomock.Any()).Return(true, nil).Times(1)
+				mockRepo.EXPECT().InsertSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockAuth := auth.NewMockAuthentication(mc)
+			tt.setup(mockRepo, mockAuth)
+			s, _ := NewService(mockRepo, mockAuth, nil, config.Config{})
+			got, err := s.VerifyTwoFactorLoginService(context.Background(), models.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: tt.code})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyTwoFactorLoginService() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.AccessToken != "access" {
+				t.Errorf("VerifyTwoFactorLoginService() got = %v", got)
+			}
+		})
+	}
+}
+
+func TestService_UserLoginService_twoFactorChallenge(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockAuth := auth.NewMockAuthentication(mc)
+	mockRepo.EXPECT().VerifyUserCredentials(gomock.Any(), "test@example.com").Return(models.User{
+		PasswordHash:     "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y", // Valid hash for "validpassword"
+		TwoFactorEnabled: true,
+	}, nil).Times(1)
+	mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).DoAndReturn(func(claims auth.Claims) (string, error) {
+		if len(claims.Audience) != 1 || claims.Audience[0] != auth.AudienceChallenge {
+			t.Errorf("challenge token audience = %v", claims.Audience)
+		}
+		return "challenge", nil
+	}).Times(1)
+
+	s, _ := NewService(mockRepo, mockAuth, nil, config.Config{})
+	got, err := s.UserLoginService(context.Background(), models.NewUser{Email: "test@example.com", Password: "validpassword"})
+	if err != nil {
+		t.Fatalf("UserLoginService() error = %v", err)
+	}
+	if got != (models.TokenPair{ChallengeToken: "challenge"}) {
+		t.Errorf("UserLoginService() got = %v, want only a challenge token", got)
+	}
+}
+
+func TestService_ConfirmTOTPService(t *testing.T) {
+	secret, _ := generateTOTPSecret()
+	code, _ := totpCode(secret, time.Now().Unix()/totpPeriod)
+
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{TOTPPendingSecret: secret}, nil).Times(1)
+	mockRepo.EXPECT().EnableTOTP(gomock.Any(), uint(1), gomock.Any(), gomock.Len(recoveryCodeCount)).Return(nil).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+	got, err := s.ConfirmTOTPService(context.Background(), 1, code)
+	if err != nil {
+		t.Fatalf("ConfirmTOTPService() error = %v", err)
+	}
+	if len(got.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("ConfirmTOTPService() returned %d recovery codes, want %d", len(got.RecoveryCodes), recoveryCodeCount)
+	}
+}
+
+func TestService_EnrolTOTPService(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     models.User
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "wrong password",
+			user:     models.User{Email: "test@example.com", PasswordHash: "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y"},
+			password: "wrongpassword",
+			wantErr:  ErrInvalidPassword,
+		},
+		{
+			name:     "valid password",
+			user:     models.User{Email: "test@example.com", PasswordHash: "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y"},
+			password: "validpassword",
+		},
+		{
+			name: "account of an identity provider has no password",
+			user: models.User{Email: "test@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(tt.user, nil).Times(1)
+			if tt.wantErr == nil {
+				mockRepo.EXPECT().SaveTOTPPendingSecret(gomock.Any(), uint(1), gomock.Any()).Return(nil).Times(1)
+			}
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			got, err := s.EnrolTOTPService(context.Background(), 1, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EnrolTOTPService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.Secret == "" {
+				t.Errorf("EnrolTOTPService() returned no secret")
+			}
+		})
+	}
+}
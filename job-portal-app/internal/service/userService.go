@@ -2,183 +2,160 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 	"job-portal-api/internal/config"
+	"job-portal-api/internal/i18n"
+	"job-portal-api/internal/mail"
 	"job-portal-api/internal/models"
-	"math/rand"
-	"net/smtp"
-	"strconv"
-	"time"
+	"job-portal-api/internal/repository"
 )
 
-func (s *Service) UserLoginService(ctx context.Context, userData models.NewUser) (string, error) {
+func (s *Service) UserLoginService(ctx context.Context, userData models.NewUser) (models.TokenPair, error) {
 	// Checking the email in the db
+	email := normalizeEmail(userData.Email)
 	var userDetails models.User
-	userDetails, err := s.UserRepo.VerifyUserCredentials(ctx, userData.Email)
+	userDetails, err := s.UserRepo.VerifyUserCredentials(ctx, email)
 	if err != nil {
 		log.Info().Err(err).Msg("Failed to verify user credentials")
-		return "", fmt.Errorf("failed to verify user credentials: %w", err)
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginFailed, Details: map[string]string{"email": email, "reason": "unknown email"}})
+		return models.TokenPair{}, fmt.Errorf("failed to verify user credentials: %w", err)
 	}
 	err = bcrypt.CompareHashAndPassword([]byte(userDetails.PasswordHash), []byte(userData.Password))
 	if err != nil {
 		log.Info().Err(err).Msg("Invalid password provided")
-		return "", errors.New("invalid password provided")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginFailed, TargetUserID: uintRef(userDetails.ID), Details: map[string]string{"reason": "invalid password"}})
+		return models.TokenPair{}, errors.New("invalid password provided")
 	}
-	claims := jwt.RegisteredClaims{
-		Issuer:    "job portal project",
-		Subject:   strconv.FormatUint(uint64(userDetails.ID), 10),
-		Audience:  jwt.ClaimStrings{"users"},
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	if s.cfg.AuthConfig.EmailVerification == config.EmailVerificationLogin && userDetails.EmailVerifiedAt == nil {
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginFailed, TargetUserID: uintRef(userDetails.ID), Details: map[string]string{"reason": "email not verified"}})
+		return models.TokenPair{}, ErrEmailNotVerified
 	}
-
-	token, err := s.auth.GenerateAuthToken(claims)
-	if err != nil {
-		log.Info().Err(err).Msg("Failed to generate authentication token")
-		return "", fmt.Errorf("failed to generate authentication token: %w", err)
+	if userDetails.TwoFactorEnabled {
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditTwoFactorChallenged, TargetUserID: uintRef(userDetails.ID)})
+		return s.newChallengeToken(userDetails)
 	}
-	return token, nil
+
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditLoginSucceeded, TargetUserID: uintRef(userDetails.ID), Details: map[string]string{"method": "password"}})
+	return s.startSession(ctx, userDetails)
 }
 
 func (s *Service) RegisterUserService(ctx context.Context, userData models.NewUser) (models.User, error) {
+	// emails are stored lowercase, so every lookup finds the account however it is typed
+	email := normalizeEmail(userData.Email)
+	err := s.passwords.check("password", userData.Password, models.User{Username: userData.Username, Email: email})
+	if err != nil {
+		return models.User{}, err
+	}
 	hashedPass, err := bcrypt.GenerateFromPassword([]byte(userData.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return models.User{}, fmt.Errorf("generating password hash: %w", err)
 	}
 	userDetails := models.User{
 		Username:     userData.Username,
-		Email:        userData.Email,
+		Email:        email,
 		PasswordHash: string(hashedPass),
-	}
-	userDetails, err = s.UserRepo.InsertUser(ctx, userDetails)
+		Role:         models.RoleCandidate,
+	}
+	// the verification email is queued with the account, so one is never created without the other
+	err = s.UserRepo.Transaction(ctx, func(tx repository.UserRepo) error {
+		userDetails, err = tx.InsertUser(ctx, userDetails)
+		if err != nil {
+			return err
+		}
+		return s.queueVerificationEmail(ctx, tx, userDetails)
+	})
 	if err != nil {
 		return models.User{}, err
 	}
 	return userDetails, nil
 }
 
-func (s *Service) ForgetPasswordService(ctx context.Context, data models.ForgetPasswordRequest) (models.ForgetPasswordResponse, error) {
-	// Check if the user exists
-	_, err := s.UserRepo.GetUserByEmail(ctx, data.Email)
+// newEmail renders the template of the locale with data into an outbox message for a single
+// recipient.
+func newEmail(locale, to, template string, data any) (models.OutboxMessage, error) {
+	msg, err := mail.Render(locale, template, data)
 	if err != nil {
-		log.Printf("Error getting user by email: %v", err)
-		return models.ForgetPasswordResponse{}, errors.New("user not found")
+		return models.OutboxMessage{}, err
 	}
-
-	// Generate OTP
-	otp := generateOTP()
-
-	// Save OTP and expiration in Redis cache
-	err = s.rdb.SaveOTPInCache(data.Email, otp)
+	msg.To = []string{to}
+	payload, err := json.Marshal(msg)
 	if err != nil {
-		log.Printf("Error saving OTP in cache: %v", err)
-		return models.ForgetPasswordResponse{}, errors.New("failed to generate OTP")
-	}
-
-	// Send OTP via email
-	err = sendOTPEmail(data.Email, otp)
-	if err != nil {
-		log.Printf("Error sending OTP email: %v", err)
-		return models.ForgetPasswordResponse{}, errors.New("failed to send OTP via email")
-	}
-
-	return models.ForgetPasswordResponse{Message: "OTP sent to the registered email address"}, nil
+		return models.OutboxMessage{}, err
+	}
+	return models.OutboxMessage{
+		Kind:        models.OutboxKindEmail,
+		Destination: to,
+		Summary:     msg.Subject,
+		Payload:     payload,
+	}, nil
 }
 
-func generateOTP() string {
-	return strconv.Itoa(rand.Intn(999999))
+// emailLocale is the locale of the emails sent to user: the one they saved, or else the one
+// of the request that sends the email.
+func emailLocale(ctx context.Context, user models.User) string {
+	if locale, ok := i18n.Normalize(user.Locale); ok {
+		return locale
+	}
+	return i18n.FromContext(ctx)
 }
 
-func sendOTPEmail(email, otp string) error {
-	cfg := config.GetConfig()
-	from := "sandeepsinghs321@gmail.com"
-	password := "latc ymgz ksxv zuzc"
-
-	// Recipient's email address
-	to := email
-
-	// SMTP server details
-	smtpServer := "smtp.gmail.com"
-	smtpPort := cfg.MailConfig.Port
-
-	// Message content
-	message := []byte(fmt.Sprintf("Subject: Forget Password OTP\n\nYour OTP for forget password: %s", otp))
-
-	// Authentication information
-	auth := smtp.PlainAuth("", from, password, smtpServer)
-
-	// SMTP connection
-	smtpAddr := fmt.Sprintf("%s:%d", smtpServer, smtpPort)
-	err := smtp.SendMail(smtpAddr, auth, from, []string{to}, message)
+// queueEmail queues an email on repo, which is the transaction of the change the email is
+// about when there is one.
+func queueEmail(ctx context.Context, repo repository.UserRepo, locale, to, template string, data any) error {
+	msg, err := newEmail(locale, to, template, data)
 	if err != nil {
-		log.Printf("Error sending email: %v", err)
 		return err
 	}
-
-	log.Print("Email sent successfully!")
-	return nil
+	return repo.EnqueueOutbox(ctx, msg)
 }
 
-func (s *Service) VerifyOTPService(ctx context.Context, email string, otp string) error {
-	storedOTP, err := s.rdb.GetOTP(email)
-	if err != nil {
-		return errors.New("failed to retrieve OTP from the database")
+// ChangePasswordService changes the password of the logged in user. Every other session of
+// the user is revoked, the one making the change stays logged in.
+func (s *Service) ChangePasswordService(ctx context.Context, uid uint, sessionID string, data models.ChangePasswordRequest) error {
+	if data.NewPassword != data.ConfirmPassword {
+		return ErrPasswordMismatch
 	}
-
-	// Check if the OTP matches the stored OTP
-	if otp != storedOTP {
-		return errors.New("invalid OTP")
+	if data.NewPassword == data.OldPassword {
+		return &PasswordError{Field: "new_password", Problems: []string{"it must differ from the old password"}}
 	}
 
-	// Delete the OTP from the database (optional, depending on your use case)
-	err = s.rdb.DeleteOTP(email)
+	user, err := s.UserRepo.GetUserByID(ctx, uint64(uid))
 	if err != nil {
-		return errors.New("failed to delete OTP from the database")
+		log.Error().Err(err).Uint("user id", uid).Msg("failed to retrieve user")
+		return err
 	}
-
-	// OTP verification successful
-	return nil
-}
-
-func (s *Service) UpdatePasswordService(ctx context.Context, email, newPassword string) error {
-	// Hash the new password before updating
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	err = s.passwords.check("new_password", data.NewPassword, user)
 	if err != nil {
-		log.Printf("Error hashing the new password for email %s: %v", email, err)
-		return errors.New("failed to hash the new password")
+		return err
 	}
 
-	// Update the password in the database
-	err = s.UserRepo.UpdatePassword(ctx, email, string(hashedPassword))
+	// Compare the oldPass with the hashed password stored in the database
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(data.OldPassword))
 	if err != nil {
-		log.Printf("Error updating the password for email %s: %v", email, err)
-		return fmt.Errorf("failed to update the password: %w", err)
+		log.Warn().Uint("user id", uid).Msg("invalid old password")
+		s.audit(ctx, models.AuditEvent{EventType: models.AuditPasswordChangeFailed, TargetUserID: uintRef(uid), Details: map[string]string{"reason": "invalid old password"}})
+		return ErrInvalidOldPassword
 	}
 
-	log.Printf("Password updated successfully for email %s", email)
-	return nil
-}
-
-func (s *Service) VerifyOldPassword(ctx context.Context, email string, oldPass string) error {
-	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		log.Error().Err(err).Str("email", email).Msg("failed to retrieve user by email")
-		return err
+		return errors.New("failed to hash the new password")
+	}
+	err = s.UserRepo.UpdatePassword(ctx, user.Email, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to update the password: %w", err)
 	}
 
-	// Compare the oldPass with the hashed password stored in the database
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPass))
+	err = s.UserRepo.RevokeUserSessions(ctx, uid, sessionID)
 	if err != nil {
-		// Passwords do not match
-		log.Warn().Str("email", email).Msg("invalid old password")
-		return errors.New("invalid old password")
+		return fmt.Errorf("failed to revoke sessions: %w", err)
 	}
 
-	// Old password is valid
-	log.Info().Str("email", email).Msg("old password verification successful")
+	log.Info().Uint("user id", uid).Msg("password changed successfully")
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditPasswordChanged, TargetUserID: uintRef(uid)})
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"go.uber.org/mock/gomock"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/repository"
 	"reflect"
@@ -65,6 +66,32 @@ func TestService_RegisterUserService(t *testing.T) {
 				return models.User{}, errors.New("db error")
 			},
 		},
+		{
+			name: "password contains the username",
+			args: args{
+				ctx: context.Background(),
+				userData: models.NewUser{
+					Username: "testuser",
+					Email:    "test@example.com",
+					Password: "testuser-rocks",
+				},
+			},
+			want:    models.User{},
+			wantErr: true,
+		},
+		{
+			name: "breached password",
+			args: args{
+				ctx: context.Background(),
+				userData: models.NewUser{
+					Username: "testuser",
+					Email:    "test@example.com",
+					Password: "Password123",
+				},
+			},
+			want:    models.User{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,9 +99,12 @@ func TestService_RegisterUserService(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
 			if tt.mockRepoResponse != nil {
+				mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+					return fn(mockRepo)
+				}).Times(1)
 				mockRepo.EXPECT().InsertUser(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(mockRepo, &auth.Auth{})
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
 			got, err := s.RegisterUserService(tt.args.ctx, tt.args.userData)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RegisterUserService() error = %v, wantErr %v", err, tt.wantErr)
@@ -87,6 +117,47 @@ func TestService_RegisterUserService(t *testing.T) {
 	}
 }
 
+func TestService_RegisterUserService_verificationEmail(t *testing.T) {
+	cfg := config.Config{AuthConfig: config.AuthConfig{
+		EmailVerification:       config.EmailVerificationLogin,
+		EmailVerificationSecret: "secret",
+	}}
+	tests := []struct {
+		name       string
+		enqueueErr error
+		wantErr    bool
+	}{
+		{name: "email queued with the account"},
+		{name: "account rolled back when the email cannot be queued", enqueueErr: errors.New("failed to queue the message"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tx := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+				return fn(tx)
+			}).Times(1)
+			tx.EXPECT().InsertUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user models.User) (models.User, error) {
+				user.ID = 3
+				return user, nil
+			}).Times(1)
+			tx.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, messages ...models.OutboxMessage) error {
+				if len(messages) != 1 || messages[0].Destination != "new@example.com" || messages[0].Summary != "Verify your email address" {
+					t.Errorf("EnqueueOutbox() got %+v", messages)
+				}
+				return tt.enqueueErr
+			}).Times(1)
+
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, cfg)
+			_, err := s.RegisterUserService(context.Background(), models.NewUser{Username: "newbie", Email: "new@example.com", Password: "c0rrect-h0rse"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterUserService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestService_UserLoginService(t *testing.T) {
 	type fields struct {
 		UserRepo repository.UserRepo
@@ -182,6 +253,7 @@ func TestService_UserLoginService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			mockAuth := auth.NewMockAuthentication(mc)
 
 			if tt.mockRepoResponse != nil {
@@ -191,16 +263,78 @@ func TestService_UserLoginService(t *testing.T) {
 			if tt.mockAuthToken != nil {
 				mockAuth.EXPECT().GenerateAuthToken(gomock.Any()).Return(tt.mockAuthToken()).AnyTimes()
 			}
+			mockRepo.EXPECT().InsertSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
-			s, _ := NewService(mockRepo, mockAuth)
+			s, _ := NewService(mockRepo, mockAuth, nil, config.Config{})
 			gotToken, err := s.UserLoginService(tt.args.ctx, tt.args.userData)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UserLoginService() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if gotToken != tt.wantToken {
+			if gotToken.AccessToken != tt.wantToken {
 				t.Errorf("UserLoginService() gotToken = %v, wantToken %v", gotToken, tt.wantToken)
 			}
 		})
 	}
 }
+
+func TestService_ChangePasswordService(t *testing.T) {
+	const validHash = "$2a$10$xQmztwxwwg2trzNLHpuSq.crH8PojzsVG7Jh4lN96i9tgYrvodV5y" // Valid hash for "validpassword"
+	tests := []struct {
+		name    string
+		data    models.ChangePasswordRequest
+		setup   func(mockRepo *repository.MockUserRepo)
+		wantErr error
+	}{
+		{
+			name:    "confirmation does not match",
+			data:    models.ChangePasswordRequest{OldPassword: "validpassword", NewPassword: "newpassword1", ConfirmPassword: "newpassword2"},
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrPasswordMismatch,
+		},
+		{
+			name: "new password too short",
+			data: models.ChangePasswordRequest{OldPassword: "validpassword", NewPassword: "short", ConfirmPassword: "short"},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com", PasswordHash: validHash}, nil).Times(1)
+			},
+			wantErr: ErrWeakPassword,
+		},
+		{
+			name:    "new password same as the old one",
+			data:    models.ChangePasswordRequest{OldPassword: "validpassword", NewPassword: "validpassword", ConfirmPassword: "validpassword"},
+			setup:   func(mockRepo *repository.MockUserRepo) {},
+			wantErr: ErrWeakPassword,
+		},
+		{
+			name: "invalid old password",
+			data: models.ChangePasswordRequest{OldPassword: "wrongpassword", NewPassword: "newpassword", ConfirmPassword: "newpassword"},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com", PasswordHash: validHash}, nil).Times(1)
+			},
+			wantErr: ErrInvalidOldPassword,
+		},
+		{
+			name: "success",
+			data: models.ChangePasswordRequest{OldPassword: "validpassword", NewPassword: "newpassword", ConfirmPassword: "newpassword"},
+			setup: func(mockRepo *repository.MockUserRepo) {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(models.User{Email: "test@example.com", PasswordHash: validHash}, nil).Times(1)
+				mockRepo.EXPECT().UpdatePassword(gomock.Any(), "test@example.com", gomock.Any()).Return(nil).Times(1)
+				mockRepo.EXPECT().RevokeUserSessions(gomock.Any(), uint(1), "current-session").Return(nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().InsertAuditEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{}, nil, config.Config{})
+			err := s.ChangePasswordService(context.Background(), 1, "current-session", tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChangePasswordService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+)
+
+const maxWebhookDeliveries = 200
+
+var (
+	ErrWebhookNotFound   = errors.New("webhook endpoint not found")
+	ErrInvalidWebhookURL = errors.New("the webhook url must be an absolute http or https url")
+)
+
+// CreateWebhookEndpointService registers an endpoint of the company. Like API keys, endpoints
+// are managed by the company's owners. The response is the only place the secret is shown.
+func (s *Service) CreateWebhookEndpointService(ctx context.Context, actor models.Actor, cid uint64, data models.NewWebhookEndpointRequest) (models.NewWebhookEndpointResponse, error) {
+	err := s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+	if err != nil {
+		return models.NewWebhookEndpointResponse{}, err
+	}
+	target, err := url.Parse(data.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || target.User != nil {
+		return models.NewWebhookEndpointResponse{}, ErrInvalidWebhookURL
+	}
+	var events []string
+	for _, event := range data.Events {
+		if !models.ValidWebhookEvent(event) {
+			return models.NewWebhookEndpointResponse{}, fmt.Errorf("unknown event %q", event)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return models.NewWebhookEndpointResponse{}, err
+	}
+	endpoint := models.WebhookEndpoint{
+		CompanyID: uint(cid),
+		URL:       target.String(),
+		Secret:    models.WebhookSecretPrefix + secret,
+		Events:    events,
+		CreatedBy: actor.UserID,
+	}
+	endpoint, err = s.UserRepo.InsertWebhookEndpoint(ctx, endpoint)
+	if err != nil {
+		return models.NewWebhookEndpointResponse{}, err
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditWebhookCreated, Details: map[string]string{
+		"webhook_id": fmt.Sprint(endpoint.ID),
+		"company_id": fmt.Sprint(cid),
+		"url":        endpoint.URL,
+		"events":     strings.Join(endpoint.Events, " "),
+	}})
+	return models.NewWebhookEndpointResponse{WebhookEndpoint: endpoint, Secret: endpoint.Secret}, nil
+}
+
+func (s *Service) ListWebhookEndpointsService(ctx context.Context, actor models.Actor, cid uint64) ([]models.WebhookEndpoint, error) {
+	err := s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.FetchWebhookEndpoints(ctx, uint(cid))
+}
+
+// DeleteWebhookEndpointService removes an endpoint. Deliveries still queued for it are dropped
+// by the dispatcher.
+func (s *Service) DeleteWebhookEndpointService(ctx context.Context, actor models.Actor, cid uint64, id uint) error {
+	err := s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.UserRepo.DeleteWebhookEndpoint(ctx, uint(cid), id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	s.audit(ctx, models.AuditEvent{EventType: models.AuditWebhookDeleted, Details: map[string]string{
+		"webhook_id": fmt.Sprint(id),
+		"company_id": fmt.Sprint(cid),
+	}})
+	return nil
+}
+
+// ListWebhookDeliveriesService returns the latest delivery attempts to an endpoint.
+func (s *Service) ListWebhookDeliveriesService(ctx context.Context, actor models.Actor, cid uint64, id uint, limit int) ([]models.WebhookDelivery, error) {
+	endpoint, err := s.companyWebhookEndpoint(ctx, actor, cid, id)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = maxWebhookDeliveries
+	}
+	return s.UserRepo.FetchWebhookDeliveries(ctx, endpoint.ID, limit)
+}
+
+// SendTestWebhookService queues a webhook.test event to the endpoint, whatever it subscribed
+// to, so the receiver and its signature check can be tried out.
+func (s *Service) SendTestWebhookService(ctx context.Context, actor models.Actor, cid uint64, id uint) error {
+	endpoint, err := s.companyWebhookEndpoint(ctx, actor, cid, id)
+	if err != nil {
+		return err
+	}
+	event, err := newWebhookEvent(models.WebhookEventTest, map[string]string{"message": "this is a test event"})
+	if err != nil {
+		return err
+	}
+	msg, err := newWebhookMessage(endpoint, event)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.EnqueueOutbox(ctx, msg)
+}
+
+func (s *Service) companyWebhookEndpoint(ctx context.Context, actor models.Actor, cid uint64, id uint) (models.WebhookEndpoint, error) {
+	err := s.authorizeCompany(ctx, actor, cid, models.MemberRoleOwner)
+	if err != nil {
+		return models.WebhookEndpoint{}, err
+	}
+	endpoint, err := s.UserRepo.FetchWebhookEndpoint(ctx, id)
+	if err != nil {
+		return models.WebhookEndpoint{}, err
+	}
+	if endpoint.ID == 0 || endpoint.CompanyID != uint(cid) {
+		return models.WebhookEndpoint{}, ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+// publishWebhookEvent queues the event for every endpoint of the company subscribed to it.
+// repo is the transaction of the change the event is about when there is one.
+func publishWebhookEvent(ctx context.Context, repo repository.UserRepo, cid uint, eventType string, data any) error {
+	endpoints, err := repo.FetchWebhookEndpoints(ctx, cid)
+	if err != nil {
+		return err
+	}
+	return queueWebhookEvent(ctx, repo, endpoints, eventType, data)
+}
+
+// queueWebhookEvent queues the event for those of endpoints that subscribed to it.
+func queueWebhookEvent(ctx context.Context, repo repository.UserRepo, endpoints []models.WebhookEndpoint, eventType string, data any) error {
+	var err error
+	var messages []models.OutboxMessage
+	var event models.WebhookEvent
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribed(eventType) {
+			continue
+		}
+		// every endpoint gets the same event id, receivers can use it to drop duplicates
+		if event.ID == "" {
+			event, err = newWebhookEvent(eventType, data)
+			if err != nil {
+				return err
+			}
+		}
+		msg, err := newWebhookMessage(endpoint, event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, msg)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return repo.EnqueueOutbox(ctx, messages...)
+}
+
+func newWebhookEvent(eventType string, data any) (models.WebhookEvent, error) {
+	id, err := randomToken(16)
+	if err != nil {
+		return models.WebhookEvent{}, err
+	}
+	return models.WebhookEvent{
+		ID:        "evt_" + id,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}, nil
+}
+
+// newWebhookMessage builds the outbox message that delivers event to the endpoint. The url
+// and secret are looked up again when it is sent, so the message does not hold the secret.
+func newWebhookMessage(endpoint models.WebhookEndpoint, event models.WebhookEvent) (models.OutboxMessage, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	payload, err := json.Marshal(OutboxWebhook{EndpointID: endpoint.ID, Event: event.Type, Body: body})
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return models.OutboxMessage{
+		Kind:        models.OutboxKindWebhook,
+		Destination: endpoint.URL,
+		Summary:     event.Type,
+		Payload:     payload,
+	}, nil
+}